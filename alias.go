@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+// gitAliasKey is the git alias install-alias falls back to when it can't put git-profile on PATH
+const gitAliasKey = "alias.profile"
+
+// invokedViaGit reports whether git started this process, as it does for 'git profile' and hooks
+func invokedViaGit() bool {
+	return os.Getenv("GIT_EXEC_PATH") != ""
+}
+
+// currentExecutable returns the path of the running binary with symlinks resolved
+func currentExecutable() (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(executable)
+}
+
+// sameFile reports whether two paths name the same file
+func sameFile(a string, b string) bool {
+	aInfo, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bInfo, err := os.Stat(b)
+	return err == nil && os.SameFile(aInfo, bInfo)
+}
+
+// gitProfileReachable reports whether 'git profile' runs anything: a git-profile binary on PATH or the git alias
+func gitProfileReachable() bool {
+	if _, err := exec.LookPath("git-profile"); err == nil {
+		return true
+	}
+	alias, _ := gitConfigGet("", gitAliasKey)
+	return alias != ""
+}
+
+// linkDir picks the directory to link git-profile into: a per-user bin directory that is already on PATH
+func linkDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	var onPath []string
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		onPath = append(onPath, filepath.Clean(dir))
+	}
+	candidates := []string{filepath.Join(homeDir, ".local", "bin"), filepath.Join(homeDir, "bin"), filepath.Join(homeDir, "go", "bin")}
+	for _, dir := range candidates {
+		if slices.Contains(onPath, dir) {
+			return dir, nil
+		}
+	}
+	return "", errorf("none of %s is on PATH", displayPath(candidates[0]))
+}
+
+// installAlias makes 'git profile' run executable: by linking it as git-profile into dir (picked by linkDir when empty),
+// or with a global git alias when useAlias is set or no directory was given and linking fails
+func installAlias(executable string, dir string, useAlias bool) (string, error) {
+	if !useAlias {
+		var err error
+		chosen := dir
+		if chosen == "" {
+			chosen, err = linkDir()
+		}
+		if err == nil {
+			link := filepath.Join(chosen, "git-profile"+filepath.Ext(executable))
+			if err = os.MkdirAll(chosen, 0755); err == nil {
+				err = os.Symlink(executable, link)
+			}
+			if err == nil {
+				return fmt.Sprintf(tr("Linked %s to %s"), displayPath(link), displayPath(executable)), nil
+			}
+		}
+		if dir != "" {
+			return "", err
+		}
+		infof("can't link git-profile into PATH (%v); setting up a git alias instead", err)
+	}
+
+	previous, _ := gitConfigGet("", gitAliasKey)
+	if _, err := runGit("", "config", "--global", gitAliasKey, "!"+shellQuote(executable)); err != nil {
+		return "", err
+	}
+	if previous != "" {
+		return fmt.Sprintf(tr("Replaced the git alias 'profile' (was %s) to run %s"), previous, displayPath(executable)), nil
+	}
+	return fmt.Sprintf(tr("Added the git alias 'profile' running %s"), displayPath(executable)), nil
+}
+
+// newInstallAliasCmd builds the install-alias command
+func newInstallAliasCmd(configManager *ConfigManager) *cobra.Command {
+	var dir string
+	var useAlias bool
+
+	var installAliasCmd = &cobra.Command{
+		Use:   "install-alias",
+		Short: "Make 'git profile' run this binary, by linking it into PATH or with a git alias",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			executable, err := currentExecutable()
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Installing the alias failed:"), err)
+				os.Exit(1)
+			}
+
+			// Git prefers a git-profile on PATH over an alias, so a different one there would shadow either fix
+			if path, err := exec.LookPath("git-profile"); err == nil {
+				if sameFile(path, executable) {
+					fmt.Fprintf(notices, tr("✅ 'git profile' already runs this binary (%s).\n"), displayPath(path))
+					return
+				}
+				fmt.Fprintf(stdout, tr("Installing the alias failed: 'git profile' runs %s, not this binary (%s); remove it or put %s first on PATH\n"),
+					displayPath(path), displayPath(executable), displayPath(filepath.Dir(executable)))
+				os.Exit(1)
+			}
+
+			result, err := installAlias(executable, expandHome(dir), useAlias)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Installing the alias failed:"), err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(notices, tr("✅ %s; 'git profile' now works.\n"), result)
+		},
+	}
+
+	installAliasCmd.Flags().StringVar(&dir, "dir", "", "Link git-profile into this directory (default: ~/.local/bin, ~/bin or ~/go/bin, whichever is on PATH)")
+	installAliasCmd.Flags().BoolVar(&useAlias, "git-alias", false, "Set up the global git alias 'profile' instead of linking")
+	return installAliasCmd
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInstallAlias tests linking the binary into PATH and falling back to a git alias
+func TestInstallAlias(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "git-profile-home")
+	assert.NoError(t, err)
+	defer os.RemoveAll(homeDir)
+	t.Setenv("HOME", homeDir)
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(homeDir, ".gitconfig"))
+
+	executable := filepath.Join(homeDir, "downloads", "git-profile_linux_amd64")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(executable), 0755))
+	assert.NoError(t, os.WriteFile(executable, []byte("#!/bin/sh\n"), 0755))
+	originalPath := os.Getenv("PATH")
+
+	// ~/.local/bin is used once it is on PATH
+	binDir := filepath.Join(homeDir, ".local", "bin")
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+originalPath)
+	dir, err := linkDir()
+	assert.NoError(t, err)
+	assert.Equal(t, binDir, dir)
+
+	result, err := installAlias(executable, "", false)
+	assert.NoError(t, err)
+	assert.Contains(t, result, "Linked")
+	assert.True(t, sameFile(filepath.Join(binDir, "git-profile"), executable))
+	assert.True(t, gitProfileReachable())
+
+	// Without a suitable directory on PATH, a git alias takes over
+	t.Setenv("PATH", originalPath)
+	_, err = linkDir()
+	assert.Error(t, err)
+	result, err = installAlias(executable, "", false)
+	assert.NoError(t, err)
+	assert.Contains(t, result, "Added the git alias")
+	alias, err := gitConfigGet("", gitAliasKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "!'"+executable+"'", alias)
+
+	result, err = installAlias(executable, "", true)
+	assert.NoError(t, err)
+	assert.Contains(t, result, "Replaced the git alias")
+
+	// An explicit directory that can't be used is an error, not a fallback
+	blocked := filepath.Join(homeDir, "blocked")
+	assert.NoError(t, os.WriteFile(blocked, nil, 0644))
+	_, err = installAlias(executable, blocked, false)
+	assert.Error(t, err)
+}
+
+// TestInvokedViaGit tests detecting that git started the process
+func TestInvokedViaGit(t *testing.T) {
+	t.Setenv("GIT_EXEC_PATH", "")
+	assert.False(t, invokedViaGit())
+	t.Setenv("GIT_EXEC_PATH", "/usr/lib/git-core")
+	assert.True(t, invokedViaGit())
+}
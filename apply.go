@@ -0,0 +1,335 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+)
+
+// applyOptions holds the flags of the apply command
+type applyOptions struct {
+	Tag        string
+	Registered bool
+	Recursive  string
+	Yes        bool
+	Strict     bool
+	NoGH       bool
+	Rewrite    bool
+	DryRun     bool
+	Worktree   bool
+	Submodules bool
+	Global     bool
+	Local      bool
+	AllScopes  bool
+	FromEnv    bool
+}
+
+// selectProfileToApply prompts for a profile, most recently used first with the remote's suggestion preselected
+func (cm *ConfigManager) selectProfileToApply(tag string) (string, bool) {
+	var profileNames []string
+	for _, name := range cm.visibleProfiles() {
+		if tag == "" || cm.Profiles[name].HasTag(tag) {
+			profileNames = append(profileNames, name)
+		}
+	}
+
+	if len(profileNames) == 0 {
+		fmt.Fprintln(stdout, tr("No matching profiles found."))
+		return "", false
+	}
+	cm.sortByRecentUse(profileNames)
+
+	label, cursor := tr("Select profile to apply"), 0
+
+	// Propose the profile matching the repository's remote
+	if suggested, found := cm.suggestProfileForRepo("."); found {
+		label = fmt.Sprintf(tr("Select profile to apply (suggested: %s)"), suggested)
+		for i, name := range profileNames {
+			if name == suggested {
+				cursor = i
+			}
+		}
+	}
+
+	selected, err := cm.selectProfile(label, profileNames, cursor)
+	if err != nil {
+		fmt.Fprintln(notices, tr("Cancelled."))
+		return "", false
+	}
+	return selected, true
+}
+
+// newApplyCmd builds the apply command
+func newApplyCmd(configManager *ConfigManager) *cobra.Command {
+	var options applyOptions
+
+	var applyCmd = &cobra.Command{
+		Use:   "apply [profile]",
+		Short: "Apply a specific Git profile (interactive)",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var selectedProfile string
+			var selected Profile
+			if options.FromEnv {
+				if len(args) > 0 || options.Registered || options.Recursive != "" {
+					fmt.Fprintln(stdout, tr("Error applying profile: --from-env takes the profile from the environment and only applies to the current repository"))
+					os.Exit(1)
+				}
+				name, profile, err := configManager.profileFromEnv()
+				if err != nil {
+					fmt.Fprintln(stdout, tr("Error applying profile:"), err)
+					os.Exit(1)
+				}
+				selectedProfile, selected = name, profile
+			} else if len(args) > 0 {
+				selectedProfile = args[0]
+				if _, exists := configManager.Profiles[selectedProfile]; !exists {
+					fmt.Fprintf(stdout, tr("Profile '%s' not found.\n"), selectedProfile)
+					os.Exit(1)
+				}
+				if err := configManager.checkNotArchived(selectedProfile); err != nil {
+					fmt.Fprintln(stdout, tr("Error applying profile:"), err)
+					os.Exit(1)
+				}
+			} else if options.Registered || options.Recursive != "" {
+				fmt.Fprintln(stdout, tr("Specify the profile to apply to multiple repositories."))
+				os.Exit(1)
+			} else {
+				if err := requireTerminal(tr("pass the profile: git profile apply <profile>")); err != nil {
+					fmt.Fprintln(stdout, tr("Error applying profile:"), err)
+					os.Exit(1)
+				}
+				name, ok := configManager.selectProfileToApply(options.Tag)
+				if !ok {
+					return
+				}
+				selectedProfile = name
+			}
+			if !options.FromEnv {
+				selected = configManager.Profiles[selectedProfile]
+			}
+
+			// --worktree writes config.worktree, so other worktrees of the clone keep the shared local identity
+			if options.Worktree {
+				if options.Registered || options.Recursive != "" {
+					fmt.Fprintln(stdout, tr("Error applying profile: --worktree only applies to the current worktree"))
+					os.Exit(1)
+				}
+				if err := checkWorktreeConfig("."); err != nil {
+					fmt.Fprintln(stdout, tr("Error applying profile:"), err)
+					os.Exit(1)
+				}
+			}
+			if (options.Global || options.AllScopes) && (options.Registered || options.Recursive != "") {
+				fmt.Fprintln(stdout, tr("Error applying profile: --global and --all-scopes only apply to the current repository"))
+				os.Exit(1)
+			}
+			targets := applyTargets(options)
+			if options.Submodules && (options.Registered || options.Recursive != "") {
+				fmt.Fprintln(stdout, tr("Error applying profile: --recurse-submodules only applies to the current repository"))
+				os.Exit(1)
+			}
+
+			profile, err := resolveProfile(selected)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Error applying profile:"), err)
+				os.Exit(1)
+			}
+			if err := verifySigningKey(profile); err != nil {
+				if options.Strict {
+					fmt.Fprintf(stdout, tr("Error applying profile: %v\n"), err)
+					os.Exit(1)
+				}
+				warnf("%v", err)
+			} else if expiry, soon := configManager.signingKeyExpiry(profile); soon {
+				warnf("signing key %s %s; extend it with 'gpg --quick-set-expire %s 1y'", profile.Signing.Key, expiry, profile.Signing.Key)
+			}
+
+			switch {
+			case options.Recursive != "":
+				if err := configManager.applyRecursive(options.Recursive, selectedProfile, options.Yes, options.DryRun); err != nil {
+					fmt.Fprintln(stdout, tr("Error applying profile:"), err)
+					os.Exit(1)
+				}
+			case options.Registered:
+				if err := configManager.applyRegistered(selectedProfile, options.DryRun); err != nil {
+					fmt.Fprintln(stdout, tr("Error applying profile:"), err)
+					os.Exit(1)
+				}
+			case options.DryRun:
+				configManager.dryRunApplyHooks(stdout, hookPreApply, selectedProfile)
+				if err := dryRunTargets(stdout, ".", selectedProfile, profile, targets); err != nil {
+					fmt.Fprintln(stdout, tr("Error applying profile:"), err)
+					os.Exit(1)
+				}
+				if options.Submodules {
+					if err := dryRunSubmodules(stdout, ".", selectedProfile, profile); err != nil {
+						fmt.Fprintln(stdout, tr("Error applying profile:"), err)
+						os.Exit(1)
+					}
+				}
+				if options.Rewrite {
+					if url, _ := gitConfigGet(".", "remote.origin.url"); url != "" {
+						if rewritten, err := rewriteRemoteURL(url, selectedProfile, profile); err == nil {
+							fmt.Fprintln(stdout, shellCommand("git", "remote", "set-url", "origin", rewritten))
+						}
+					}
+				}
+				if profile.GitHub.User != "" && !options.NoGH {
+					fmt.Fprintln(stdout, shellCommand(append([]string{"gh"}, ghAuthSwitchArgs(profile)...)...))
+				}
+				configManager.dryRunApplyHooks(stdout, hookPostApply, selectedProfile)
+			default:
+				if err := configManager.runApplyHooks(hookPreApply, ".", selectedProfile, profile); err != nil {
+					fmt.Fprintf(stdout, tr("Error applying profile: %v\n"), err)
+					os.Exit(1)
+				}
+				// Any failed scope fails the command, whether or not others were applied, so scripts can rely on the status
+				failures := configManager.applyToTargets(".", selectedProfile, profile, targets)
+				switch {
+				case len(targets) == 1 && len(failures) == 1:
+					fmt.Fprintf(stdout, tr("Error applying profile: %v\n"), errors.Unwrap(failures[0]))
+					os.Exit(1)
+				case len(failures) == len(targets):
+					fmt.Fprintln(stdout, tr("Error applying profile: every scope failed"))
+					os.Exit(1)
+				}
+				configManager.markUsed(selectedProfile)
+
+				// Submodules fall back to the global identity unless their own local config is set
+				if options.Submodules {
+					applied, failures := applyToSubmodules(".", selectedProfile, profile)
+					for _, err := range failures {
+						warnf("%v", err)
+					}
+					fmt.Fprintf(notices, tr("Profile '%s' applied to %d submodule(s).\n"), selectedProfile, applied)
+				}
+
+				// Keep the allowed signers file current so SSH signatures verify locally
+				if profile.Signing.Key != "" && profile.SigningFormat() == signingFormatSSH {
+					if path, err := allowedSignersPath(); err == nil {
+						for _, err := range configManager.syncAllowedSigners(path) {
+							warnf("%v", err)
+						}
+					}
+				}
+
+				// Make sure ssh offers the profile's key
+				switch err := checkSSHAgent(profile); {
+				case errors.Is(err, errKeyNotInAgent) && !isTerminal():
+					warnf("%v; run 'ssh-add %s'", err, profile.SSH.Key)
+				case errors.Is(err, errKeyNotInAgent):
+					confirm := promptui.Prompt{
+						Label:     fmt.Sprintf(tr("SSH key %s is not loaded in the ssh-agent. Add it"), profile.SSH.Key),
+						IsConfirm: true,
+					}
+					if _, err := confirm.Run(); err == nil {
+						if err := addToAgent(expandHome(profile.SSH.Key)); err != nil {
+							warnf("%v", err)
+						}
+					}
+				case err != nil:
+					warnf("%v", err)
+				}
+
+				// Route origin through the profile's SSH host alias
+				if options.Rewrite {
+					if path, err := sshConfigPath(); err == nil {
+						if err := configManager.syncSSHConfig(path); err != nil {
+							warnf("%v", err)
+						}
+					}
+					url, err := rewriteOrigin(".", selectedProfile, profile)
+					if err != nil {
+						fmt.Fprintln(stdout, tr("Error rewriting remote:"), err)
+						os.Exit(1)
+					}
+					fmt.Fprintf(notices, tr("Remote 'origin' rewritten to %s\n"), url)
+				}
+
+				// Switch the GitHub CLI along with the Git identity
+				if profile.GitHub.User != "" && !options.NoGH {
+					if err := switchGHAccount(profile); err != nil {
+						warnf("%v", err)
+					} else {
+						fmt.Fprintf(notices, tr("GitHub CLI switched to '%s'.\n"), profile.GitHub.User)
+					}
+				}
+
+				if err := configManager.runApplyHooks(hookPostApply, ".", selectedProfile, profile); err != nil {
+					warnf("%v", err)
+				}
+
+				if len(failures) > 0 {
+					fmt.Fprintf(stdout, tr("Profile '%s' applied to %d of %d scopes.\n"), selectedProfile, len(targets)-len(failures), len(targets))
+					os.Exit(1)
+				}
+				fmt.Fprintf(notices, tr("Profile '%s' applied successfully!\n"), selectedProfile)
+			}
+		},
+	}
+
+	applyCmd.Flags().StringVar(&options.Tag, "tag", "", "Only offer profiles with this tag")
+	applyCmd.Flags().BoolVar(&options.Registered, "registered", false, "Reapply the profile to every repository registered with it")
+	applyCmd.Flags().StringVarP(&options.Recursive, "recursive", "r", "", "Apply the profile to every repository under this directory")
+	applyCmd.Flags().BoolVarP(&options.Yes, "yes", "y", false, "Skip confirmation prompts")
+	applyCmd.Flags().BoolVar(&options.Rewrite, "rewrite-remote", false, "Rewrite origin to use the profile's SSH host alias")
+	applyCmd.Flags().BoolVar(&options.NoGH, "no-gh", false, "Don't switch the GitHub CLI account")
+	applyCmd.Flags().BoolVar(&options.DryRun, "dry-run", false, "Print the commands apply would run without changing anything")
+	applyCmd.Flags().BoolVar(&options.Strict, "strict", false, "Fail instead of warning when the signing key can't be verified")
+	applyCmd.Flags().BoolVar(&options.Submodules, "recurse-submodules", false, "Also apply the profile to every initialized submodule, nested ones included")
+	applyCmd.Flags().BoolVar(&options.Worktree, "worktree", false, "Write the profile to this worktree's config only (needs extensions.worktreeConfig)")
+	applyCmd.Flags().BoolVar(&options.Global, "global", false, "Write the profile to the global config (combine with --local to write both)")
+	applyCmd.Flags().BoolVar(&options.Local, "local", false, "Write the profile to the repository's config, the default unless --global or --worktree is given")
+	applyCmd.Flags().BoolVar(&options.FromEnv, "from-env", false, "Take the profile from GIT_PROFILE or GIT_PROFILE_NAME, GIT_PROFILE_EMAIL and GIT_PROFILE_SIGNING_KEY, e.g. in CI")
+	applyCmd.Flags().BoolVar(&options.AllScopes, "all-scopes", false, "Write the profile to both the global and the repository's config")
+
+	return applyCmd
+}
+
+// newUnapplyCmd builds the unapply command
+func newUnapplyCmd(configManager *ConfigManager) *cobra.Command {
+	var worktree bool
+
+	var unapplyCmd = &cobra.Command{
+		Use:   "unapply",
+		Short: "Remove the settings written by apply from the current repository",
+		Run: func(cmd *cobra.Command, args []string) {
+			var configArgs []string
+			scope := scopeLocal
+			if worktree {
+				if err := checkWorktreeConfig("."); err != nil {
+					fmt.Fprintln(stdout, tr("Error removing profile:"), err)
+					os.Exit(1)
+				}
+				configArgs, scope = worktreeConfigArgs, scopeWorktree
+			}
+			assigned, _ := gitConfigGet(".", assignedProfileKey)
+
+			if err := unapplyProfile(".", configArgs...); err != nil {
+				fmt.Fprintln(stdout, tr("Error removing profile:"), err)
+				os.Exit(1)
+			}
+			configManager.recordHistory("unapply", assigned, ".", scope)
+
+			if repoPath := repoTopLevel("."); repoPath != "" {
+				if _, exists := configManager.Repos[repoPath]; exists {
+					delete(configManager.Repos, repoPath)
+					configManager.save()
+				}
+			}
+
+			if assigned == "" {
+				fmt.Fprintln(notices, tr("No profile was applied to this repository."))
+				return
+			}
+			fmt.Fprintf(notices, tr("Profile '%s' removed from this repository.\n"), assigned)
+		},
+	}
+
+	unapplyCmd.Flags().BoolVar(&worktree, "worktree", false, "Remove what 'apply --worktree' wrote to this worktree's config")
+	return unapplyCmd
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ApplyHooks are shell commands run around apply, e.g. to switch a VPN, kubeconfig or npmrc along with the identity
+type ApplyHooks struct {
+	PreApply  []string `json:"pre_apply,omitempty"`
+	PostApply []string `json:"post_apply,omitempty"`
+}
+
+// Stages of apply hooks, also passed to them as GIT_PROFILE_HOOK
+const (
+	hookPreApply  = "pre-apply"
+	hookPostApply = "post-apply"
+)
+
+// commands returns the hook commands of a stage
+func (h ApplyHooks) commands(stage string) []string {
+	if stage == hookPreApply {
+		return h.PreApply
+	}
+	return h.PostApply
+}
+
+// applyHookCommands returns the commands to run at a stage for a profile: the global hooks, then the profile's own.
+// Hooks of profiles from shared sources are skipped, since they would run commands someone else wrote.
+func (cm *ConfigManager) applyHookCommands(stage string, name string) []string {
+	commands := append([]string(nil), cm.Hooks.commands(stage)...)
+
+	own := cm.Profiles[name].Hooks.commands(stage)
+	if source, shared := cm.sourceOf(name); shared && len(own) > 0 {
+		warnf("ignoring the %s hooks of profile '%s' from %s; copy them to the global hooks to run them", stage, name, source)
+		return commands
+	}
+	return append(commands, own...)
+}
+
+// hookEnv serializes a profile into the environment variables hooks receive
+func hookEnv(stage string, dir string, name string, profile Profile) []string {
+	data, _ := json.Marshal(profile)
+	return []string{
+		"GIT_PROFILE_HOOK=" + stage,
+		"GIT_PROFILE=" + name,
+		"GIT_PROFILE_REPO=" + repoTopLevel(dir),
+		"GIT_PROFILE_USER_NAME=" + profile.Name,
+		"GIT_PROFILE_USER_EMAIL=" + profile.Email,
+		"GIT_PROFILE_SIGNING_KEY=" + profile.Signing.Key,
+		"GIT_PROFILE_SIGNING_FORMAT=" + profile.SigningFormat(),
+		"GIT_PROFILE_SSH_KEY=" + expandHome(profile.SSH.Key),
+		"GIT_PROFILE_GITHUB_USER=" + profile.GitHub.User,
+		"GIT_PROFILE_HOST=" + profile.Host,
+		"GIT_PROFILE_WORKSPACE=" + profile.Workspace,
+		"GIT_PROFILE_TAGS=" + strings.Join(profile.Tags, ","),
+		"GIT_PROFILE_JSON=" + string(data),
+	}
+}
+
+// shellArgs runs a hook command through the platform's shell
+func shellArgs(command string) []string {
+	if runtime.GOOS == "windows" {
+		return []string{"cmd", "/C", command}
+	}
+	return []string{"sh", "-c", command}
+}
+
+// runApplyHooks runs the hooks of a stage in the repository at dir, stopping at the first one that fails
+func (cm *ConfigManager) runApplyHooks(stage string, dir string, name string, profile Profile) error {
+	for _, command := range cm.applyHookCommands(stage, name) {
+		infof("running %s hook: %s", stage, command)
+
+		args := shellArgs(command)
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), hookEnv(stage, dir, name, profile)...)
+		cmd.Stdin = os.Stdin
+		// Hook output goes to stderr so it never mixes into results on stdout
+		cmd.Stdout = stderr
+		cmd.Stderr = stderr
+
+		if err := cmd.Run(); err != nil {
+			return errorf("%s hook '%s': %w", stage, command, err)
+		}
+	}
+	return nil
+}
+
+// applyWithHooks applies a profile to the repository at dir between the pre- and post-apply hooks, as apply does in
+// the current repository: a failing pre-apply hook leaves the repository untouched, a failing post-apply hook only warns
+func (cm *ConfigManager) applyWithHooks(dir string, name string, profile Profile) error {
+	// Hooks get the resolved profile, like they do in apply, and nothing runs when it can't be resolved
+	profile, err := resolveProfile(profile)
+	if err != nil {
+		return err
+	}
+	if err := cm.runApplyHooks(hookPreApply, dir, name, profile); err != nil {
+		return err
+	}
+	if err := applyProfile(dir, name, profile); err != nil {
+		return err
+	}
+	if err := cm.runApplyHooks(hookPostApply, dir, name, profile); err != nil {
+		warnf("%s: %v", displayPath(dir), err)
+	}
+	return nil
+}
+
+// dryRunApplyHooks prints the hooks of a stage as comments, without running them
+func (cm *ConfigManager) dryRunApplyHooks(w io.Writer, stage string, name string) {
+	for _, command := range cm.applyHookCommands(stage, name) {
+		fmt.Fprintf(w, "# %s hook: %s\n", stage, command)
+	}
+}
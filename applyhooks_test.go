@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestApplyHooks tests running global and profile hooks with the profile in the environment
+func TestApplyHooks(t *testing.T) {
+	repoDir := initTestRepo(t)
+	outputFile := filepath.Join(repoDir, "hooks.log")
+
+	profile := Profile{Name: "John Doe", Email: "john.doe@company.com", Tags: []string{"client", "work"}}
+	profile.Hooks.PreApply = []string{`echo "profile $GIT_PROFILE_HOOK $GIT_PROFILE $GIT_PROFILE_USER_EMAIL $GIT_PROFILE_TAGS" >> hooks.log`}
+	profile.Hooks.PostApply = []string{`echo "profile $GIT_PROFILE_HOOK" >> hooks.log`}
+	cm := &ConfigManager{Profiles: map[string]Profile{"work": profile}}
+	cm.Hooks.PreApply = []string{`echo "global $GIT_PROFILE_HOOK" >> hooks.log`}
+
+	assert.NoError(t, cm.runApplyHooks(hookPreApply, repoDir, "work", profile))
+	assert.NoError(t, cm.runApplyHooks(hookPostApply, repoDir, "work", profile))
+	data, err := os.ReadFile(outputFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "global pre-apply\nprofile pre-apply work john.doe@company.com client,work\nprofile post-apply\n", string(data))
+
+	// The first failing hook stops the rest
+	cm.Hooks.PreApply = []string{"exit 3", "echo unreachable >> hooks.log"}
+	err = cm.runApplyHooks(hookPreApply, repoDir, "work", profile)
+	assert.ErrorContains(t, err, "pre-apply hook 'exit 3'")
+	data, _ = os.ReadFile(outputFile)
+	assert.NotContains(t, string(data), "unreachable")
+
+	var out bytes.Buffer
+	cm.dryRunApplyHooks(&out, hookPreApply, "work")
+	assert.True(t, strings.HasPrefix(out.String(), "# pre-apply hook: exit 3\n"))
+
+	// Profiles applied to other repositories give hooks the same resolved profile as apply does
+	t.Setenv("WORK_KEY", "ABCDEF0123456789")
+	cm.Hooks.PreApply = []string{`echo "key $GIT_PROFILE_SIGNING_KEY" > hooks.log`}
+	resolved := profile
+	resolved.Hooks = ApplyHooks{}
+	resolved.Signing.Key = `{{env "WORK_KEY"}}`
+	cm.Profiles["work"] = resolved
+	assert.NoError(t, cm.applyWithHooks(repoDir, "work", resolved))
+	data, _ = os.ReadFile(outputFile)
+	assert.Equal(t, "key ABCDEF0123456789\n", string(data))
+	os.Unsetenv("WORK_KEY")
+	assert.ErrorContains(t, cm.applyWithHooks(repoDir, "work", resolved), "WORK_KEY")
+	data, _ = os.ReadFile(outputFile)
+	assert.Equal(t, "key ABCDEF0123456789\n", string(data))
+
+	// Shared profiles don't get to run their own hooks
+	cm.Hooks = ApplyHooks{}
+	cm.sourced = map[string]sourcedProfile{"work": {Source: "https://example.com/team.json", Profile: profile}}
+	assert.Empty(t, cm.applyHookCommands(hookPreApply, "work"))
+
+	// Hooks are part of the config schema
+	problems, err := validateConfigData([]byte(`{"profiles": {"work": {"name": "John Doe", "email": "john.doe@company.com",
+		"hooks": {"post_apply": ["kubectl config use-context work"]}}}, "hooks": {"pre_apply": ["vpn up"]}}`))
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+}
+
+// TestHookEnv tests serializing a profile for hooks
+func TestHookEnv(t *testing.T) {
+	profile := Profile{Name: "John Doe", Email: "john.doe@company.com"}
+	profile.GitHub.User = "jdoe"
+
+	env := hookEnv(hookPostApply, ".", "work", profile)
+	assert.Contains(t, env, "GIT_PROFILE_HOOK=post-apply")
+	assert.Contains(t, env, "GIT_PROFILE=work")
+	assert.Contains(t, env, "GIT_PROFILE_USER_NAME=John Doe")
+	assert.Contains(t, env, "GIT_PROFILE_GITHUB_USER=jdoe")
+	assert.Contains(t, env, `GIT_PROFILE_JSON={"name":"John Doe","email":"john.doe@company.com",`+
+		`"author":{},"committer":{},"signing":{},"credential":{},"ssh":{},"github":{"user":"jdoe"},"settings":{},"network":{},"commit_template":{},"excludes":{},"hooks":{}}`)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// visibleProfiles returns the names of the profiles that aren't archived, sorted, as offered by ls and the selectors
+func (cm *ConfigManager) visibleProfiles() []string {
+	var names []string
+	for name, profile := range cm.Profiles {
+		if !profile.Archived {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// checkNotArchived refuses to apply an archived profile
+func (cm *ConfigManager) checkNotArchived(name string) error {
+	if cm.Profiles[name].Archived {
+		return errorf("profile '%s' is archived; restore it with 'git profile unarchive %s' to apply it", name, name)
+	}
+	return nil
+}
+
+// setArchived archives or restores profiles, returning how many changed
+func (cm *ConfigManager) setArchived(names []string, archived bool) (int, error) {
+	for _, name := range names {
+		if _, exists := cm.Profiles[name]; !exists {
+			return 0, errorf("profile '%s' not found", name)
+		}
+		if source, shared := cm.sourceOf(name); shared {
+			return 0, errorf("profile '%s' comes from %s; set \"archived\" there instead", name, source)
+		}
+	}
+
+	changed := 0
+	for _, name := range names {
+		profile := cm.Profiles[name]
+		if profile.Archived != archived {
+			profile.Archived = archived
+			cm.Profiles[name] = profile
+			changed++
+		}
+	}
+	if changed > 0 {
+		cm.save()
+	}
+	return changed, nil
+}
+
+// newArchiveCmd builds the archive command
+func newArchiveCmd(configManager *ConfigManager) *cobra.Command {
+	var archiveCmd = &cobra.Command{
+		Use:   "archive <profile>...",
+		Short: "Hide profiles from ls and the selectors and block applying them, keeping them for audit",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if _, err := configManager.setArchived(args, true); err != nil {
+				fmt.Fprintln(stdout, tr("Archiving failed:"), err)
+				os.Exit(1)
+			}
+			for _, name := range args {
+				fmt.Fprintf(notices, tr("📦 Profile '%s' archived.\n"), name)
+			}
+		},
+	}
+
+	return archiveCmd
+}
+
+// newUnarchiveCmd builds the unarchive command
+func newUnarchiveCmd(configManager *ConfigManager) *cobra.Command {
+	var unarchiveCmd = &cobra.Command{
+		Use:   "unarchive <profile>...",
+		Short: "Restore archived profiles",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if _, err := configManager.setArchived(args, false); err != nil {
+				fmt.Fprintln(stdout, tr("Unarchiving failed:"), err)
+				os.Exit(1)
+			}
+			for _, name := range args {
+				fmt.Fprintf(notices, tr("Profile '%s' restored.\n"), name)
+			}
+		},
+	}
+
+	return unarchiveCmd
+}
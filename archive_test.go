@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestArchivedProfiles tests that archived profiles are hidden and can't be applied, but still match emails
+func TestArchivedProfiles(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	cm := &ConfigManager{ConfigPath: filepath.Join(tmpDir, ".git-profiles-test.json"), Profiles: map[string]Profile{
+		"client-a": {Name: "John Doe", Email: "john@client-a.com", Remotes: []string{"github.com/client-a/*"}},
+		"work":     {Name: "John Doe", Email: "john.doe@company.com"},
+	}}
+
+	changed, err := cm.setArchived([]string{"client-a"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, []string{"work"}, cm.visibleProfiles())
+	assert.ErrorContains(t, cm.checkNotArchived("client-a"), "unarchive client-a")
+	assert.NoError(t, cm.checkNotArchived("work"))
+
+	// Old commits still belong to the profile, but new clones aren't steered to it
+	name, found := cm.findProfileByEmail("john@client-a.com")
+	assert.True(t, found)
+	assert.Equal(t, "client-a", name)
+	_, found = cm.suggestProfile("git@github.com:client-a/api.git")
+	assert.False(t, found)
+
+	changed, err = cm.setArchived([]string{"client-a"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, []string{"client-a", "work"}, cm.visibleProfiles())
+	_, err = cm.setArchived([]string{"missing"}, true)
+	assert.ErrorContains(t, err, "not found")
+}
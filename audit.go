@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// identityUsage counts how often an identity appears in a repository's history
+type identityUsage struct {
+	Name      string
+	Email     string
+	Authored  int
+	Committed int
+}
+
+// auditCommits aggregates commits by identity, most frequent first
+func auditCommits(commits []commitIdentity) []identityUsage {
+	usage := make(map[string]*identityUsage)
+	record := func(name, email string) *identityUsage {
+		key := name + "\x00" + strings.ToLower(email)
+		if _, exists := usage[key]; !exists {
+			usage[key] = &identityUsage{Name: name, Email: email}
+		}
+		return usage[key]
+	}
+
+	for _, commit := range commits {
+		record(commit.AuthorName, commit.AuthorEmail).Authored++
+		record(commit.CommitterName, commit.CommitterEmail).Committed++
+	}
+
+	var identities []identityUsage
+	for _, identity := range usage {
+		identities = append(identities, *identity)
+	}
+
+	sort.Slice(identities, func(i, j int) bool {
+		left, right := identities[i], identities[j]
+		if left.Authored+left.Committed != right.Authored+right.Committed {
+			return left.Authored+left.Committed > right.Authored+right.Committed
+		}
+		if left.Email != right.Email {
+			return left.Email < right.Email
+		}
+		return left.Name < right.Name
+	})
+
+	return identities
+}
+
+// newAuditCmd builds the audit command
+func newAuditCmd(configManager *ConfigManager) *cobra.Command {
+	var auditCmd = &cobra.Command{
+		Use:   "audit [path]",
+		Short: "Report every identity in a repository's history",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			repoPath := "."
+			if len(args) > 0 {
+				repoPath = args[0]
+			}
+
+			commits, err := listCommits(repoPath, "--all")
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Audit failed:"), err)
+				os.Exit(1)
+			}
+
+			identities := auditCommits(commits)
+			fmt.Fprintf(stdout, tr("🔍 %d identities across %d commits\n\n"), len(identities), len(commits))
+
+			unknown := 0
+			for _, identity := range identities {
+				match := tr("UNKNOWN")
+				if name, found := configManager.findProfileByEmail(identity.Email); found {
+					match = fmt.Sprintf(tr("profile: %s"), name)
+				} else {
+					unknown++
+				}
+				fmt.Fprintf(stdout, "  %s <%s>\n", identity.Name, identity.Email)
+				fmt.Fprintf(stdout, tr("    %d authored, %d committed (%s)\n"), identity.Authored, identity.Committed, match)
+			}
+
+			if unknown > 0 {
+				fmt.Fprintf(stdout, tr("\n⚠️  %d identities don't match any saved profile\n"), unknown)
+			}
+		},
+	}
+
+	return auditCmd
+}
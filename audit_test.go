@@ -0,0 +1,66 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAuditCommits tests aggregating identities from commit history
+func TestAuditCommits(t *testing.T) {
+	commits := []commitIdentity{
+		{SHA: "a", AuthorName: "John Doe", AuthorEmail: "john.doe@company.com", CommitterName: "John Doe", CommitterEmail: "john.doe@company.com"},
+		{SHA: "b", AuthorName: "John Doe", AuthorEmail: "John.Doe@company.com", CommitterName: "GitHub", CommitterEmail: "noreply@github.com"},
+		{SHA: "c", AuthorName: "Jane Smith", AuthorEmail: "jane@example.com", CommitterName: "GitHub", CommitterEmail: "noreply@github.com"},
+	}
+
+	identities := auditCommits(commits)
+	assert.Len(t, identities, 3)
+
+	assert.Equal(t, "john.doe@company.com", identities[0].Email)
+	assert.Equal(t, 2, identities[0].Authored)
+	assert.Equal(t, 1, identities[0].Committed)
+
+	assert.Equal(t, "noreply@github.com", identities[1].Email)
+	assert.Equal(t, 0, identities[1].Authored)
+	assert.Equal(t, 2, identities[1].Committed)
+
+	assert.Equal(t, "Jane Smith", identities[2].Name)
+}
+
+// TestFindProfileByEmail tests looking up profiles by email
+func TestFindProfileByEmail(t *testing.T) {
+	cm := &ConfigManager{
+		Profiles: map[string]Profile{
+			"work":     {Name: "John Doe", Email: "john.doe@company.com"},
+			"work-alt": {Name: "J. Doe", Email: "john.doe@company.com"},
+		},
+	}
+
+	name, found := cm.findProfileByEmail("JOHN.DOE@company.com")
+	assert.True(t, found)
+	assert.Equal(t, "work", name)
+
+	_, found = cm.findProfileByEmail("nobody@example.com")
+	assert.False(t, found)
+}
+
+// TestFindProfileByEmailAlias tests that aliases identify their profile, after main emails
+func TestFindProfileByEmailAlias(t *testing.T) {
+	cm := &ConfigManager{
+		Profiles: map[string]Profile{
+			"a-old":    {Name: "John Doe", Email: "john@acme.com", EmailAliases: []string{"john@oldcorp.com"}},
+			"work":     {Name: "John Doe", Email: "john@oldcorp.com"},
+			"personal": {Name: "John Doe", Email: "john@personal.dev", EmailAliases: []string{"1234+john@users.noreply.github.com"}},
+		},
+	}
+
+	name, found := cm.findProfileByEmail("1234+JOHN@users.noreply.github.com")
+	assert.True(t, found)
+	assert.Equal(t, "personal", name)
+
+	// The profile whose main email it is wins over one listing it as an alias
+	name, found = cm.findProfileByEmail("john@oldcorp.com")
+	assert.True(t, found)
+	assert.Equal(t, "work", name)
+}
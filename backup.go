@@ -0,0 +1,234 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+)
+
+// defaultBackupKeep is how many backups are kept when the config doesn't say otherwise
+const defaultBackupKeep = 10
+
+// backupTimeFormat names backups so they sort chronologically
+const backupTimeFormat = "20060102T150405.000000000Z"
+
+// backupDir returns the directory config backups are written to
+func backupDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "git-profile", "backups"), nil
+}
+
+// backupKeep returns the configured backup retention
+func (cm *ConfigManager) backupKeep() int {
+	if cm.BackupKeep > 0 {
+		return cm.BackupKeep
+	}
+	return defaultBackupKeep
+}
+
+// listBackups returns the backup file names, newest first
+func listBackups(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	return names, nil
+}
+
+// backup snapshots the config file before a destructive operation and prunes old snapshots
+func (cm *ConfigManager) backup(reason string) (string, error) {
+	data, err := os.ReadFile(cm.ConfigPath)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := backupDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, time.Now().UTC().Format(backupTimeFormat)+"-"+reason+".json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", err
+	}
+
+	names, err := listBackups(dir)
+	if err != nil {
+		return path, err
+	}
+	for i := cm.backupKeep(); i < len(names); i++ {
+		os.Remove(filepath.Join(dir, names[i]))
+	}
+	return path, nil
+}
+
+// backupBefore takes a backup ahead of reason, warning instead of failing when it can't be written
+func (cm *ConfigManager) backupBefore(reason string) {
+	if _, err := cm.backup(reason); err != nil {
+		warnf("Backup failed: %v", err)
+	}
+}
+
+// restoreBackup replaces the config file with a backup, backing up the current one first
+func (cm *ConfigManager) restoreBackup(name string) error {
+	dir, err := backupDir()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, filepath.Base(name)))
+	if err != nil {
+		return err
+	}
+	if _, err := parseConfig(data); err != nil {
+		return errorf("backup %s is not a valid config: %w", name, err)
+	}
+
+	if _, err := cm.backup("restore"); err != nil {
+		return err
+	}
+	if err := os.WriteFile(cm.ConfigPath, data, 0644); err != nil {
+		return err
+	}
+	cm.load()
+	return nil
+}
+
+// describeBackup renders a backup file name as its time and the operation it preceded
+func describeBackup(name string) string {
+	stamp, reason, _ := strings.Cut(strings.TrimSuffix(name, ".json"), "-")
+	taken, err := time.Parse(backupTimeFormat, stamp)
+	if err != nil {
+		return name
+	}
+	return fmt.Sprintf(tr("%s  before %s"), taken.Local().Format("2006-01-02 15:04:05"), reason)
+}
+
+// newBackupCmd builds the backup command
+func newBackupCmd(configManager *ConfigManager) *cobra.Command {
+	var keep int
+
+	var backupCmd = &cobra.Command{
+		Use:   "backup",
+		Short: "Back up the config now, or change how many backups are kept",
+		Run: func(cmd *cobra.Command, args []string) {
+			if cmd.Flags().Changed("keep") {
+				if keep < 1 {
+					fmt.Fprintln(stdout, tr("Keep at least one backup."))
+					os.Exit(1)
+				}
+				configManager.BackupKeep = keep
+				configManager.save()
+				fmt.Fprintf(notices, tr("Keeping the %d most recent backups.\n"), keep)
+				return
+			}
+
+			path, err := configManager.backup("manual")
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Backup failed:"), err)
+				os.Exit(1)
+			}
+			if path == "" {
+				fmt.Fprintln(stdout, tr("Nothing to back up yet."))
+				return
+			}
+			fmt.Fprintf(notices, tr("Backup written to: %s\n"), path)
+		},
+	}
+	backupCmd.Flags().IntVar(&keep, "keep", defaultBackupKeep, "Number of backups to keep")
+
+	return backupCmd
+}
+
+// newRestoreCmd builds the restore command
+func newRestoreCmd(configManager *ConfigManager) *cobra.Command {
+	var list bool
+
+	var restoreCmd = &cobra.Command{
+		Use:   "restore [backup]",
+		Short: "Roll the config back to a backup",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			dir, err := backupDir()
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Restore failed:"), err)
+				os.Exit(1)
+			}
+			names, err := listBackups(dir)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Restore failed:"), err)
+				os.Exit(1)
+			}
+			if len(names) == 0 {
+				fmt.Fprintln(stdout, tr("No backups found."))
+				return
+			}
+
+			if list {
+				for _, name := range names {
+					fmt.Fprintf(stdout, "%s  (%s)\n", describeBackup(name), name)
+				}
+				return
+			}
+
+			var selected string
+			if len(args) > 0 {
+				selected = args[0]
+			} else {
+				if err := requireTerminal(tr("pass the backup to restore (see 'restore --list')")); err != nil {
+					fmt.Fprintln(stdout, tr("Restore failed:"), err)
+					os.Exit(1)
+				}
+				var items []string
+				for _, name := range names {
+					items = append(items, describeBackup(name))
+				}
+				prompt := promptui.Select{
+					Label: tr("Select backup to restore"),
+					Items: items,
+				}
+				index, _, err := prompt.Run()
+				if err != nil {
+					fmt.Fprintln(notices, tr("Cancelled."))
+					return
+				}
+				selected = names[index]
+			}
+
+			if err := configManager.restoreBackup(selected); err != nil {
+				fmt.Fprintln(stdout, tr("Restore failed:"), err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(notices, tr("Config restored from %s.\n"), selected)
+		},
+	}
+	restoreCmd.Flags().BoolVar(&list, "list", false, "List the available backups")
+
+	return restoreCmd
+}
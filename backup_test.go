@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBackupAndRestore tests rotating backups and rolling the config back to one
+func TestBackupAndRestore(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(homeDir)
+	t.Setenv("HOME", homeDir)
+
+	cm := &ConfigManager{
+		ConfigPath: filepath.Join(homeDir, ".git-profiles.json"),
+		Profiles:   map[string]Profile{"work": {Name: "John Doe", Email: "john.doe@company.com"}},
+		BackupKeep: 3,
+	}
+
+	// Nothing is backed up before the config exists
+	path, err := cm.backup("rm")
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+
+	cm.save()
+	first, err := cm.backup("rm")
+	assert.NoError(t, err)
+	assert.FileExists(t, first)
+
+	delete(cm.Profiles, "work")
+	cm.save()
+	for i := 0; i < 4; i++ {
+		_, err := cm.backup("edit")
+		assert.NoError(t, err)
+	}
+
+	dir, err := backupDir()
+	assert.NoError(t, err)
+	names, err := listBackups(dir)
+	assert.NoError(t, err)
+	assert.Len(t, names, 3)
+	assert.NoFileExists(t, first)
+
+	// Restoring brings the profile back and backs up the config it replaces
+	cm.BackupKeep = 10
+	cm.save()
+	_, err = cm.backup("rm")
+	assert.NoError(t, err)
+	cm.Profiles["work"] = Profile{Name: "John Doe", Email: "john.doe@company.com"}
+	cm.save()
+	_, err = cm.backup("rm")
+	assert.NoError(t, err)
+
+	names, err = listBackups(dir)
+	assert.NoError(t, err)
+	delete(cm.Profiles, "work")
+	cm.save()
+	assert.NoError(t, cm.restoreBackup(names[0]))
+	assert.Contains(t, cm.Profiles, "work")
+
+	restoredNames, err := listBackups(dir)
+	assert.NoError(t, err)
+	assert.Len(t, restoredNames, len(names)+1)
+	assert.Contains(t, restoredNames[0], "-restore.json")
+	assert.Contains(t, describeBackup(restoredNames[0]), "before restore")
+}
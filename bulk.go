@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/manifoldco/promptui"
+)
+
+// pendingChanges returns the repositories under root whose identity differs from the profile
+func (cm *ConfigManager) pendingChanges(root string, name string) ([]repoStatus, int, error) {
+	repos, err := findRepos(root)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	profile, err := resolveProfile(cm.Profiles[name])
+	if err != nil {
+		return nil, 0, err
+	}
+	var pending []repoStatus
+	for _, repo := range repos {
+		status := cm.inspectRepo(repo)
+		assigned, _ := gitConfigGet(repo, assignedProfileKey)
+		if status.Name != profile.Name || status.Email != profile.Email || assigned != name {
+			pending = append(pending, status)
+		}
+	}
+
+	return pending, len(repos), nil
+}
+
+// applyRecursive applies a profile to every repository under root after previewing the changes, or only prints the commands on a dry run
+func (cm *ConfigManager) applyRecursive(root string, name string, yes bool, dryRun bool) error {
+	profile, exists := cm.Profiles[name]
+	if !exists {
+		return errorf("profile '%s' not found", name)
+	}
+
+	pending, total, err := cm.pendingChanges(root, name)
+	if err != nil {
+		return err
+	}
+
+	if len(pending) == 0 {
+		fmt.Fprintf(notices, tr("All %d repositories under %s already use profile '%s'.\n"), total, displayPath(root), name)
+		return nil
+	}
+
+	fmt.Fprintf(notices, tr("Profile '%s' will be applied to %d of %d repositories:\n"), name, len(pending), total)
+	for _, status := range pending {
+		current := tr("no identity")
+		if status.Email != "" {
+			current = fmt.Sprintf("%s <%s>", status.Name, status.Email)
+		}
+		fmt.Fprintf(notices, "  %s (%s)\n", displayPath(status.Path), current)
+	}
+
+	if dryRun {
+		for _, status := range pending {
+			fmt.Fprintf(stdout, "\n# %s\n", displayPath(status.Path))
+			cm.dryRunApplyHooks(stdout, hookPreApply, name)
+			if err := dryRunApply(stdout, status.Path, name, profile); err != nil {
+				return err
+			}
+			cm.dryRunApplyHooks(stdout, hookPostApply, name)
+		}
+		return nil
+	}
+
+	if !yes {
+		if err := requireTerminal(tr("pass --yes to apply without confirmation")); err != nil {
+			return err
+		}
+		confirmPrompt := promptui.Prompt{
+			Label:     fmt.Sprintf(tr("Apply profile '%s' to these repositories"), name),
+			IsConfirm: true,
+		}
+		if _, err := confirmPrompt.Run(); err != nil {
+			fmt.Fprintln(notices, tr("Apply cancelled."))
+			return nil
+		}
+	}
+
+	failed := 0
+	for _, status := range pending {
+		if err := cm.applyWithHooks(status.Path, name, profile); err != nil {
+			failed++
+			fmt.Fprintf(stdout, "  ❌ %s: %v\n", displayPath(status.Path), err)
+			continue
+		}
+		cm.registerRepo(status.Path, name)
+		cm.recordHistory("apply", name, status.Path, scopeLocal)
+	}
+	cm.markUsed(name)
+
+	fmt.Fprintf(notices, tr("Changed %d repositories, %d already up to date, %d failed.\n"), len(pending)-failed, total-len(pending), failed)
+	if failed > 0 {
+		return errorf("%d repositories failed", failed)
+	}
+	return nil
+}
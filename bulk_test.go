@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestApplyRecursive tests applying a profile to every repository under a directory
+func TestApplyRecursive(t *testing.T) {
+	root, err := os.MkdirTemp("", "git-profile-scan")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	cm := &ConfigManager{
+		ConfigPath: filepath.Join(root, ".git-profiles-test.json"),
+		Profiles: map[string]Profile{
+			"work": {Name: "John Doe", Email: "john.doe@company.com"},
+		},
+	}
+
+	for _, repo := range []string{"api", "web", "infra/tools"} {
+		repoDir := filepath.Join(root, repo)
+		assert.NoError(t, os.MkdirAll(repoDir, 0755))
+		_, err := runGit(repoDir, "init", "--quiet")
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, applyProfile(filepath.Join(root, "web"), "work", cm.Profiles["work"]))
+
+	pending, total, err := cm.pendingChanges(root, "work")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, pending, 2)
+
+	// Apply hooks run in every repository that changes, like they do for the current one
+	hooksLog := filepath.Join(root, "hooks.log")
+	cm.Hooks.PreApply = []string{`echo "pre $(basename "$GIT_PROFILE_REPO")" >> ` + hooksLog}
+	cm.Hooks.PostApply = []string{`echo "post $(git config user.email)" >> ` + hooksLog}
+	assert.NoError(t, cm.applyRecursive(root, "work", true, false))
+	data, err := os.ReadFile(hooksLog)
+	assert.NoError(t, err)
+	assert.Equal(t, "pre api\npost john.doe@company.com\npre tools\npost john.doe@company.com\n", string(data))
+
+	pending, _, err = cm.pendingChanges(root, "work")
+	assert.NoError(t, err)
+	assert.Empty(t, pending)
+	assert.Len(t, cm.registeredPaths("work"), 2)
+
+	// A failing pre-apply hook leaves the repository untouched
+	cm.Hooks.PreApply = []string{"exit 1"}
+	assert.NoError(t, os.RemoveAll(filepath.Join(root, "api", ".git")))
+	_, err = runGit(filepath.Join(root, "api"), "init", "--quiet")
+	assert.NoError(t, err)
+	assert.Error(t, cm.applyRecursive(root, "work", true, false))
+	assigned, _ := gitConfigGet(filepath.Join(root, "api"), assignedProfileKey)
+	assert.Empty(t, assigned)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// Values of the --color flag
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+// ANSI colors used to highlight listings
+const (
+	colorGreen  = "32"
+	colorYellow = "33"
+	colorRed    = "31"
+)
+
+// stdoutIsTerminal reports whether stdout is attached to a terminal
+var stdoutIsTerminal = func() bool {
+	return isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd())
+}
+
+// useColor resolves the --color flag: auto colors a terminal unless NO_COLOR or --plain is set
+func useColor(mode string) (bool, error) {
+	switch mode {
+	case colorAlways:
+		return true, nil
+	case colorNever:
+		return false, nil
+	case colorAuto, "":
+		return stdoutIsTerminal() && !noColor(), nil
+	}
+	return false, errorf("unknown color mode '%s' (use %s, %s or %s)", mode, colorAuto, colorAlways, colorNever)
+}
+
+// paint wraps s in an ANSI color when enabled
+func paint(enabled bool, color string, s string) string {
+	if !enabled {
+		return s
+	}
+	return "\x1b[" + color + "m" + s + "\x1b[0m"
+}
+
+// missingKeys returns the key files a profile refers to that don't exist on this machine
+func (p Profile) missingKeys() []string {
+	var paths []string
+	if p.SSH.Key != "" {
+		paths = append(paths, p.SSH.Key)
+	}
+	if key := p.Signing.Key; key != "" && p.SigningFormat() == signingFormatSSH && !isSSHKeyLiteral(key) && !strings.HasPrefix(key, "key::") {
+		paths = append(paths, key)
+	}
+
+	var missing []string
+	for _, path := range paths {
+		if _, err := os.Stat(expandHome(path)); os.IsNotExist(err) {
+			missing = append(missing, path)
+		}
+	}
+	return missing
+}
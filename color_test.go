@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestColorMode tests resolving the --color flag
+func TestColorMode(t *testing.T) {
+	defer func(original func() bool) { stdoutIsTerminal = original }(stdoutIsTerminal)
+	defer func(mode outputOptions) { outputMode = mode }(outputMode)
+	outputMode = outputOptions{}
+	t.Setenv("NO_COLOR", "")
+	os.Unsetenv("NO_COLOR")
+
+	stdoutIsTerminal = func() bool { return true }
+	enabled, err := useColor(colorAuto)
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+
+	t.Setenv("NO_COLOR", "1")
+	enabled, _ = useColor(colorAuto)
+	assert.False(t, enabled)
+	enabled, _ = useColor(colorAlways)
+	assert.True(t, enabled)
+
+	stdoutIsTerminal = func() bool { return false }
+	os.Unsetenv("NO_COLOR")
+	enabled, _ = useColor(colorAuto)
+	assert.False(t, enabled)
+	enabled, _ = useColor(colorNever)
+	assert.False(t, enabled)
+
+	_, err = useColor("sometimes")
+	assert.Error(t, err)
+
+	assert.Equal(t, "\x1b[32mwork\x1b[0m", paint(true, colorGreen, "work"))
+	assert.Equal(t, "work", paint(false, colorGreen, "work"))
+}
+
+// TestMissingKeys tests spotting key files that don't exist
+func TestMissingKeys(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	present := filepath.Join(tmpDir, "id_work")
+	assert.NoError(t, os.WriteFile(present, []byte("key"), 0600))
+	absent := filepath.Join(tmpDir, "id_personal.pub")
+
+	profile := Profile{Name: "John Doe", Email: "john.doe@company.com"}
+	profile.SSH.Key = present
+	profile.Signing.Key = absent
+	assert.Equal(t, []string{absent}, profile.missingKeys())
+
+	profile.Signing.Key = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIJ"
+	assert.Empty(t, profile.missingKeys())
+}
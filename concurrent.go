@@ -0,0 +1,147 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"reflect"
+	"sort"
+)
+
+// configHash fingerprints the content of the config file
+func configHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// parseSnapshot decodes a config file like parseConfig, taking an empty file as an empty config
+func parseSnapshot(data []byte) (configFile, error) {
+	if len(data) == 0 {
+		return configFile{Profiles: make(map[string]Profile)}, nil
+	}
+	return parseConfig(data)
+}
+
+// remember records data as the config file content this process knows, the base for merging changes made meanwhile
+func (cm *ConfigManager) remember(data []byte) {
+	cm.loadedHash = configHash(data)
+	// A copy of its own, as the maps of the loaded config are modified in place
+	cm.loaded, _ = parseSnapshot(data)
+}
+
+// mergeConcurrentChanges checks whether another process (a second terminal, a sync tool) changed the config file
+// since this one read it and if so merges those changes into data, the config about to be saved; conflicting profile
+// edits are asked about, failing without a terminal so the other change isn't overwritten
+func (cm *ConfigManager) mergeConcurrentChanges(data []byte) ([]byte, error) {
+	current, err := os.ReadFile(cm.ConfigPath)
+	if os.IsNotExist(err) {
+		return data, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if configHash(current) == cm.loadedHash {
+		return data, nil
+	}
+
+	remote, err := parseSnapshot(current)
+	if err != nil {
+		return nil, errorf("%s was changed by another process and can't be read, so it was left as is: %w", displayPath(cm.ConfigPath), err)
+	}
+	local, err := parseSnapshot(data)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := mergeConcurrent(cm.loaded, local, remote, concurrentConflictResolver)
+	if err != nil {
+		return nil, errorf("%s was changed by another process while this command ran: %w", displayPath(cm.ConfigPath), err)
+	}
+	warnf("%s was changed by another process while this command ran; its changes were merged in.", displayPath(cm.ConfigPath))
+
+	cm.adoptMerged(merged)
+	return json.MarshalIndent(merged, "", "  ")
+}
+
+// concurrentConflictResolver asks how to resolve a profile both this and another process changed
+func concurrentConflictResolver(conflict profileConflict) (*Profile, error) {
+	return promptConflict(conflict, tr("in another process"), tr("run the command again to make the change on top of the other one"))
+}
+
+// mergeConcurrent three-way merges the config this process saves with the one another process saved since it was read
+func mergeConcurrent(base, local, remote configFile, resolve conflictResolver) (configFile, error) {
+	merged, err := mergeSnapshots(base, local, remote, tr("in another process"), resolve)
+	if err != nil {
+		return configFile{}, err
+	}
+	merged.Version = local.Version
+
+	// Nearly every apply registers a repository, so runs registering different ones are merged entry by entry
+	merged.Repos = mergeEntries(base.Repos, local.Repos, remote.Repos)
+
+	var conflicts []string
+	var conflict bool
+	if merged.BackupKeep, conflict = mergeSection(base.BackupKeep, local.BackupKeep, remote.BackupKeep); conflict {
+		conflicts = append(conflicts, "backup_keep")
+	}
+	if merged.KeyExpiryWarnDays, conflict = mergeSection(base.KeyExpiryWarnDays, local.KeyExpiryWarnDays, remote.KeyExpiryWarnDays); conflict {
+		conflicts = append(conflicts, "key_expiry_warn_days")
+	}
+	if merged.Hooks, conflict = mergeSection(base.Hooks, local.Hooks, remote.Hooks); conflict {
+		conflicts = append(conflicts, "hooks")
+	}
+	for _, section := range conflicts {
+		warnf("%s changed both here and %s; kept the local version.", section, tr("in another process"))
+	}
+	return merged, nil
+}
+
+// mergeEntries three-way merges a map entry by entry, keeping the local entry when both sides changed it
+func mergeEntries[V any](base, local, remote map[string]V) map[string]V {
+	keys := make(map[string]bool)
+	for _, entries := range []map[string]V{base, local, remote} {
+		for key := range entries {
+			keys[key] = true
+		}
+	}
+	var sorted []string
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	merged := make(map[string]V)
+	for _, key := range sorted {
+		baseEntry, inBase := base[key]
+		localEntry, inLocal := local[key]
+		remoteEntry, inRemote := remote[key]
+
+		entry, exists := localEntry, inLocal
+		if inLocal == inBase && reflect.DeepEqual(localEntry, baseEntry) {
+			entry, exists = remoteEntry, inRemote
+		}
+		if exists {
+			merged[key] = entry
+		}
+	}
+	return merged
+}
+
+// adoptMerged takes over the merged config as the state of this process, keeping shared and fragment profiles
+func (cm *ConfigManager) adoptMerged(merged configFile) {
+	for name := range cm.localProfiles() {
+		if _, kept := merged.Profiles[name]; !kept {
+			delete(cm.Profiles, name)
+		}
+	}
+	for name, profile := range merged.Profiles {
+		cm.Profiles[name] = profile
+	}
+	cm.Rules = merged.Rules
+	cm.Repos = merged.Repos
+	cm.Templates = merged.Templates
+	cm.Sources = merged.Sources
+	cm.BackupKeep = merged.BackupKeep
+	cm.KeyExpiryWarnDays = merged.KeyExpiryWarnDays
+	cm.Hooks = merged.Hooks
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConcurrentSave tests merging changes another process saved to the config file in the meantime
+func TestConcurrentSave(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	configPath := filepath.Join(tmpDir, ".git-profiles-test.json")
+	assert.NoError(t, os.WriteFile(configPath, []byte(`{"profiles": {"work": {"name": "John Doe", "email": "john.doe@company.com"}}}`), 0644))
+	open := func() *ConfigManager {
+		cm := &ConfigManager{ConfigPath: configPath, Profiles: make(map[string]Profile)}
+		cm.load()
+		return cm
+	}
+	first, second := open(), open()
+
+	// Changes to different parts are all kept
+	first.Profiles["personal"] = Profile{Name: "John Personal", Email: "john.personal@gmail.com"}
+	first.Repos = map[string]RegisteredRepo{"/home/john/blog": {Profile: "personal"}}
+	first.save()
+
+	work := second.Profiles["work"]
+	work.Name = "John A. Doe"
+	second.Profiles["work"] = work
+	second.Repos = map[string]RegisteredRepo{"/home/john/api": {Profile: "work"}}
+	second.save()
+	assert.Contains(t, second.Profiles, "personal")
+	assert.Len(t, second.Repos, 2)
+
+	reloaded := open()
+	assert.Equal(t, "John A. Doe", reloaded.Profiles["work"].Name)
+	assert.Equal(t, "john.personal@gmail.com", reloaded.Profiles["personal"].Email)
+	assert.Equal(t, "personal", reloaded.Repos["/home/john/blog"].Profile)
+	assert.Equal(t, "work", reloaded.Repos["/home/john/api"].Profile)
+
+	// A profile removed on one side and left alone on the other stays removed
+	delete(first.Profiles, "personal")
+	first.save()
+	reloaded.Rules = append(reloaded.Rules, Rule{Dir: "~/work/", Profile: "work"})
+	reloaded.save()
+	assert.NotContains(t, reloaded.Profiles, "personal")
+	assert.Len(t, open().Rules, 1)
+
+	// Without a terminal, editing the same profile on both sides fails and leaves the other change in place
+	first, second = open(), open()
+	work.Email = "john@company.com"
+	first.Profiles["work"] = work
+	first.save()
+	work.Email = "jdoe@company.com"
+	second.Profiles["work"] = work
+	data, err := json.Marshal(configFile{Version: currentConfigVersion, Profiles: second.Profiles})
+	assert.NoError(t, err)
+	_, err = second.mergeConcurrentChanges(data)
+	assert.ErrorContains(t, err, "changed by another process")
+	assert.ErrorContains(t, err, "not running in a terminal")
+	assert.Equal(t, "john@company.com", open().Profiles["work"].Email)
+}
+
+// TestMergeEntries tests the entry by entry three-way merge of repositories
+func TestMergeEntries(t *testing.T) {
+	base := map[string]int{"kept": 1, "changed": 1, "removed": 1, "both": 1}
+	local := map[string]int{"kept": 1, "changed": 1, "both": 2, "added": 1}
+	remote := map[string]int{"kept": 1, "changed": 2, "removed": 1, "both": 3}
+
+	assert.Equal(t, map[string]int{"kept": 1, "changed": 2, "both": 2, "added": 1}, mergeEntries(base, local, remote))
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// currentConfigVersion is the config file layout written by this version of git-profile
+const currentConfigVersion = 2
+
+// configMigration upgrades a raw config from one version to the next
+type configMigration func(raw map[string]json.RawMessage) (map[string]json.RawMessage, error)
+
+// configMigrations holds the upgrade from version i to version i+1 at index i
+var configMigrations = []configMigration{
+	// 0 -> 1: the file was a bare map of profiles
+	func(raw map[string]json.RawMessage) (map[string]json.RawMessage, error) {
+		profiles, err := json.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]json.RawMessage{"profiles": profiles}, nil
+	},
+	// 1 -> 2: the version field was introduced
+	func(raw map[string]json.RawMessage) (map[string]json.RawMessage, error) {
+		return raw, nil
+	},
+}
+
+// decodeStrict decodes data into v, rejecting fields v doesn't know so no data is silently dropped
+func decodeStrict(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+// detectConfigVersion returns the layout version of a raw config
+func detectConfigVersion(data []byte, raw map[string]json.RawMessage) (int, error) {
+	if value, exists := raw["version"]; exists {
+		var version int
+		if err := json.Unmarshal(value, &version); err != nil {
+			return 0, errorf("invalid config version %s", value)
+		}
+		return version, nil
+	}
+
+	// Versionless files are either the object layout or the legacy bare map of profiles
+	if decodeStrict(data, &configFile{}) == nil {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// migrateConfig upgrades raw config data to the current version, returning the version it started at
+func migrateConfig(data []byte) ([]byte, int, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, 0, err
+	}
+
+	version, err := detectConfigVersion(data, raw)
+	if err != nil {
+		return nil, 0, err
+	}
+	if version > currentConfigVersion {
+		return nil, version, errorf("config version %d is newer than this git-profile supports (%d); please upgrade git-profile", version, currentConfigVersion)
+	}
+
+	for v := version; v < currentConfigVersion; v++ {
+		if raw, err = configMigrations[v](raw); err != nil {
+			return nil, version, errorf("migrating config from version %d: %w", v, err)
+		}
+	}
+
+	raw["version"] = json.RawMessage(fmt.Sprint(currentConfigVersion))
+	migrated, err := json.Marshal(raw)
+	return migrated, version, err
+}
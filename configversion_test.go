@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConfigMigration tests upgrading every config version to the current layout
+func TestConfigMigration(t *testing.T) {
+	for _, data := range []string{
+		`{"work": {"name": "John Doe", "email": "john.doe@example.com"}}`,
+		`{"profiles": {"work": {"name": "John Doe", "email": "john.doe@example.com"}}}`,
+		`{"version": 2, "profiles": {"work": {"name": "John Doe", "email": "john.doe@example.com"}}}`,
+	} {
+		config, err := parseConfig([]byte(data))
+		assert.NoError(t, err)
+		assert.Equal(t, currentConfigVersion, config.Version)
+		assert.Equal(t, "john.doe@example.com", config.Profiles["work"].Email)
+	}
+
+	config, err := parseConfig([]byte(`{"work": {"name": "John Doe"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, config.migratedFrom)
+
+	// Files from a newer git-profile and unknown fields fail instead of losing data
+	_, err = parseConfig([]byte(`{"version": 99, "profiles": {}}`))
+	assert.ErrorContains(t, err, "upgrade git-profile")
+	_, err = parseConfig([]byte(`{"version": 2, "profiles": {}, "teams": []}`))
+	assert.ErrorContains(t, err, `unknown field "teams"`)
+	_, err = parseConfig([]byte(`{"version": "two"}`))
+	assert.Error(t, err)
+}
+
+// TestLoadMigratesConfig tests that loading an old config rewrites it at the current version
+func TestLoadMigratesConfig(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	t.Setenv("HOME", tmpDir)
+
+	configPath := filepath.Join(tmpDir, ".git-profiles-test.json")
+	legacy := `{"work": {"name": "John Doe", "email": "john.doe@example.com"}}`
+	assert.NoError(t, os.WriteFile(configPath, []byte(legacy), 0644))
+
+	cm := &ConfigManager{ConfigPath: configPath}
+	cm.load()
+	assert.Equal(t, "John Doe", cm.Profiles["work"].Name)
+
+	var saved map[string]json.RawMessage
+	data, err := os.ReadFile(configPath)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(data, &saved))
+	assert.JSONEq(t, "2", string(saved["version"]))
+	assert.Contains(t, saved, "profiles")
+
+	// The original file is kept as a backup
+	dir, err := backupDir()
+	assert.NoError(t, err)
+	backups, err := listBackups(dir)
+	assert.NoError(t, err)
+	assert.Len(t, backups, 1)
+}
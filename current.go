@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// newCurrentCmd builds the current command
+func newCurrentCmd(configManager *ConfigManager) *cobra.Command {
+	var porcelain bool
+	var currentCmd = &cobra.Command{
+		Use:   "current",
+		Short: "Print the profile in use in the current directory, exiting non-zero when none is",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			name, found := configManager.appliedProfile(".")
+			userName, _ := gitConfigGet(".", "user.name")
+			email, _ := gitConfigGet(".", "user.email")
+
+			// The profile, user.name and user.email, with - for what isn't set
+			if porcelain {
+				writePorcelain(stdout, name, userName, email)
+				if !found {
+					os.Exit(1)
+				}
+				return
+			}
+
+			switch {
+			case found:
+				fmt.Fprintf(stdout, tr("🎯 Profile in use: %s (%s <%s>)\n"), name, userName, email)
+				return
+			case email == "":
+				fmt.Fprintln(stdout, tr("No identity is configured here; apply a profile with 'git profile apply'."))
+			default:
+				fmt.Fprintf(stdout, tr("No saved profile matches the identity %s <%s>.\n"), userName, email)
+			}
+			os.Exit(1)
+		},
+	}
+
+	currentCmd.Flags().BoolVar(&porcelain, "porcelain", false, "Print the stable, tab-separated format for scripts")
+	return currentCmd
+}
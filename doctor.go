@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// Severities reported by doctor checks
+const (
+	severityWarning = "warning"
+	severityError   = "error"
+)
+
+// doctorFinding describes a problem detected by a doctor check
+type doctorFinding struct {
+	Severity string
+	Message  string
+}
+
+// doctorCheck inspects the repository at dir and reports any problems
+type doctorCheck func(cm *ConfigManager, dir string) []doctorFinding
+
+// doctorChecks are run in order by the doctor command
+var doctorChecks = []doctorCheck{
+	checkIdentityConfigured,
+	checkRemoteProfile,
+	checkPolicyFinding,
+	checkSigningKeyFinding,
+	checkKeyExpiryFinding,
+	checkSSHAgentFinding,
+	checkDuplicateIdentities,
+	checkProfileNames,
+}
+
+// appliedProfile returns the profile in use in the repository at dir, by assignment or by email
+func (cm *ConfigManager) appliedProfile(dir string) (string, bool) {
+	if assigned, err := gitConfigGet(dir, assignedProfileKey); err == nil {
+		if _, exists := cm.Profiles[assigned]; exists {
+			return assigned, true
+		}
+	}
+
+	email, err := gitConfigGet(dir, "user.email")
+	if err != nil || email == "" {
+		return "", false
+	}
+	return cm.findProfileByEmail(email)
+}
+
+// checkIdentityConfigured reports a missing or unknown effective identity
+func checkIdentityConfigured(cm *ConfigManager, dir string) []doctorFinding {
+	name, _ := gitConfigGet(dir, "user.name")
+	email, _ := gitConfigGet(dir, "user.email")
+	if name == "" || email == "" {
+		return []doctorFinding{{severityError, tr("user.name or user.email is not configured; run 'git profile apply'")}}
+	}
+
+	if _, found := cm.findProfileByEmail(email); !found && len(cm.Profiles) > 0 {
+		return []doctorFinding{{severityWarning, fmt.Sprintf(tr("email <%s> does not match any saved profile"), email)}}
+	}
+	return nil
+}
+
+// checkRemoteProfile warns when the applied profile differs from the one suggested by the origin remote
+func checkRemoteProfile(cm *ConfigManager, dir string) []doctorFinding {
+	suggested, found := cm.suggestProfileForRepo(dir)
+	if !found {
+		return nil
+	}
+
+	applied, _ := cm.appliedProfile(dir)
+	if applied == suggested {
+		return nil
+	}
+
+	if applied == "" {
+		applied = "none"
+	}
+	return []doctorFinding{{severityWarning, fmt.Sprintf(tr("remote origin suggests profile '%s' but '%s' is applied"), suggested, applied)}}
+}
+
+// runDoctor runs every doctor check against the repository at dir
+func (cm *ConfigManager) runDoctor(dir string) []doctorFinding {
+	var findings []doctorFinding
+	for _, check := range doctorChecks {
+		findings = append(findings, check(cm, dir)...)
+	}
+	return findings
+}
+
+// newDoctorCmd builds the doctor command
+func newDoctorCmd(configManager *ConfigManager) *cobra.Command {
+	var doctorCmd = &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose the Git identity setup of the current repository",
+		Run: func(cmd *cobra.Command, args []string) {
+			findings := configManager.runDoctor(".")
+			if len(findings) == 0 {
+				fmt.Fprintln(notices, tr("✅ No problems found."))
+				return
+			}
+
+			failed := false
+			for _, finding := range findings {
+				if finding.Severity == severityError {
+					failed = true
+					fmt.Fprintf(stdout, "❌ %s\n", finding.Message)
+				} else {
+					fmt.Fprintf(stdout, "⚠️  %s\n", finding.Message)
+				}
+			}
+
+			if failed {
+				os.Exit(1)
+			}
+		},
+	}
+
+	return doctorCmd
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDoctorRemoteProfile tests the remote/profile mismatch check
+func TestDoctorRemoteProfile(t *testing.T) {
+	repoDir := initTestRepo(t)
+	cm := &ConfigManager{
+		Profiles: map[string]Profile{
+			"work":     {Name: "John Doe", Email: "john.doe@company.com", Remotes: []string{"github.com/mycorp/*"}},
+			"personal": {Name: "John Personal", Email: "john.personal@gmail.com"},
+		},
+	}
+
+	_, err := runGit(repoDir, "remote", "add", "origin", "git@github.com:mycorp/api.git")
+	assert.NoError(t, err)
+
+	assert.NoError(t, applyProfile(repoDir, "personal", cm.Profiles["personal"]))
+	findings := checkRemoteProfile(cm, repoDir)
+	assert.Len(t, findings, 1)
+	assert.Contains(t, findings[0].Message, "'work'")
+
+	assert.NoError(t, applyProfile(repoDir, "work", cm.Profiles["work"]))
+	assert.Empty(t, cm.runDoctor(repoDir))
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// safeShellWord matches arguments that need no quoting in a shell
+var safeShellWord = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// shellCommand renders a command line as it would be typed, quoting only the arguments that need it
+func shellCommand(args ...string) string {
+	words := make([]string, len(args))
+	for i, arg := range args {
+		if safeShellWord.MatchString(arg) {
+			words[i] = arg
+		} else {
+			words[i] = shellQuote(arg)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// dryRunApply prints the commands applying a profile to the repository at dir would run, without running them
+func dryRunApply(w io.Writer, dir string, name string, profile Profile, configArgs ...string) error {
+	keys, err := appliedKeys(dir, configArgs...)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		fmt.Fprintln(w, shellCommand(append(append([]string{"git", "config"}, appliedScope(configArgs)...), "--unset-all", key)...))
+	}
+
+	profile, err = resolveProfile(profile)
+	if err != nil {
+		return err
+	}
+	var kinds []string
+	for kind, file := range profileFiles(profile) {
+		if file.Content != "" {
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		fmt.Fprintf(w, "# write %s\n", displayPath(profileFiles(profile)[kind].location(name, kind)))
+	}
+
+	for _, args := range applyCommands(name, profile, configArgs...) {
+		fmt.Fprintln(w, shellCommand(append([]string{"git"}, args...)...))
+	}
+	return nil
+}
+
+// importPlan lists what an import would do to the saved profiles
+type importPlan struct {
+	Added       []string
+	Overwritten []string
+	Skipped     []string
+	Removed     []string
+}
+
+// planImport sorts imported profiles into those an import would add, overwrite or leave alone, and existing ones it would drop
+func (cm *ConfigManager) planImport(imported map[string]Profile, strategy string) importPlan {
+	var plan importPlan
+	for name := range imported {
+		_, exists := cm.Profiles[name]
+		switch {
+		case !exists:
+			plan.Added = append(plan.Added, name)
+		case strategy == importReplace:
+			plan.Overwritten = append(plan.Overwritten, name)
+		default:
+			plan.Skipped = append(plan.Skipped, name)
+		}
+	}
+	if strategy == importReplace {
+		for name := range cm.Profiles {
+			if _, kept := imported[name]; !kept {
+				plan.Removed = append(plan.Removed, name)
+			}
+		}
+	}
+
+	for _, names := range [][]string{plan.Added, plan.Overwritten, plan.Skipped, plan.Removed} {
+		sort.Strings(names)
+	}
+	return plan
+}
+
+// print describes the plan
+func (p importPlan) print(w io.Writer) {
+	for _, group := range []struct {
+		label string
+		names []string
+	}{
+		{tr("Would add"), p.Added},
+		{tr("Would overwrite"), p.Overwritten},
+		{tr("Would keep the existing"), p.Skipped},
+		{tr("Would remove"), p.Removed},
+	} {
+		if len(group.names) > 0 {
+			fmt.Fprintf(w, "%s: %s\n", group.label, strings.Join(group.names, ", "))
+		}
+	}
+	if len(p.Added)+len(p.Overwritten)+len(p.Removed) == 0 {
+		fmt.Fprintln(w, tr("Nothing would change."))
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDryRunApply tests that a dry run prints the apply commands and leaves the repository alone
+func TestDryRunApply(t *testing.T) {
+	repoDir := initTestRepo(t)
+	assert.NoError(t, applyProfile(repoDir, "personal", Profile{Name: "John Personal", Email: "john.personal@gmail.com"}))
+
+	var buf bytes.Buffer
+	work := Profile{Name: "John Doe", Email: "john.doe@company.com"}
+	assert.NoError(t, dryRunApply(&buf, repoDir, "work", work))
+	assert.Equal(t, `git config --local --unset-all git-profile.key
+git config --local --unset-all git-profile.name
+git config --local --unset-all user.name
+git config --local --unset-all user.email
+git config user.name 'John Doe'
+git config --add git-profile.key user.name
+git config user.email john.doe@company.com
+git config --add git-profile.key user.email
+git config git-profile.name work
+`, buf.String())
+
+	email, err := gitConfigGet(repoDir, "user.email")
+	assert.NoError(t, err)
+	assert.Equal(t, "john.personal@gmail.com", email)
+
+	assert.Equal(t, `git config user.name 'O'\''Brien'`, shellCommand("git", "config", "user.name", "O'Brien"))
+}
+
+// TestPlanImport tests previewing what each import strategy would change
+func TestPlanImport(t *testing.T) {
+	cm := &ConfigManager{Profiles: map[string]Profile{
+		"work":     {Name: "John Doe", Email: "john.doe@company.com"},
+		"personal": {Name: "John Personal", Email: "john.personal@gmail.com"},
+	}}
+	imported := map[string]Profile{
+		"work": {Name: "John Doe", Email: "john.doe@acme.com"},
+		"oss":  {Name: "John Doe", Email: "john@oss.dev"},
+	}
+
+	assert.Equal(t, importPlan{Added: []string{"oss"}, Skipped: []string{"work"}}, cm.planImport(imported, importMerge))
+	plan := cm.planImport(imported, importReplace)
+	assert.Equal(t, importPlan{Added: []string{"oss"}, Overwritten: []string{"work"}, Removed: []string{"personal"}}, plan)
+
+	var buf bytes.Buffer
+	plan.print(&buf)
+	assert.Equal(t, "Would add: oss\nWould overwrite: work\nWould remove: personal\n", buf.String())
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// duplicateIdentity is a set of profiles sharing an email address, and the user name too when Name is set
+type duplicateIdentity struct {
+	Name  string
+	Email string
+	Names []string
+}
+
+func (d duplicateIdentity) String() string {
+	names := "'" + strings.Join(d.Names, "', '") + "'"
+	if d.Name != "" {
+		return fmt.Sprintf(tr("profiles %s share the identity %s <%s>"), names, d.Name, d.Email)
+	}
+	return fmt.Sprintf(tr("profiles %s share the email <%s>"), names, d.Email)
+}
+
+// duplicateIdentities groups the profiles sharing an email, ordered by email
+func (cm *ConfigManager) duplicateIdentities() []duplicateIdentity {
+	byEmail := make(map[string][]string)
+	for name, profile := range cm.Profiles {
+		email := strings.ToLower(profile.Email)
+		byEmail[email] = append(byEmail[email], name)
+	}
+
+	var duplicates []duplicateIdentity
+	for _, names := range byEmail {
+		if len(names) < 2 {
+			continue
+		}
+		sort.Strings(names)
+		first := cm.Profiles[names[0]]
+		duplicate := duplicateIdentity{Name: first.Name, Email: first.Email, Names: names}
+		for _, name := range names[1:] {
+			if cm.Profiles[name].Name != first.Name {
+				duplicate.Name = ""
+			}
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		return strings.ToLower(duplicates[i].Email) < strings.ToLower(duplicates[j].Email)
+	})
+	return duplicates
+}
+
+// warnDuplicates warns about the duplicate identities involving any of names
+func (cm *ConfigManager) warnDuplicates(names ...string) {
+	for _, duplicate := range cm.duplicateIdentities() {
+		for _, name := range names {
+			if slices.Contains(duplicate.Names, name) {
+				warnf("%s", duplicate)
+				break
+			}
+		}
+	}
+}
+
+// activeProfiles returns the profiles matching the identity name <email>, by main email or else by alias, narrowed to the
+// one assigned in dir when several match
+func (cm *ConfigManager) activeProfiles(dir string, name string, email string) ([]string, bool) {
+	var matches, aliased []string
+	for profileName, saved := range cm.Profiles {
+		profile := resolvedIdentity(saved)
+		switch {
+		case profile.Name != name:
+		case strings.EqualFold(profile.Email, email):
+			matches = append(matches, profileName)
+		case profile.OwnsEmail(email):
+			aliased = append(aliased, profileName)
+		}
+	}
+	if len(matches) == 0 {
+		matches = aliased
+	}
+	sort.Strings(matches)
+	if len(matches) < 2 {
+		return matches, false
+	}
+
+	if assigned, err := gitConfigGet(dir, assignedProfileKey); err == nil && slices.Contains(matches, assigned) {
+		return []string{assigned}, false
+	}
+	return matches, true
+}
+
+// checkDuplicateIdentities reports profiles sharing an email, and a repository identity that can't be told apart
+func checkDuplicateIdentities(cm *ConfigManager, dir string) []doctorFinding {
+	var findings []doctorFinding
+	for _, duplicate := range cm.duplicateIdentities() {
+		findings = append(findings, doctorFinding{severityWarning, duplicate.String()})
+	}
+
+	name, _ := gitConfigGet(dir, "user.name")
+	email, _ := gitConfigGet(dir, "user.email")
+	if matches, ambiguous := cm.activeProfiles(dir, name, email); ambiguous {
+		findings = append(findings, doctorFinding{severityWarning, fmt.Sprintf(
+			tr("identity %s <%s> matches profiles '%s'; apply one of them to record which is in use"), name, email, strings.Join(matches, "', '"))})
+	}
+	return findings
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDuplicateIdentities tests detecting profiles that share an email or a whole identity
+func TestDuplicateIdentities(t *testing.T) {
+	cm := &ConfigManager{
+		Profiles: map[string]Profile{
+			"work":     {Name: "John Doe", Email: "john.doe@company.com"},
+			"client-a": {Name: "John Doe", Email: "John.Doe@company.com"},
+			"personal": {Name: "John Personal", Email: "john@oss.dev"},
+			"oss":      {Name: "John Doe", Email: "john@oss.dev"},
+			"blog":     {Name: "John Doe", Email: "john@blog.dev"},
+		},
+	}
+
+	duplicates := cm.duplicateIdentities()
+	assert.Len(t, duplicates, 2)
+	assert.Equal(t, "profiles 'client-a', 'work' share the identity John Doe <John.Doe@company.com>", duplicates[0].String())
+	assert.Equal(t, "profiles 'oss', 'personal' share the email <john@oss.dev>", duplicates[1].String())
+
+	// The assignment recorded by apply settles which of the matching profiles is active
+	repoDir := initTestRepo(t)
+	matches, ambiguous := cm.activeProfiles(repoDir, "John Doe", "john.doe@company.com")
+	assert.True(t, ambiguous)
+	assert.Equal(t, []string{"client-a", "work"}, matches)
+
+	_, err := runGit(repoDir, "config", "--local", assignedProfileKey, "work")
+	assert.NoError(t, err)
+	matches, ambiguous = cm.activeProfiles(repoDir, "John Doe", "john.doe@company.com")
+	assert.False(t, ambiguous)
+	assert.Equal(t, []string{"work"}, matches)
+
+	matches, ambiguous = cm.activeProfiles(repoDir, "John Doe", "john@blog.dev")
+	assert.False(t, ambiguous)
+	assert.Equal(t, []string{"blog"}, matches)
+
+	// An alias makes its profile active, unless another profile has it as its main email
+	blog := cm.Profiles["blog"]
+	blog.EmailAliases = []string{"john@old-blog.dev", "john@oss.dev"}
+	cm.Profiles["blog"] = blog
+	matches, ambiguous = cm.activeProfiles(repoDir, "John Doe", "John@old-blog.dev")
+	assert.False(t, ambiguous)
+	assert.Equal(t, []string{"blog"}, matches)
+	matches, _ = cm.activeProfiles(repoDir, "John Doe", "john@oss.dev")
+	assert.Equal(t, []string{"oss"}, matches)
+}
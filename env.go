@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Shells env prints exports for
+const (
+	envShellBash = "bash"
+	envShellFish = "fish"
+	envShellPwsh = "pwsh"
+)
+
+// envExports renders the identity variables of profile as commands setting them in shell, one per line
+func envExports(profile Profile, shell string) (string, error) {
+	var exports strings.Builder
+	for _, variable := range identityEnv(profile) {
+		key, value, _ := strings.Cut(variable, "=")
+		switch shell {
+		case envShellBash:
+			fmt.Fprintf(&exports, "export %s=%s\n", key, shellQuote(value))
+		case envShellFish:
+			fmt.Fprintf(&exports, "set -gx %s '%s'\n", key, strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value))
+		case envShellPwsh:
+			fmt.Fprintf(&exports, "$env:%s = '%s'\n", key, strings.ReplaceAll(value, "'", "''"))
+		default:
+			return "", errorf("unknown shell '%s' (use %s, %s or %s)", shell, envShellBash, envShellFish, envShellPwsh)
+		}
+	}
+	return exports.String(), nil
+}
+
+// newEnvCmd builds the env command
+func newEnvCmd(configManager *ConfigManager) *cobra.Command {
+	var shell string
+
+	var envCmd = &cobra.Command{
+		Use:   "env <profile>",
+		Short: "Print shell exports committing as a profile, for eval in containers, without changing any config",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			// stdout is eval'd, so only the exports go there and errors go to stderr
+			name := args[0]
+			profile, exists := configManager.Profiles[name]
+			if !exists {
+				fmt.Fprintf(stderr, tr("Profile '%s' not found.\n"), name)
+				os.Exit(1)
+			}
+			profile, err := resolveProfile(profile)
+			if err != nil {
+				fmt.Fprintln(stderr, tr("Env failed:"), err)
+				os.Exit(1)
+			}
+
+			exports, err := envExports(profile, shell)
+			if err != nil {
+				fmt.Fprintln(stderr, tr("Env failed:"), err)
+				os.Exit(1)
+			}
+			fmt.Fprint(stdout, exports)
+		},
+	}
+
+	envCmd.Flags().StringVar(&shell, "shell", envShellBash, "Syntax of the exports: bash (also zsh and sh), fish or pwsh")
+	return envCmd
+}
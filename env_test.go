@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEnvExports tests rendering the identity of a profile as shell exports
+func TestEnvExports(t *testing.T) {
+	profile := Profile{Name: "John O'Doe", Email: "john.doe@company.com", Committer: Identity{Name: "CI Bot", Email: "ci@company.com"}}
+
+	exports, err := envExports(profile, envShellBash)
+	assert.NoError(t, err)
+	assert.Equal(t, `export GIT_AUTHOR_NAME='John O'\''Doe'
+export GIT_AUTHOR_EMAIL='john.doe@company.com'
+export GIT_COMMITTER_NAME='CI Bot'
+export GIT_COMMITTER_EMAIL='ci@company.com'
+`, exports)
+
+	exports, err = envExports(profile, envShellFish)
+	assert.NoError(t, err)
+	assert.Contains(t, exports, `set -gx GIT_AUTHOR_NAME 'John O\'Doe'`+"\n")
+
+	exports, err = envExports(profile, envShellPwsh)
+	assert.NoError(t, err)
+	assert.Contains(t, exports, `$env:GIT_AUTHOR_NAME = 'John O''Doe'`+"\n")
+
+	_, err = envExports(profile, "tcsh")
+	assert.ErrorContains(t, err, "unknown shell")
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+)
+
+// identityEnv returns the variables Git reads the author and committer of commits from, which win over any config file
+func identityEnv(profile Profile) []string {
+	author, committer := profile.AuthorIdentity(), profile.CommitterIdentity()
+	return []string{
+		"GIT_AUTHOR_NAME=" + author.Name,
+		"GIT_AUTHOR_EMAIL=" + author.Email,
+		"GIT_COMMITTER_NAME=" + committer.Name,
+		"GIT_COMMITTER_EMAIL=" + committer.Email,
+	}
+}
+
+// runAsProfile runs command with the identity of profile in its environment, returning its exit code
+func runAsProfile(profile Profile, command string, args ...string) (int, error) {
+	cmd := exec.Command(command, args...)
+	cmd.Env = append(os.Environ(), identityEnv(profile)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	return 0, err
+}
+
+// newExecCmd builds the exec command
+func newExecCmd(configManager *ConfigManager) *cobra.Command {
+	var execCmd = &cobra.Command{
+		Use:   "exec <profile> -- <command> [args...]",
+		Short: "Run a command committing as a profile, through GIT_AUTHOR_* and GIT_COMMITTER_*, without changing any config",
+		Args:  cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := args[0]
+			profile, exists := configManager.Profiles[name]
+			if !exists {
+				fmt.Fprintf(stdout, tr("Profile '%s' not found.\n"), name)
+				os.Exit(1)
+			}
+			profile, err := resolveProfile(profile)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Exec failed:"), err)
+				os.Exit(1)
+			}
+
+			debugf("exec: running %v as profile '%s'", args[1:], name)
+			code, err := runAsProfile(profile, args[1], args[2:]...)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Exec failed:"), err)
+				os.Exit(1)
+			}
+			os.Exit(code)
+		},
+	}
+
+	return execCmd
+}
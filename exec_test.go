@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRunAsProfile tests running a command with a profile's author and committer in its environment
+func TestRunAsProfile(t *testing.T) {
+	profile := Profile{Name: "John Doe", Email: "john.doe@company.com"}
+	profile.Author = Identity{Name: "Jane Client"}
+	assert.Equal(t, []string{
+		"GIT_AUTHOR_NAME=Jane Client",
+		"GIT_AUTHOR_EMAIL=john.doe@company.com",
+		"GIT_COMMITTER_NAME=John Doe",
+		"GIT_COMMITTER_EMAIL=john.doe@company.com",
+	}, identityEnv(profile))
+
+	code, err := runAsProfile(profile, "sh", "-c", `test "$GIT_AUTHOR_NAME" = "Jane Client" && test "$GIT_COMMITTER_NAME" = "John Doe"`)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, code)
+
+	code, err = runAsProfile(profile, "sh", "-c", "exit 3")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, code)
+
+	_, err = runAsProfile(profile, "git-profile-no-such-command")
+	assert.Error(t, err)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+)
+
+// shellQuote wraps s in single quotes for safe use in POSIX shell scripts
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// envFilterScript renders a filter-branch env filter replacing the from email with the profile's author identity as
+// author and its committer identity as committer
+func envFilterScript(from string, profile Profile) string {
+	var script strings.Builder
+	roles := []struct {
+		Name     string
+		Identity Identity
+	}{
+		{"AUTHOR", profile.AuthorIdentity()},
+		{"COMMITTER", profile.CommitterIdentity()},
+	}
+	for _, role := range roles {
+		fmt.Fprintf(&script, "if [ \"$GIT_%s_EMAIL\" = %s ]; then\n", role.Name, shellQuote(from))
+		fmt.Fprintf(&script, "  GIT_%s_NAME=%s; export GIT_%s_NAME\n", role.Name, shellQuote(role.Identity.Name), role.Name)
+		fmt.Fprintf(&script, "  GIT_%s_EMAIL=%s; export GIT_%s_EMAIL\n", role.Name, shellQuote(role.Identity.Email), role.Name)
+		script.WriteString("fi\n")
+	}
+	return script.String()
+}
+
+// unpushedRange returns the range of commits on the current branch not yet on its upstream, the only ones that can
+// be rewritten without force-pushing
+func unpushedRange(dir string) (string, error) {
+	if _, err := runGit(dir, "rev-parse", "--verify", "--quiet", "@{u}"); err != nil {
+		return "", errorf("the current branch has no upstream; pass --range to choose the commits to rewrite")
+	}
+	return "@{u}..HEAD", nil
+}
+
+// commitsByEmail filters commits whose author or committer uses email
+func commitsByEmail(commits []commitIdentity, email string) []commitIdentity {
+	var matching []commitIdentity
+	for _, commit := range commits {
+		if commit.AuthorEmail == email || commit.CommitterEmail == email {
+			matching = append(matching, commit)
+		}
+	}
+	return matching
+}
+
+// rewriteAuthor rewrites commits in revRange made with the from email to use the profile identity
+func rewriteAuthor(dir string, from string, profile Profile, revRange string) error {
+	cmd := exec.Command("git", "filter-branch", "--force", "--env-filter", envFilterScript(from, profile), "--", revRange)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "FILTER_BRANCH_SQUELCH_WARNING=1")
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git filter-branch: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// newFixAuthorCmd builds the fix-author command
+func newFixAuthorCmd(configManager *ConfigManager) *cobra.Command {
+	var from, toProfile, revRange string
+	var yes bool
+
+	var fixAuthorCmd = &cobra.Command{
+		Use:   "fix-author",
+		Short: "Rewrite commits made with the wrong identity",
+		Long: "Rewrite the author and committer of commits made with the wrong email so they use a saved profile.\n\n" +
+			"This rewrites history: every rewritten commit gets a new SHA, branches that were already pushed\n" +
+			"must be force-pushed, and collaborators will need to rebase. The original refs are kept under\n" +
+			"refs/original/ until you delete them.",
+		Run: func(cmd *cobra.Command, args []string) {
+			saved, exists := configManager.Profiles[toProfile]
+			if !exists {
+				fmt.Fprintf(stdout, tr("Profile '%s' not found.\n"), toProfile)
+				os.Exit(1)
+			}
+			profile, err := resolveProfile(saved)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Fix failed:"), err)
+				os.Exit(1)
+			}
+
+			if revRange == "" {
+				if revRange, err = unpushedRange("."); err != nil {
+					fmt.Fprintln(stdout, tr("Fix failed:"), err)
+					os.Exit(1)
+				}
+			}
+
+			commits, err := listCommits(".", revRange)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Fix failed:"), err)
+				os.Exit(1)
+			}
+
+			matching := commitsByEmail(commits, from)
+			if len(matching) == 0 {
+				fmt.Fprintf(stdout, tr("No commits in %s use <%s>.\n"), revRange, from)
+				return
+			}
+
+			author := profile.AuthorIdentity()
+			fmt.Fprintf(notices, tr("%d commit(s) in %s will be rewritten to %s <%s>:\n"), len(matching), revRange, author.Name, author.Email)
+			for _, commit := range matching {
+				fmt.Fprintf(notices, "  %s %s <%s>\n", commit.SHA, commit.AuthorName, commit.AuthorEmail)
+			}
+			fmt.Fprintln(stdout, tr("\n⚠️  This rewrites history. Rewritten commits get new SHAs and pushed branches must be force-pushed."))
+
+			if !yes {
+				if err := requireTerminal(tr("pass --yes to rewrite without confirmation")); err != nil {
+					fmt.Fprintln(stdout, tr("Fix failed:"), err)
+					os.Exit(1)
+				}
+				confirmPrompt := promptui.Prompt{
+					Label:     tr("Rewrite these commits"),
+					IsConfirm: true,
+				}
+				if _, err := confirmPrompt.Run(); err != nil {
+					fmt.Fprintln(notices, tr("Rewrite cancelled."))
+					return
+				}
+			}
+
+			if err := rewriteAuthor(".", from, profile, revRange); err != nil {
+				fmt.Fprintln(stdout, tr("Fix failed:"), err)
+				os.Exit(1)
+			}
+
+			fmt.Fprintf(notices, tr("Rewrote %d commit(s). Original refs are saved under refs/original/.\n"), len(matching))
+		},
+	}
+
+	fixAuthorCmd.Flags().StringVar(&from, "from", "", "Email to replace")
+	fixAuthorCmd.Flags().StringVar(&toProfile, "to-profile", "", "Profile whose identity replaces it")
+	fixAuthorCmd.Flags().StringVar(&revRange, "range", "", "Revision range to rewrite (e.g. HEAD~10..HEAD; default: the commits not pushed to the upstream yet)")
+	fixAuthorCmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip the confirmation prompt")
+	fixAuthorCmd.MarkFlagRequired("from")
+	fixAuthorCmd.MarkFlagRequired("to-profile")
+
+	return fixAuthorCmd
+}
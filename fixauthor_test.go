@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestShellQuote tests quoting values for shell scripts
+func TestShellQuote(t *testing.T) {
+	assert.Equal(t, "'plain'", shellQuote("plain"))
+	assert.Equal(t, `'O'\''Brien'`, shellQuote("O'Brien"))
+}
+
+// TestRewriteAuthor tests rewriting commits made with the wrong email
+func TestRewriteAuthor(t *testing.T) {
+	repoDir := initTestRepo(t)
+	commitAs(t, repoDir, "John Doe", "john.doe@company.com")
+	commitAs(t, repoDir, "John Personal", "john.personal@gmail.com")
+	commitAs(t, repoDir, "John Personal", "john.personal@gmail.com")
+
+	commits, err := listCommits(repoDir, "HEAD")
+	assert.NoError(t, err)
+	assert.Len(t, commitsByEmail(commits, "john.personal@gmail.com"), 2)
+
+	work := Profile{Name: "John O'Doe", Email: "john.doe@company.com"}
+	err = rewriteAuthor(repoDir, "john.personal@gmail.com", work, "HEAD~1..HEAD")
+	assert.NoError(t, err)
+
+	commits, err = listCommits(repoDir, "HEAD")
+	assert.NoError(t, err)
+	assert.Equal(t, "John O'Doe", commits[0].AuthorName)
+	assert.Equal(t, "john.doe@company.com", commits[0].CommitterEmail)
+	assert.Equal(t, "john.personal@gmail.com", commits[1].AuthorEmail)
+
+	// Without an upstream there's no safe default range
+	_, err = unpushedRange(repoDir)
+	assert.Error(t, err)
+	_, err = runGit(repoDir, "branch", "pushed", "HEAD~1")
+	assert.NoError(t, err)
+	_, err = runGit(repoDir, "branch", "--set-upstream-to=pushed")
+	assert.NoError(t, err)
+	revRange, err := unpushedRange(repoDir)
+	assert.NoError(t, err)
+	commits, err = listCommits(repoDir, revRange)
+	assert.NoError(t, err)
+	assert.Len(t, commits, 1)
+
+	// The author and committer overrides each replace their own role
+	release := Profile{Name: "John Doe", Email: "john.doe@company.com"}
+	release.Committer = Identity{Name: "Release Bot", Email: "release@company.com"}
+	err = rewriteAuthor(repoDir, "john.doe@company.com", release, revRange)
+	assert.NoError(t, err)
+	commits, err = listCommits(repoDir, "HEAD")
+	assert.NoError(t, err)
+	assert.Equal(t, "john.doe@company.com", commits[0].AuthorEmail)
+	assert.Equal(t, "Release Bot", commits[0].CommitterName)
+	assert.Equal(t, "release@company.com", commits[0].CommitterEmail)
+	assert.Equal(t, "john.doe@company.com", commits[2].CommitterEmail)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Forge kinds git-profile has host-specific helpers for
+const (
+	forgeGitHub    = "github"
+	forgeGitLab    = "gitlab"
+	forgeBitbucket = "bitbucket"
+)
+
+// forgeKind guesses which forge software runs on host, returning "" when unknown
+func forgeKind(host string) string {
+	host = strings.ToLower(host)
+	for _, kind := range []string{forgeGitHub, forgeGitLab, forgeBitbucket} {
+		if strings.Contains(host, kind) {
+			return kind
+		}
+	}
+	return ""
+}
+
+// remotePatterns returns the profile's remote patterns plus the one implied by its host and workspace
+func (p Profile) remotePatterns() []string {
+	patterns := p.Remotes
+	if p.Host == "" {
+		return patterns
+	}
+
+	implied := strings.ToLower(p.Host) + "/*"
+	if p.Workspace != "" {
+		implied = strings.ToLower(p.Host) + "/" + p.Workspace + "/*"
+	}
+	return append(append([]string(nil), patterns...), implied)
+}
+
+// noreplyEmail builds the forge's private commit email for an account, e.g. 123+jdoe@users.noreply.github.com
+func noreplyEmail(host string, user string, id string) (string, error) {
+	if user == "" {
+		return "", errorf("an account username is required")
+	}
+
+	switch forgeKind(host) {
+	case forgeGitHub:
+		domain := "users.noreply.github.com"
+		if !strings.EqualFold(host, "github.com") {
+			domain = "users.noreply." + strings.ToLower(host)
+		}
+		if id == "" {
+			return user + "@" + domain, nil
+		}
+		return id + "+" + user + "@" + domain, nil
+	case forgeGitLab:
+		if id == "" {
+			return "", errorf("GitLab noreply emails need the numeric user ID (--id)")
+		}
+		return id + "-" + user + "@users.noreply." + strings.ToLower(host), nil
+	case forgeBitbucket:
+		return "", errorf("Bitbucket has no noreply emails; use an email verified on the Bitbucket account")
+	}
+	return "", errorf("no noreply email format known for host '%s'", host)
+}
+
+// newNoreplyCmd builds the noreply command
+func newNoreplyCmd(configManager *ConfigManager) *cobra.Command {
+	var user, id string
+	var set bool
+
+	var noreplyCmd = &cobra.Command{
+		Use:   "noreply <profile>",
+		Short: "Show (or set) the noreply commit email of a profile's forge account",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := args[0]
+			profile, exists := configManager.Profiles[name]
+			if !exists {
+				fmt.Fprintf(stdout, tr("Profile '%s' not found.\n"), name)
+				os.Exit(1)
+			}
+
+			host := profile.Host
+			if host == "" {
+				host = "github.com"
+			}
+			if user == "" {
+				user = profile.GitHub.User
+			}
+
+			email, err := noreplyEmail(host, user, id)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Noreply failed:"), err)
+				os.Exit(1)
+			}
+
+			if !set {
+				fmt.Fprintln(stdout, email)
+				return
+			}
+			profile.Email = email
+			configManager.Profiles[name] = profile
+			configManager.save()
+			fmt.Fprintf(notices, tr("Profile '%s' email set to %s.\n"), name, email)
+		},
+	}
+	noreplyCmd.Flags().StringVar(&user, "user", "", "Account username on the forge (defaults to the profile's GitHub login)")
+	noreplyCmd.Flags().StringVar(&id, "id", "", "Numeric account ID (required for GitLab)")
+	noreplyCmd.Flags().BoolVar(&set, "set", false, "Save the noreply email as the profile email")
+
+	return noreplyCmd
+}
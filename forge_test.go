@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNoreplyEmail tests building forge-specific noreply emails
+func TestNoreplyEmail(t *testing.T) {
+	email, err := noreplyEmail("github.com", "jdoe", "123")
+	assert.NoError(t, err)
+	assert.Equal(t, "123+jdoe@users.noreply.github.com", email)
+
+	email, err = noreplyEmail("github.com", "jdoe", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "jdoe@users.noreply.github.com", email)
+
+	email, err = noreplyEmail("gitlab.com", "jdoe", "456")
+	assert.NoError(t, err)
+	assert.Equal(t, "456-jdoe@users.noreply.gitlab.com", email)
+
+	_, err = noreplyEmail("gitlab.com", "jdoe", "")
+	assert.Error(t, err)
+	_, err = noreplyEmail("bitbucket.org", "jdoe", "")
+	assert.Error(t, err)
+	_, err = noreplyEmail("git.example.com", "jdoe", "")
+	assert.Error(t, err)
+}
+
+// TestHostDetection tests suggesting profiles from their host and workspace
+func TestHostDetection(t *testing.T) {
+	cm := &ConfigManager{
+		Profiles: map[string]Profile{
+			"gitlab":   {Name: "John Doe", Email: "john@gitlab.dev", Host: "gitlab.com"},
+			"agency":   {Name: "John Doe", Email: "john@agency.com", Host: "bitbucket.org", Workspace: "agency"},
+			"explicit": {Name: "John Doe", Email: "john@client.com", Remotes: []string{"gitlab.com/client/*"}},
+		},
+	}
+
+	name, found := cm.suggestProfile("git@bitbucket.org:agency/site.git")
+	assert.True(t, found)
+	assert.Equal(t, "agency", name)
+
+	_, found = cm.suggestProfile("git@bitbucket.org:other/site.git")
+	assert.False(t, found)
+
+	name, found = cm.suggestProfile("https://gitlab.com/someone/repo.git")
+	assert.True(t, found)
+	assert.Equal(t, "gitlab", name)
+
+	// Explicit remote patterns are more specific than a bare host
+	name, found = cm.suggestProfile("https://gitlab.com/client/repo.git")
+	assert.True(t, found)
+	assert.Equal(t, "explicit", name)
+}
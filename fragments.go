@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+)
+
+// profileFragment is a file of profiles in the fragments directory, as it was last read or written
+type profileFragment struct {
+	Path     string
+	Profiles map[string]Profile
+}
+
+// defaultFragmentsDir returns the directory whose *.json files add profiles, e.g. one per dotfile manager
+func defaultFragmentsDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "git-profile", "profiles.d"), nil
+}
+
+// mergeFragments adds the profiles of every fragment file that aren't defined in the config file, later files winning
+func (cm *ConfigManager) mergeFragments() {
+	cm.fragments = nil
+	cm.fragmentOwner = make(map[string]string)
+	if cm.FragmentsDir == "" {
+		return
+	}
+	if cm.Profiles == nil {
+		cm.Profiles = make(map[string]Profile)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(cm.FragmentsDir, "*.json"))
+	if err != nil {
+		return
+	}
+	sort.Strings(paths)
+
+	defined := make(map[string]bool)
+	for name := range cm.Profiles {
+		defined[name] = true
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			warnf("Profile fragment %s skipped: %v", path, err)
+			continue
+		}
+		config, err := parseConfig(data)
+		if err != nil {
+			warnf("Profile fragment %s skipped: %v", path, err)
+			continue
+		}
+
+		cm.fragments = append(cm.fragments, profileFragment{Path: path, Profiles: config.Profiles})
+		for name, profile := range config.Profiles {
+			if defined[name] {
+				continue
+			}
+			cm.Profiles[name] = profile
+			cm.fragmentOwner[name] = path
+		}
+	}
+}
+
+// replaceProfiles swaps in a new set of config file profiles, merging the fragment and shared profiles back in so
+// saveFragments doesn't take those that aren't in profiles as removed
+func (cm *ConfigManager) replaceProfiles(profiles map[string]Profile) {
+	cm.Profiles = profiles
+	cm.mergeFragments()
+	cm.mergeSources()
+}
+
+// fragmentOf returns the fragment file a profile is saved to, if it doesn't live in the config file
+func (cm *ConfigManager) fragmentOf(name string) (string, bool) {
+	path, exists := cm.fragmentOwner[name]
+	return path, exists
+}
+
+// saveFragments writes profiles owned by fragment files back to them, leaving unchanged files untouched
+func (cm *ConfigManager) saveFragments() {
+	for i, fragment := range cm.fragments {
+		profiles := make(map[string]Profile)
+		for name, profile := range fragment.Profiles {
+			profiles[name] = profile
+		}
+		for name, path := range cm.fragmentOwner {
+			if path != fragment.Path {
+				continue
+			}
+			if profile, exists := cm.Profiles[name]; exists {
+				profiles[name] = profile
+			} else {
+				delete(profiles, name)
+			}
+		}
+		if reflect.DeepEqual(profiles, fragment.Profiles) {
+			continue
+		}
+
+		data, err := json.MarshalIndent(configFile{Version: currentConfigVersion, Profiles: profiles}, "", "  ")
+		if err != nil {
+			fatal(err)
+		}
+		if err := os.WriteFile(fragment.Path, data, 0644); err != nil {
+			fatal(err)
+		}
+		cm.fragments[i].Profiles = profiles
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProfileFragments tests merging profiles.d files and saving profiles back to the file they came from
+func TestProfileFragments(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	fragmentsDir := filepath.Join(tmpDir, "profiles.d")
+	assert.NoError(t, os.MkdirAll(fragmentsDir, 0755))
+	writeFragment := func(name string, profiles map[string]Profile) {
+		data, err := json.Marshal(profiles)
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(filepath.Join(fragmentsDir, name), data, 0644))
+	}
+	writeFragment("50-company.json", map[string]Profile{
+		"work":     {Name: "John Doe", Email: "john.doe@company.com"},
+		"oncall":   {Name: "Company Oncall", Email: "oncall@company.com"},
+		"personal": {Name: "Not Me", Email: "someone@company.com"},
+	})
+	writeFragment("90-personal.json", map[string]Profile{
+		"oncall": {Name: "John Doe", Email: "john.oncall@company.com"},
+		"oss":    {Name: "John Doe", Email: "john@oss.dev"},
+	})
+
+	cm := &ConfigManager{
+		ConfigPath: filepath.Join(tmpDir, ".git-profiles-test.json"),
+		Profiles: map[string]Profile{
+			"personal": {Name: "John Personal", Email: "john.personal@gmail.com"},
+		},
+	}
+	cm.save()
+
+	// The config file beats every fragment, and later fragments beat earlier ones
+	loaded := &ConfigManager{ConfigPath: cm.ConfigPath, FragmentsDir: fragmentsDir}
+	loaded.load()
+	assert.Len(t, loaded.Profiles, 4)
+	assert.Equal(t, "john.personal@gmail.com", loaded.Profiles["personal"].Email)
+	assert.Equal(t, "john.oncall@company.com", loaded.Profiles["oncall"].Email)
+	path, owned := loaded.fragmentOf("work")
+	assert.True(t, owned)
+	assert.Equal(t, filepath.Join(fragmentsDir, "50-company.json"), path)
+	_, owned = loaded.fragmentOf("personal")
+	assert.False(t, owned)
+
+	// Edits and removals go back to the owning file, shadowed entries are kept
+	work := loaded.Profiles["work"]
+	work.Email = "john.doe@corp.company.com"
+	loaded.Profiles["work"] = work
+	delete(loaded.Profiles, "oss")
+	loaded.Profiles["new"] = Profile{Name: "John New", Email: "john@new.dev"}
+	loaded.save()
+
+	company, err := os.ReadFile(filepath.Join(fragmentsDir, "50-company.json"))
+	assert.NoError(t, err)
+	companyConfig, err := parseConfig(company)
+	assert.NoError(t, err)
+	assert.Equal(t, "john.doe@corp.company.com", companyConfig.Profiles["work"].Email)
+	assert.Equal(t, "oncall@company.com", companyConfig.Profiles["oncall"].Email)
+	assert.Equal(t, "someone@company.com", companyConfig.Profiles["personal"].Email)
+
+	personal, err := os.ReadFile(filepath.Join(fragmentsDir, "90-personal.json"))
+	assert.NoError(t, err)
+	personalConfig, err := parseConfig(personal)
+	assert.NoError(t, err)
+	assert.NotContains(t, personalConfig.Profiles, "oss")
+	assert.Contains(t, personalConfig.Profiles, "oncall")
+
+	main, err := os.ReadFile(cm.ConfigPath)
+	assert.NoError(t, err)
+	mainConfig, err := parseConfig(main)
+	assert.NoError(t, err)
+	assert.Len(t, mainConfig.Profiles, 2)
+	assert.Contains(t, mainConfig.Profiles, "new")
+	assert.NotContains(t, mainConfig.Profiles, "work")
+}
+
+// TestFragmentsSurviveReplace tests that replacing the config file profiles by a sync pull or an import leaves the
+// fragment files alone
+func TestFragmentsSurviveReplace(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	t.Setenv("HOME", tmpDir)
+
+	fragmentsDir := filepath.Join(tmpDir, "profiles.d")
+	assert.NoError(t, os.MkdirAll(fragmentsDir, 0755))
+	corpPath := filepath.Join(fragmentsDir, "corp.json")
+	assert.NoError(t, os.WriteFile(corpPath, []byte(`{"profiles": {"corp": {"name": "John Doe", "email": "john.doe@corp.com"}}}`), 0644))
+	assertCorpKept := func(cm *ConfigManager) {
+		t.Helper()
+		assert.Contains(t, cm.Profiles, "corp")
+		data, err := os.ReadFile(corpPath)
+		assert.NoError(t, err)
+		config, err := parseConfig(data)
+		assert.NoError(t, err)
+		assert.Equal(t, "john.doe@corp.com", config.Profiles["corp"].Email)
+	}
+
+	cm := &ConfigManager{ConfigPath: filepath.Join(tmpDir, ".git-profiles-test.json"), FragmentsDir: fragmentsDir}
+	cm.load()
+	cm.Profiles["work"] = Profile{Name: "John Doe", Email: "john.doe@company.com"}
+	cm.save()
+
+	// A sync pull
+	remote := filepath.Join(tmpDir, "remote.git")
+	_, err = runGit("", "init", "--quiet", "--bare", remote)
+	assert.NoError(t, err)
+	other := &ConfigManager{
+		ConfigPath: filepath.Join(tmpDir, "other.json"),
+		Profiles:   map[string]Profile{"oss": {Name: "John Doe", Email: "john@oss.dev"}},
+	}
+	otherDir, syncDir := filepath.Join(tmpDir, "other-sync"), filepath.Join(tmpDir, "sync")
+	assert.NoError(t, setupSync(otherDir, remote))
+	_, err = other.pushSync(otherDir)
+	assert.NoError(t, err)
+	assert.NoError(t, setupSync(syncDir, remote))
+	pulled, err := cm.pullSync(syncDir, nil)
+	assert.NoError(t, err)
+	assert.True(t, pulled)
+	assert.Contains(t, cm.Profiles, "oss")
+	assertCorpKept(cm)
+
+	// An import replacing every profile
+	importPath := filepath.Join(tmpDir, "import.json")
+	assert.NoError(t, os.WriteFile(importPath, []byte(`{"profiles": {"client": {"name": "John Doe", "email": "john@client.com"}}}`), 0644))
+	assert.NoError(t, cm.Import(importPath, importOptions{Strategy: importReplace}))
+	assert.NotContains(t, cm.Profiles, "oss")
+	assertCorpKept(cm)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+// envProfileName is the name a profile given entirely through the environment is applied under
+const envProfileName = "env"
+
+// profileFromEnv reads the profile apply --from-env applies: the saved profile GIT_PROFILE selects, with any of
+// GIT_PROFILE_NAME, GIT_PROFILE_EMAIL and GIT_PROFILE_SIGNING_KEY overriding its values, or without a selector a
+// profile made of those variables alone, which is never saved
+func (cm *ConfigManager) profileFromEnv() (string, Profile, error) {
+	name, profile := envProfileName, Profile{}
+	if selected := os.Getenv("GIT_PROFILE"); selected != "" {
+		saved, exists := cm.Profiles[selected]
+		if !exists {
+			return "", Profile{}, errorf("GIT_PROFILE names profile '%s', which doesn't exist", selected)
+		}
+		if err := cm.checkNotArchived(selected); err != nil {
+			return "", Profile{}, err
+		}
+		name, profile = selected, saved
+	} else if os.Getenv("GIT_PROFILE_NAME") == "" || os.Getenv("GIT_PROFILE_EMAIL") == "" {
+		return "", Profile{}, errors.New(tr("set GIT_PROFILE to a saved profile, or GIT_PROFILE_NAME and GIT_PROFILE_EMAIL"))
+	}
+
+	if value := os.Getenv("GIT_PROFILE_NAME"); value != "" {
+		profile.Name = value
+	}
+	if value := os.Getenv("GIT_PROFILE_EMAIL"); value != "" {
+		profile.Email = value
+	}
+	if value := os.Getenv("GIT_PROFILE_SIGNING_KEY"); value != "" {
+		profile.Signing.Key = value
+	}
+
+	if problems := validateProfile(profile, false); len(problems) > 0 {
+		return "", Profile{}, errorf("profile from the environment is invalid: %w", errors.Join(problems...))
+	}
+	return name, profile, nil
+}
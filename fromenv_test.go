@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProfileFromEnv tests reading the profile to apply from the environment
+func TestProfileFromEnv(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	cm := &ConfigManager{ConfigPath: filepath.Join(tmpDir, ".git-profiles-test.json"), Profiles: map[string]Profile{
+		"corp": {Name: "John Doe", Email: "john.doe@company.com"},
+	}}
+	for _, key := range []string{"GIT_PROFILE", "GIT_PROFILE_NAME", "GIT_PROFILE_EMAIL", "GIT_PROFILE_SIGNING_KEY"} {
+		t.Setenv(key, "")
+	}
+
+	_, _, err = cm.profileFromEnv()
+	assert.ErrorContains(t, err, "set GIT_PROFILE")
+
+	// Without a selector the variables make up a profile of their own
+	t.Setenv("GIT_PROFILE_NAME", "CI Bot")
+	t.Setenv("GIT_PROFILE_EMAIL", "ci@company.com")
+	t.Setenv("GIT_PROFILE_SIGNING_KEY", "ABCDEF0123456789")
+	name, profile, err := cm.profileFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, envProfileName, name)
+	assert.Equal(t, "CI Bot", profile.Name)
+	assert.Equal(t, "ci@company.com", profile.Email)
+	assert.Equal(t, "ABCDEF0123456789", profile.Signing.Key)
+
+	// The selector picks a saved profile, which the other variables override
+	t.Setenv("GIT_PROFILE", "corp")
+	t.Setenv("GIT_PROFILE_NAME", "")
+	t.Setenv("GIT_PROFILE_SIGNING_KEY", "")
+	name, profile, err = cm.profileFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, "corp", name)
+	assert.Equal(t, "John Doe", profile.Name)
+	assert.Equal(t, "ci@company.com", profile.Email)
+
+	t.Setenv("GIT_PROFILE_EMAIL", "not-an-email")
+	_, _, err = cm.profileFromEnv()
+	assert.ErrorContains(t, err, "invalid")
+
+	t.Setenv("GIT_PROFILE", "missing")
+	_, _, err = cm.profileFromEnv()
+	assert.ErrorContains(t, err, "doesn't exist")
+}
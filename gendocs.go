@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+// generateDocs writes man pages to manDir and markdown reference to markdownDir for root and all its subcommands, skipping an empty dir
+func generateDocs(root *cobra.Command, manDir string, markdownDir string) error {
+	// A build date in every page would make packaged manuals differ between identical releases
+	root.DisableAutoGenTag = true
+
+	if manDir != "" {
+		if err := os.MkdirAll(manDir, 0755); err != nil {
+			return err
+		}
+		header := &doc.GenManHeader{
+			Title:   "GIT-PROFILE",
+			Section: "1",
+			Source:  "git-profile " + version,
+			Manual:  "Git Profile Manual",
+		}
+		if err := doc.GenManTree(root, header, manDir); err != nil {
+			return errorf("generating man pages: %w", err)
+		}
+	}
+
+	if markdownDir != "" {
+		if err := os.MkdirAll(markdownDir, 0755); err != nil {
+			return err
+		}
+		if err := doc.GenMarkdownTree(root, markdownDir); err != nil {
+			return errorf("generating markdown reference: %w", err)
+		}
+	}
+	return nil
+}
+
+// newGenDocsCmd builds the hidden gen-docs command packagers use to ship manuals
+func newGenDocsCmd(rootCmd *cobra.Command) *cobra.Command {
+	var manDir string
+	var markdownDir string
+
+	var genDocsCmd = &cobra.Command{
+		Use:    "gen-docs",
+		Short:  "Generate man pages and markdown reference for all commands",
+		Hidden: true,
+		Args:   cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if manDir == "" && markdownDir == "" {
+				fmt.Fprintln(stdout, tr("Generating docs failed: pass --man and/or --markdown with an output directory"))
+				os.Exit(1)
+			}
+			if err := generateDocs(rootCmd, expandHome(manDir), expandHome(markdownDir)); err != nil {
+				fmt.Fprintln(stdout, tr("Generating docs failed:"), err)
+				os.Exit(1)
+			}
+			if manDir != "" {
+				fmt.Fprintf(notices, tr("✅ Man pages written to %s\n"), manDir)
+			}
+			if markdownDir != "" {
+				fmt.Fprintf(notices, tr("✅ Markdown reference written to %s\n"), markdownDir)
+			}
+		},
+	}
+
+	genDocsCmd.Flags().StringVar(&manDir, "man", "", "Directory to write man pages (section 1) to")
+	genDocsCmd.Flags().StringVar(&markdownDir, "markdown", "", "Directory to write the markdown reference to")
+	return genDocsCmd
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGenerateDocs tests writing man pages and markdown for every visible command
+func TestGenerateDocs(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "git-profile-docs-*")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	root := &cobra.Command{Use: "git-profile", Short: "Manage Git profiles"}
+	root.AddCommand(&cobra.Command{Use: "ls", Short: "List profiles", Run: func(*cobra.Command, []string) {}})
+	root.AddCommand(newGenDocsCmd(root))
+
+	manDir := filepath.Join(tempDir, "man")
+	markdownDir := filepath.Join(tempDir, "markdown")
+	assert.NoError(t, generateDocs(root, manDir, markdownDir))
+
+	page, err := os.ReadFile(filepath.Join(manDir, "git-profile-ls.1"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `.TH "GIT-PROFILE" "1"`)
+	assert.Contains(t, string(page), "List profiles")
+	assert.FileExists(t, filepath.Join(manDir, "git-profile.1"))
+	assert.NoFileExists(t, filepath.Join(manDir, "git-profile-gen-docs.1"))
+
+	reference, err := os.ReadFile(filepath.Join(markdownDir, "git-profile_ls.md"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(reference), "## git-profile ls")
+	assert.NotContains(t, string(reference), "Auto generated by spf13/cobra")
+	assert.FileExists(t, filepath.Join(markdownDir, "git-profile.md"))
+
+	// An empty dir skips that format
+	onlyMarkdown := filepath.Join(tempDir, "only-markdown")
+	assert.NoError(t, generateDocs(root, "", onlyMarkdown))
+	assert.FileExists(t, filepath.Join(onlyMarkdown, "git-profile.md"))
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// defaultGitHubHost is the host gh authenticates against when a profile doesn't name one
+const defaultGitHubHost = "github.com"
+
+// ghAuthSwitchArgs builds the gh arguments that make the profile's GitHub account active
+func ghAuthSwitchArgs(profile Profile) []string {
+	host := profile.GitHub.Host
+	if host == "" {
+		host = defaultGitHubHost
+	}
+	return []string{"auth", "switch", "--hostname", host, "--user", profile.GitHub.User}
+}
+
+// switchGHAccount switches the GitHub CLI to the profile's account
+func switchGHAccount(profile Profile) error {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return errorf("gh is not installed, GitHub CLI account not switched")
+	}
+
+	output, err := exec.Command("gh", ghAuthSwitchArgs(profile)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("gh auth switch: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
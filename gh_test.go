@@ -0,0 +1,17 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGHAuthSwitchArgs tests the gh invocation used to switch GitHub CLI accounts
+func TestGHAuthSwitchArgs(t *testing.T) {
+	profile := Profile{Name: "John Doe", Email: "john.doe@company.com"}
+	profile.GitHub.User = "jdoe-corp"
+	assert.Equal(t, []string{"auth", "switch", "--hostname", "github.com", "--user", "jdoe-corp"}, ghAuthSwitchArgs(profile))
+
+	profile.GitHub.Host = "github.corp.example"
+	assert.Equal(t, []string{"auth", "switch", "--hostname", "github.corp.example", "--user", "jdoe-corp"}, ghAuthSwitchArgs(profile))
+}
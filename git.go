@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// assignedProfileKey is the local Git config key recording which profile was applied to a repository
+const assignedProfileKey = "git-profile.name"
+
+// appliedKeysKey is the multi-valued local Git config key listing the keys written by the last apply
+const appliedKeysKey = "git-profile.key"
+
+// configScope describes which config a git config invocation reads or writes
+func configScope(args []string) string {
+	for i, arg := range args {
+		switch arg {
+		case "--local", "--global", "--system", "--worktree":
+			return strings.TrimPrefix(arg, "--")
+		case "--file", "-f":
+			if i+1 < len(args) {
+				return "file " + displayPath(args[i+1])
+			}
+		case "--get", "--get-all", "--get-regexp", "--list", "-l":
+			return "all scopes"
+		}
+	}
+
+	// A lone key is a read; writes without a scope go to the repository
+	if len(args) == 1 {
+		return "all scopes"
+	}
+	return "local"
+}
+
+// traceGit logs a finished git invocation: config commands at info level so -v shows them, the rest at debug level
+func traceGit(dir string, args []string, err error, stderr string) {
+	command := shellCommand(append([]string{"git"}, args...)...)
+	if len(args) > 0 && args[0] == "config" {
+		command += " [" + configScope(args[1:]) + "]"
+	}
+	if dir != "" {
+		command += " in " + displayPath(absolutePath("", dir))
+	}
+
+	status := "exit 0"
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		status = fmt.Sprintf("exit %d", exitErr.ExitCode())
+	case err != nil:
+		status = err.Error()
+	}
+	if msg := strings.TrimSpace(stderr); msg != "" && err != nil {
+		status += ": " + msg
+	}
+
+	if len(args) > 0 && args[0] == "config" {
+		infof("%s → %s", command, status)
+	} else {
+		debugf("%s → %s", command, status)
+	}
+}
+
+// runGit executes git with the given arguments inside dir and returns its trimmed output
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	traceGit(dir, args, err, stderr.String())
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", strings.Join(args, " "), msg)
+		}
+		return "", fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// gitConfigGet reads a Git config key, returning an empty string when the key is not set
+func gitConfigGet(dir string, key string) (string, error) {
+	cmd := exec.Command("git", "config", "--get", key)
+	cmd.Dir = dir
+
+	output, err := cmd.Output()
+	traceGit(dir, cmd.Args[1:], err, "")
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", fmt.Errorf("git config --get %s: %w", key, err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// gitConfigUnset removes every value of key from the config selected by configArgs, ignoring keys that aren't set
+func gitConfigUnset(dir string, configArgs []string, key string) error {
+	args := append([]string{"config"}, configArgs...)
+	args = append(args, "--unset-all", key)
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	output, err := cmd.CombinedOutput()
+	traceGit(dir, args, err, string(output))
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 5 {
+			return nil
+		}
+		return fmt.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// gitConfigEntries lists the config entries whose key matches keyRegexp, in config order
+func gitConfigEntries(dir string, configArgs []string, keyRegexp string) ([]configEntry, error) {
+	args := append([]string{"config"}, configArgs...)
+	args = append(args, "--null", "--get-regexp", keyRegexp)
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	output, err := cmd.Output()
+	traceGit(dir, args, err, "")
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+	}
+
+	var entries []configEntry
+	for _, record := range strings.Split(string(output), "\x00") {
+		if record == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(record, "\n")
+		entries = append(entries, configEntry{Key: key, Value: value})
+	}
+	return entries, nil
+}
+
+// gitVersionAtLeast reports whether the installed Git is at least major.minor
+func gitVersionAtLeast(major int, minor int) bool {
+	output, err := runGit("", "version")
+	if err != nil {
+		return false
+	}
+
+	var gotMajor, gotMinor int
+	if _, err := fmt.Sscanf(output, "git version %d.%d", &gotMajor, &gotMinor); err != nil {
+		return false
+	}
+	return gotMajor > major || (gotMajor == major && gotMinor >= minor)
+}
+
+// parseIdent splits a Git identity such as "Jane Doe <jane@example.com> 1700000000 +0000" into name and email
+func parseIdent(ident string) (string, string) {
+	start := strings.Index(ident, "<")
+	end := strings.LastIndex(ident, ">")
+	if start < 0 || end < start {
+		return strings.TrimSpace(ident), ""
+	}
+
+	return strings.TrimSpace(ident[:start]), strings.TrimSpace(ident[start+1 : end])
+}
+
+// commitIdentity holds the author and committer of a single commit
+type commitIdentity struct {
+	SHA            string
+	AuthorName     string
+	AuthorEmail    string
+	CommitterName  string
+	CommitterEmail string
+}
+
+// listCommits returns the identities of the commits selected by the given git log arguments
+func listCommits(dir string, args ...string) ([]commitIdentity, error) {
+	logArgs := append([]string{"log", "--format=%H%x1f%an%x1f%ae%x1f%cn%x1f%ce"}, args...)
+	output, err := runGit(dir, logArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []commitIdentity
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 5 {
+			continue
+		}
+		commits = append(commits, commitIdentity{
+			SHA:            fields[0],
+			AuthorName:     fields[1],
+			AuthorEmail:    fields[2],
+			CommitterName:  fields[3],
+			CommitterEmail: fields[4],
+		})
+	}
+
+	return commits, nil
+}
+
+// isZeroSHA reports whether sha is the all-zero object name Git uses for missing refs
+func isZeroSHA(sha string) bool {
+	return sha != "" && strings.Trim(sha, "0") == ""
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// initTestRepo creates a temporary Git repository and returns its path
+func initTestRepo(t *testing.T) string {
+	t.Helper()
+
+	repoDir, err := os.MkdirTemp("", "git-profile-repo")
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(repoDir) })
+
+	_, err = runGit(repoDir, "init", "--quiet")
+	assert.NoError(t, err)
+
+	return repoDir
+}
+
+// commitAs records an empty commit in repoDir authored and committed by the given identity
+func commitAs(t *testing.T, repoDir string, name string, email string) string {
+	t.Helper()
+
+	_, err := runGit(repoDir, "-c", "user.name="+name, "-c", "user.email="+email,
+		"-c", "commit.gpgsign=false", "commit", "--quiet", "--allow-empty", "-m", "commit by "+name)
+	assert.NoError(t, err)
+
+	sha, err := runGit(repoDir, "rev-parse", "HEAD")
+	assert.NoError(t, err)
+	return sha
+}
+
+// TestListCommits tests reading commit identities from the log
+func TestListCommits(t *testing.T) {
+	repoDir := initTestRepo(t)
+	first := commitAs(t, repoDir, "John Doe", "john.doe@company.com")
+	second := commitAs(t, repoDir, "John Personal", "john.personal@gmail.com")
+
+	commits, err := listCommits(repoDir)
+	assert.NoError(t, err)
+	assert.Len(t, commits, 2)
+	assert.Equal(t, second, commits[0].SHA)
+	assert.Equal(t, "john.personal@gmail.com", commits[0].AuthorEmail)
+	assert.Equal(t, first, commits[1].SHA)
+	assert.Equal(t, "John Doe", commits[1].CommitterName)
+}
+
+// TestParseIdent tests splitting Git identities into name and email
+func TestParseIdent(t *testing.T) {
+	name, email := parseIdent("Jane Doe <jane@example.com> 1700000000 +0000")
+	assert.Equal(t, "Jane Doe", name)
+	assert.Equal(t, "jane@example.com", email)
+
+	name, email = parseIdent("no email here")
+	assert.Equal(t, "no email here", name)
+	assert.Equal(t, "", email)
+}
+
+// TestGitConfigGet tests reading set and unset config keys
+func TestGitConfigGet(t *testing.T) {
+	repoDir := initTestRepo(t)
+
+	_, err := runGit(repoDir, "config", assignedProfileKey, "work")
+	assert.NoError(t, err)
+
+	value, err := gitConfigGet(repoDir, assignedProfileKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "work", value)
+
+	value, err = gitConfigGet(repoDir, "git-profile.missing")
+	assert.NoError(t, err)
+	assert.Equal(t, "", value)
+}
+
+// TestTraceGit tests echoing git config invocations with their scope and exit status under -v
+func TestTraceGit(t *testing.T) {
+	assert.Equal(t, "global", configScope([]string{"--global", "user.email", "john.doe@company.com"}))
+	assert.Equal(t, "local", configScope([]string{"user.email", "john.doe@company.com"}))
+	assert.Equal(t, "all scopes", configScope([]string{"user.email"}))
+	assert.Equal(t, "all scopes", configScope([]string{"--null", "--get-regexp", "^user"}))
+
+	defer func(original *slog.Logger) { logger = original }(logger)
+	var buf bytes.Buffer
+	logger = slog.New(&consoleHandler{w: &buf, level: verbosityLevel(1)})
+
+	repoDir := initTestRepo(t)
+	buf.Reset()
+	_, err := runGit(repoDir, "config", "user.email", "john.doe@company.com")
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "git config user.email john.doe@company.com [local] in ")
+	assert.Contains(t, buf.String(), "→ exit 0")
+
+	buf.Reset()
+	_, err = runGit(repoDir, "config", "--file", "/nonexistent/dir/config", "user.email", "x@y.z")
+	assert.Error(t, err)
+	assert.Regexp(t, `\[file /nonexistent/dir/config\] in .* → exit \d+: .+`, buf.String())
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gpgKeyInfo holds the details of a secret key reported by gpg --with-colons
+type gpgKeyInfo struct {
+	KeyID    string
+	Validity string
+	Expires  *time.Time
+	UIDs     []string
+}
+
+// isSSHSigningKey reports whether a signing key refers to an SSH key (literal or file path) rather than an OpenPGP key
+func isSSHSigningKey(key string) bool {
+	return isSSHKeyLiteral(key) || strings.HasPrefix(key, "key::") || strings.HasSuffix(key, ".pub") ||
+		strings.HasPrefix(key, "/") || strings.HasPrefix(key, "~/") || strings.HasPrefix(key, "./")
+}
+
+// parseGPGKeys parses the colon-delimited output of gpg --list-secret-keys --with-colons
+func parseGPGKeys(output string) []gpgKeyInfo {
+	var keys []gpgKeyInfo
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, ":")
+		if len(fields) < 10 {
+			continue
+		}
+
+		switch fields[0] {
+		case "sec", "pub":
+			key := gpgKeyInfo{KeyID: fields[4], Validity: fields[1]}
+			if expires, err := strconv.ParseInt(fields[6], 10, 64); err == nil && expires > 0 {
+				t := time.Unix(expires, 0)
+				key.Expires = &t
+			}
+			keys = append(keys, key)
+		case "uid":
+			if len(keys) > 0 {
+				keys[len(keys)-1].UIDs = append(keys[len(keys)-1].UIDs, fields[9])
+			}
+		}
+	}
+	return keys
+}
+
+// lookupGPGKey finds a secret key in the keyring of the given gpg program
+func lookupGPGKey(program string, key string) (gpgKeyInfo, error) {
+	if program == "" {
+		program = "gpg"
+	}
+	if _, err := exec.LookPath(expandHome(program)); err != nil {
+		return gpgKeyInfo{}, errorf("%s is not installed", program)
+	}
+
+	output, err := exec.Command(expandHome(program), "--batch", "--with-colons", "--list-secret-keys", key).Output()
+	keys := parseGPGKeys(string(output))
+	if err != nil || len(keys) == 0 {
+		return gpgKeyInfo{}, errorf("signing key %s is not in the local GPG keyring", key)
+	}
+	return keys[0], nil
+}
+
+// checkGPGKey verifies that a key is usable and carries a user ID with the given email
+func checkGPGKey(info gpgKeyInfo, key string, email string) error {
+	switch {
+	case info.Validity == "r":
+		return errorf("signing key %s is revoked", key)
+	case info.Validity == "e" || (info.Expires != nil && info.Expires.Before(time.Now())):
+		return errorf("signing key %s has expired", key)
+	case info.Validity == "i" || info.Validity == "d":
+		return errorf("signing key %s is invalid or disabled", key)
+	}
+
+	for _, uid := range info.UIDs {
+		if _, uidEmail := parseIdent(uid); strings.EqualFold(uidEmail, email) {
+			return nil
+		}
+	}
+	return errorf("signing key %s has no user ID for <%s>", key, email)
+}
+
+// verifySigningKey checks a profile's OpenPGP signing key against the local keyring
+func verifySigningKey(profile Profile) error {
+	key := profile.Signing.Key
+	switch {
+	case key == "" || profile.SigningFormat() == signingFormatSSH:
+		return nil
+	case profile.SigningFormat() == signingFormatX509:
+		// S/MIME keys live in the OS certificate store; only check that the signing program exists
+		program := profile.Signing.Program
+		if program == "" {
+			program = "gpgsm"
+		}
+		if _, err := exec.LookPath(expandHome(program)); err != nil {
+			return errorf("%s is not installed", program)
+		}
+		return nil
+	}
+
+	info, err := lookupGPGKey(profile.Signing.Program, key)
+	if err != nil {
+		return err
+	}
+	return checkGPGKey(info, key, profile.Email)
+}
+
+// defaultKeyExpiryWarnDays is how many days ahead signing key expiry is warned about when the config doesn't say otherwise
+const defaultKeyExpiryWarnDays = 30
+
+// keyExpiryWarnDays returns the configured signing key expiry warning period
+func (cm *ConfigManager) keyExpiryWarnDays() int {
+	if cm.KeyExpiryWarnDays > 0 {
+		return cm.KeyExpiryWarnDays
+	}
+	return defaultKeyExpiryWarnDays
+}
+
+// describeExpiry says when a key expires, e.g. "expires in 5 days (2026-10-19)", and whether that is already the case
+// or within days of now
+func describeExpiry(info gpgKeyInfo, days int, now time.Time) (string, bool) {
+	switch {
+	case info.Validity == "e" && info.Expires == nil:
+		return tr("has expired"), true
+	case info.Expires == nil:
+		return tr("never expires"), false
+	case !info.Expires.After(now):
+		return fmt.Sprintf(tr("expired on %s"), info.Expires.Format(time.DateOnly)), true
+	}
+
+	left := int(info.Expires.Sub(now).Hours() / 24)
+	description := fmt.Sprintf(tr("expires in %d days (%s)"), left, info.Expires.Format(time.DateOnly))
+	if left == 1 {
+		description = fmt.Sprintf(tr("expires in 1 day (%s)"), info.Expires.Format(time.DateOnly))
+	} else if left == 0 {
+		description = fmt.Sprintf(tr("expires today (%s)"), info.Expires.Format(time.DateOnly))
+	}
+	return description, left < days
+}
+
+// signingKeyExpiry describes when the OpenPGP signing key of profile expires, and whether it has expired or expires
+// within the warning period; keys that can't be looked up are never reported
+func (cm *ConfigManager) signingKeyExpiry(profile Profile) (string, bool) {
+	if profile.Signing.Key == "" || profile.SigningFormat() != signingFormatOpenPGP {
+		return "", false
+	}
+	info, err := lookupGPGKey(profile.Signing.Program, profile.Signing.Key)
+	if err != nil {
+		return "", false
+	}
+	return describeExpiry(info, cm.keyExpiryWarnDays(), time.Now())
+}
+
+// checkSigningKeyFinding reports problems with the signing key of the applied profile
+func checkSigningKeyFinding(cm *ConfigManager, dir string) []doctorFinding {
+	name, found := cm.appliedProfile(dir)
+	if !found {
+		return nil
+	}
+
+	profile, err := resolveProfile(cm.Profiles[name])
+	if err == nil {
+		err = verifySigningKey(profile)
+	}
+	if err != nil {
+		return []doctorFinding{{severityWarning, fmt.Sprintf(tr("profile '%s': %v"), name, err)}}
+	}
+	return nil
+}
+
+// checkKeyExpiryFinding warns when the signing key of the applied profile expires within the warning period; keys that
+// already expired, and profiles whose placeholders can't be resolved, are reported by checkSigningKeyFinding
+func checkKeyExpiryFinding(cm *ConfigManager, dir string) []doctorFinding {
+	name, found := cm.appliedProfile(dir)
+	if !found {
+		return nil
+	}
+	profile, err := resolveProfile(cm.Profiles[name])
+	if err != nil || verifySigningKey(profile) != nil {
+		return nil
+	}
+
+	if expiry, soon := cm.signingKeyExpiry(profile); soon {
+		key := profile.Signing.Key
+		return []doctorFinding{{severityWarning, fmt.Sprintf(tr("profile '%s': signing key %s %s; extend it with 'gpg --quick-set-expire %s 1y'"), name, key, expiry, key)}}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testGPGOutput = `sec:u:255:22:ABCDEF0123456789:1700000000:4102444800::u:::scESC:::+:::23::0:
+fpr:::::::::0123456789ABCDEF0123456789ABCDEF01234567:
+uid:u::::1700000000::HASH::John Doe <john.doe@company.com>::::::::::0:
+uid:u::::1700000000::HASH::John Doe <john@oss.dev>::::::::::0:
+ssb:u:255:18:1234567890ABCDEF:1700000000::::::e:::+:::23:
+`
+
+// TestParseGPGKeys tests parsing gpg colon output
+func TestParseGPGKeys(t *testing.T) {
+	keys := parseGPGKeys(testGPGOutput)
+	assert.Len(t, keys, 1)
+	assert.Equal(t, "ABCDEF0123456789", keys[0].KeyID)
+	assert.Equal(t, "u", keys[0].Validity)
+	assert.Equal(t, int64(4102444800), keys[0].Expires.Unix())
+	assert.Equal(t, []string{"John Doe <john.doe@company.com>", "John Doe <john@oss.dev>"}, keys[0].UIDs)
+}
+
+// TestCheckGPGKey tests validating key status and user IDs
+func TestCheckGPGKey(t *testing.T) {
+	key := parseGPGKeys(testGPGOutput)[0]
+	assert.NoError(t, checkGPGKey(key, "ABCDEF01", "john@oss.dev"))
+	assert.Error(t, checkGPGKey(key, "ABCDEF01", "john.personal@gmail.com"))
+
+	revoked := key
+	revoked.Validity = "r"
+	assert.ErrorContains(t, checkGPGKey(revoked, "ABCDEF01", "john@oss.dev"), "revoked")
+
+	expired := key
+	past := time.Now().Add(-24 * time.Hour)
+	expired.Expires = &past
+	assert.ErrorContains(t, checkGPGKey(expired, "ABCDEF01", "john@oss.dev"), "expired")
+
+	// SSH signing keys are not checked against the GPG keyring
+	profile := Profile{Name: "John Doe", Email: "john@oss.dev"}
+	profile.Signing.Key = "~/.ssh/id_ed25519.pub"
+	assert.NoError(t, verifySigningKey(profile))
+}
+
+// TestDescribeExpiry tests warning about signing keys expiring soon
+func TestDescribeExpiry(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	key := parseGPGKeys(testGPGOutput)[0]
+
+	expiry, soon := describeExpiry(key, 30, now)
+	assert.Contains(t, expiry, "expires in 26741 days")
+	assert.False(t, soon)
+
+	expiring := key
+	inFive := now.Add(5*24*time.Hour + time.Hour)
+	expiring.Expires = &inFive
+	expiry, soon = describeExpiry(expiring, 30, now)
+	assert.Equal(t, "expires in 5 days (2026-10-19)", expiry)
+	assert.True(t, soon)
+	_, soon = describeExpiry(expiring, 5, now)
+	assert.False(t, soon)
+
+	expired := key
+	past := now.Add(-24 * time.Hour)
+	expired.Expires = &past
+	expiry, soon = describeExpiry(expired, 30, now)
+	assert.Equal(t, "expired on 2026-10-13", expiry)
+	assert.True(t, soon)
+
+	key.Expires = nil
+	_, soon = describeExpiry(key, 30, now)
+	assert.False(t, soon)
+
+	assert.Equal(t, defaultKeyExpiryWarnDays, (&ConfigManager{}).keyExpiryWarnDays())
+	assert.Equal(t, 7, (&ConfigManager{KeyExpiryWarnDays: 7}).keyExpiryWarnDays())
+}
+
+// TestGPGProgram tests that the signing program is applied and used for verification
+func TestGPGProgram(t *testing.T) {
+	profile := Profile{Name: "John Doe", Email: "john.doe@company.com"}
+	profile.Signing.Key = "ABCDEF0123456789"
+	profile.Signing.Program = "/opt/smartcard/gpg-wrapper"
+
+	assert.Contains(t, profileConfig("work", profile), configEntry{"gpg.program", "/opt/smartcard/gpg-wrapper"})
+	assert.ErrorContains(t, verifySigningKey(profile), "/opt/smartcard/gpg-wrapper is not installed")
+}
+
+// TestX509Signing tests the config written for S/MIME signing profiles
+func TestX509Signing(t *testing.T) {
+	profile := Profile{Name: "John Doe", Email: "john.doe@company.com"}
+	profile.Signing.Key = "0x1234ABCD"
+	profile.Signing.Format = signingFormatX509
+	profile.Signing.Program = "smimesign"
+
+	entries := profileConfig("work", profile)
+	assert.Contains(t, entries, configEntry{"gpg.format", "x509"})
+	assert.Contains(t, entries, configEntry{"gpg.x509.program", "smimesign"})
+	assert.Contains(t, entries, configEntry{"user.signingkey", "0x1234ABCD"})
+	assert.NotContains(t, entries, configEntry{"gpg.program", "smimesign"})
+
+	// Formats are detected when not set explicitly
+	profile.Signing.Format = ""
+	assert.Equal(t, signingFormatOpenPGP, profile.SigningFormat())
+	profile.Signing.Key = "~/.ssh/id_ed25519.pub"
+	assert.Equal(t, signingFormatSSH, profile.SigningFormat())
+}
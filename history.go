@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Scopes recorded in the history log
+const (
+	scopeLocal    = "local"
+	scopeGlobal   = "global"
+	scopeWorktree = "worktree"
+)
+
+// historyEntry is one line of the append-only apply history
+type historyEntry struct {
+	Time    time.Time `json:"time"`
+	Action  string    `json:"action"`
+	Profile string    `json:"profile,omitempty"`
+	Repo    string    `json:"repo,omitempty"`
+	Scope   string    `json:"scope"`
+}
+
+// historyPath returns the history log kept next to the config file, e.g. ~/.git-profiles.history.jsonl
+func (cm *ConfigManager) historyPath() string {
+	if cm.ConfigPath == "" {
+		return ""
+	}
+	return strings.TrimSuffix(cm.ConfigPath, filepath.Ext(cm.ConfigPath)) + ".history.jsonl"
+}
+
+// recordHistory appends an entry to the history log, warning instead of failing when it can't be written
+func (cm *ConfigManager) recordHistory(action string, profile string, dir string, scope string) {
+	path := cm.historyPath()
+	if path == "" {
+		return
+	}
+
+	entry := historyEntry{Time: time.Now().UTC(), Action: action, Profile: profile, Scope: scope}
+	if dir != "" {
+		if entry.Repo = repoTopLevel(dir); entry.Repo == "" {
+			entry.Repo = dir
+		}
+	}
+
+	data, err := json.Marshal(entry)
+	if err == nil {
+		var file *os.File
+		if file, err = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
+			_, err = file.Write(append(data, '\n'))
+			file.Close()
+		}
+	}
+	if err != nil {
+		warnf("History not recorded: %v", err)
+	}
+}
+
+// readHistory returns the history entries in the order they were recorded, optionally only those of repo
+func (cm *ConfigManager) readHistory(repo string) ([]historyEntry, error) {
+	file, err := os.Open(cm.historyPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []historyEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry historyEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if repo == "" || entry.Repo == repo {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// lastIdentityChange returns the latest history entry for repo
+func (cm *ConfigManager) lastIdentityChange(repo string) (historyEntry, bool) {
+	entries, err := cm.readHistory(repo)
+	if err != nil || len(entries) == 0 {
+		return historyEntry{}, false
+	}
+	return entries[len(entries)-1], true
+}
+
+// describeHistoryEntry renders a history entry as a single line
+func describeHistoryEntry(entry historyEntry) string {
+	target := entry.Repo
+	if target == "" {
+		target = entry.Scope
+	} else {
+		target = displayPath(target)
+	}
+
+	profile := entry.Profile
+	if profile == "" {
+		profile = "-"
+	}
+	return fmt.Sprintf("%s  %-7s  %-12s  %s", entry.Time.Local().Format("2006-01-02 15:04:05"), entry.Action, profile, target)
+}
+
+// newHistoryCmd builds the history command
+func newHistoryCmd(configManager *ConfigManager) *cobra.Command {
+	var repo string
+
+	var historyCmd = &cobra.Command{
+		Use:   "history",
+		Short: "Show when profiles were applied and removed",
+		Run: func(cmd *cobra.Command, args []string) {
+			filter := ""
+			if repo != "" {
+				if filter = repoTopLevel(repo); filter == "" {
+					fmt.Fprintf(stdout, tr("%s is not a Git repository.\n"), repo)
+					os.Exit(1)
+				}
+			}
+
+			entries, err := configManager.readHistory(filter)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("History failed:"), err)
+				os.Exit(1)
+			}
+			if len(entries) == 0 {
+				fmt.Fprintln(stdout, tr("No history recorded yet."))
+				return
+			}
+
+			for _, entry := range entries {
+				fmt.Fprintln(stdout, describeHistoryEntry(entry))
+			}
+		},
+	}
+	historyCmd.Flags().StringVar(&repo, "repo", "", "Only show the history of this repository")
+
+	return historyCmd
+}
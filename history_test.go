@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHistory tests recording and filtering the apply history
+func TestHistory(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	first := initTestRepo(t)
+	second := initTestRepo(t)
+	cm := &ConfigManager{ConfigPath: filepath.Join(tmpDir, ".git-profiles-test.json")}
+	assert.Equal(t, filepath.Join(tmpDir, ".git-profiles-test.history.jsonl"), cm.historyPath())
+
+	_, found := cm.lastIdentityChange(repoTopLevel(first))
+	assert.False(t, found)
+
+	cm.recordHistory("apply", "work", first, scopeLocal)
+	cm.recordHistory("apply", "personal", second, scopeLocal)
+	cm.recordHistory("unapply", "work", first, scopeLocal)
+	cm.recordHistory("install", "", "", scopeGlobal)
+
+	entries, err := cm.readHistory("")
+	assert.NoError(t, err)
+	assert.Len(t, entries, 4)
+
+	entries, err = cm.readHistory(repoTopLevel(first))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "apply", entries[0].Action)
+
+	last, found := cm.lastIdentityChange(repoTopLevel(first))
+	assert.True(t, found)
+	assert.Equal(t, "unapply", last.Action)
+	assert.Contains(t, describeHistoryEntry(last), "unapply")
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// hookMarker identifies hook scripts written by git-profile
+const hookMarker = "# Managed by git-profile"
+
+// supportedHooks lists the Git hooks git-profile knows how to install
+var supportedHooks = []string{"pre-commit", "pre-push"}
+
+// hookScript renders the shell script that delegates a Git hook to git-profile
+func hookScript(hook string) string {
+	executable, err := os.Executable()
+	if err != nil {
+		executable = "git-profile"
+	}
+
+	return fmt.Sprintf("#!/bin/sh\n%s. Remove with 'git-profile hooks uninstall'.\nexec %s hooks run %s \"$@\"\n",
+		hookMarker, shellQuote(executable), hook)
+}
+
+// hooksDir resolves the hooks directory of the repository at dir, honoring core.hooksPath
+func hooksDir(dir string) (string, error) {
+	path, err := runGit(dir, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return path, nil
+}
+
+// installHook writes a git-profile managed hook into the repository at dir
+func installHook(dir string, hook string, force bool) (string, error) {
+	hooksPath, err := hooksDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(hooksPath, 0755); err != nil {
+		return "", err
+	}
+
+	hookPath := filepath.Join(hooksPath, hook)
+	if existing, err := os.ReadFile(hookPath); err == nil && !strings.Contains(string(existing), hookMarker) && !force {
+		return "", errorf("%s already exists and is not managed by git-profile (use --force to overwrite)", hookPath)
+	}
+
+	if err := os.WriteFile(hookPath, []byte(hookScript(hook)), 0755); err != nil {
+		return "", err
+	}
+
+	return hookPath, nil
+}
+
+// uninstallHook removes a git-profile managed hook from the repository at dir
+func uninstallHook(dir string, hook string) (string, error) {
+	hooksPath, err := hooksDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	hookPath := filepath.Join(hooksPath, hook)
+	existing, err := os.ReadFile(hookPath)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.Contains(string(existing), hookMarker) {
+		return "", errorf("%s is not managed by git-profile", hookPath)
+	}
+
+	return hookPath, os.Remove(hookPath)
+}
+
+// checkIdentity verifies that email belongs to the assigned (or policy-required) profile, or to any saved profile when none is assigned
+func (cm *ConfigManager) checkIdentity(assigned string, email string) error {
+	if len(cm.Profiles) == 0 {
+		return nil
+	}
+
+	if saved, exists := cm.Profiles[assigned]; exists {
+		profile, err := resolveProfile(saved)
+		if err != nil {
+			return errorf("profile '%s': %w", assigned, err)
+		}
+		if !profile.OwnsEmail(email) {
+			return errorf("email <%s> does not match profile '%s' <%s> expected for this repository", email, assigned, profile.Email)
+		}
+		return nil
+	}
+
+	if _, found := cm.findProfileByEmail(email); found {
+		return nil
+	}
+
+	return errorf("email <%s> does not match any saved profile", email)
+}
+
+// runPreCommitHook checks the effective author identity before a commit is created
+func runPreCommitHook(cm *ConfigManager) error {
+	ident, err := runGit("", "var", "GIT_AUTHOR_IDENT")
+	if err != nil {
+		return err
+	}
+	_, email := parseIdent(ident)
+
+	expected, err := cm.expectedProfile("")
+	if err != nil {
+		return err
+	}
+	debugf("pre-commit: author <%s>, expected profile '%s'", email, expected)
+
+	return cm.checkIdentity(expected, email)
+}
+
+// outgoingCommits lists the commits a push will send, given the ref updates Git feeds to the pre-push hook
+func outgoingCommits(dir string, remote string, updates io.Reader) ([]commitIdentity, error) {
+	var commits []commitIdentity
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(updates)
+	for scanner.Scan() {
+		// Each line reads: <local ref> <local sha> <remote ref> <remote sha>
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 || isZeroSHA(fields[1]) {
+			continue
+		}
+
+		localSHA, remoteSHA := fields[1], fields[3]
+		var rangeArgs []string
+		if isZeroSHA(remoteSHA) {
+			rangeArgs = []string{localSHA, "--not", "--remotes=" + remote}
+		} else {
+			rangeArgs = []string{remoteSHA + ".." + localSHA}
+		}
+
+		refCommits, err := listCommits(dir, rangeArgs...)
+		if err != nil {
+			return nil, err
+		}
+		for _, commit := range refCommits {
+			if !seen[commit.SHA] {
+				seen[commit.SHA] = true
+				commits = append(commits, commit)
+			}
+		}
+	}
+
+	return commits, scanner.Err()
+}
+
+// checkCommits verifies the author and committer of every commit, listing the offending ones
+func (cm *ConfigManager) checkCommits(assigned string, commits []commitIdentity) error {
+	var violations []string
+	for _, commit := range commits {
+		for _, email := range []string{commit.AuthorEmail, commit.CommitterEmail} {
+			if err := cm.checkIdentity(assigned, email); err != nil {
+				violations = append(violations, fmt.Sprintf("  %s %v", commit.SHA, err))
+				break
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return errorf("%d outgoing commit(s) use the wrong identity:\n%s", len(violations), strings.Join(violations, "\n"))
+	}
+	return nil
+}
+
+// runPrePushHook checks every commit about to be pushed to remote
+func runPrePushHook(cm *ConfigManager, remote string, updates io.Reader) error {
+	expected, err := cm.expectedProfile("")
+	if err != nil {
+		return err
+	}
+
+	commits, err := outgoingCommits("", remote, updates)
+	if err != nil {
+		return err
+	}
+
+	return cm.checkCommits(expected, commits)
+}
+
+// newHooksCmd builds the hooks command group
+func newHooksCmd(configManager *ConfigManager) *cobra.Command {
+	var hooksCmd = &cobra.Command{
+		Use:   "hooks",
+		Short: "Manage Git hooks that guard commit identities",
+	}
+
+	var force bool
+	var installCmd = &cobra.Command{
+		Use:       "install [hook...]",
+		Short:     "Install identity guard hooks into the current repository",
+		ValidArgs: supportedHooks,
+		Args:      cobra.OnlyValidArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			hooks := args
+			if len(hooks) == 0 {
+				hooks = supportedHooks
+			}
+
+			for _, hook := range hooks {
+				path, err := installHook(".", hook, force)
+				if err != nil {
+					fmt.Fprintln(stdout, tr("Install failed:"), err)
+					os.Exit(1)
+				}
+				fmt.Fprintf(notices, tr("Hook '%s' installed: %s\n"), hook, path)
+			}
+		},
+	}
+	installCmd.Flags().BoolVarP(&force, "force", "f", false, "Overwrite existing hooks not managed by git-profile")
+
+	var uninstallCmd = &cobra.Command{
+		Use:       "uninstall [hook...]",
+		Short:     "Remove identity guard hooks from the current repository",
+		ValidArgs: supportedHooks,
+		Args:      cobra.OnlyValidArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			hooks := args
+			if len(hooks) == 0 {
+				hooks = supportedHooks
+			}
+
+			for _, hook := range hooks {
+				path, err := uninstallHook(".", hook)
+				if err != nil {
+					fmt.Fprintln(stdout, tr("Uninstall failed:"), err)
+					os.Exit(1)
+				}
+				if path != "" {
+					fmt.Fprintf(notices, tr("Hook '%s' removed: %s\n"), hook, path)
+				}
+			}
+		},
+	}
+
+	var runCmd = &cobra.Command{
+		Use:    "run <hook> [args...]",
+		Short:  "Run a git-profile hook (invoked by Git)",
+		Hidden: true,
+		Args:   cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			switch args[0] {
+			case "pre-commit":
+				err = runPreCommitHook(configManager)
+			case "pre-push":
+				remote := "origin"
+				if len(args) > 1 {
+					remote = args[1]
+				}
+				err = runPrePushHook(configManager, remote, os.Stdin)
+			default:
+				err = errorf("unsupported hook '%s'", args[0])
+			}
+
+			for _, finding := range checkRemoteProfile(configManager, "") {
+				fmt.Fprintf(stderr, tr("🦑 git-profile: warning: %s\n"), finding.Message)
+			}
+
+			if err != nil {
+				debugf("hook %s failed: %v", args[0], err)
+				fmt.Fprintf(stderr, "🦑 git-profile: %v\n", err)
+				if last, found := configManager.lastIdentityChange(repoTopLevel("")); found {
+					fmt.Fprintf(stderr, tr("The identity here last changed on %s (%s '%s').\n"),
+						last.Time.Local().Format("2006-01-02 15:04"), last.Action, last.Profile)
+				}
+				fmt.Fprintln(stderr, tr("Apply the right profile with 'git profile apply' or bypass with --no-verify."))
+				os.Exit(1)
+			}
+		},
+	}
+
+	hooksCmd.AddCommand(installCmd, uninstallCmd, runCmd)
+	return hooksCmd
+}
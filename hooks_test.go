@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCheckIdentity tests matching an email against assigned and known profiles
+func TestCheckIdentity(t *testing.T) {
+	cm := &ConfigManager{
+		Profiles: map[string]Profile{
+			"work":     {Name: "John Doe", Email: "john.doe@company.com"},
+			"personal": {Name: "John Personal", Email: "john.personal@gmail.com"},
+		},
+	}
+
+	// Assigned profile must match exactly
+	assert.NoError(t, cm.checkIdentity("work", "John.Doe@company.com"))
+	assert.Error(t, cm.checkIdentity("work", "john.personal@gmail.com"))
+
+	// Without an assignment any known profile is accepted
+	assert.NoError(t, cm.checkIdentity("", "john.personal@gmail.com"))
+	assert.Error(t, cm.checkIdentity("", "someone@else.com"))
+
+	// A stale assignment falls back to known profiles
+	assert.NoError(t, cm.checkIdentity("deleted", "john.doe@company.com"))
+
+	// Aliases belong to their profile
+	work := cm.Profiles["work"]
+	work.EmailAliases = []string{"john.doe@oldcorp.com"}
+	cm.Profiles["work"] = work
+	assert.NoError(t, cm.checkIdentity("work", "john.doe@oldcorp.com"))
+	assert.NoError(t, cm.checkIdentity("", "John.Doe@oldcorp.com"))
+	assert.Error(t, cm.checkIdentity("personal", "john.doe@oldcorp.com"))
+}
+
+// TestInstallHook tests installing and removing managed hooks
+func TestInstallHook(t *testing.T) {
+	repoDir := initTestRepo(t)
+
+	hookPath, err := installHook(repoDir, "pre-commit", false)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(repoDir, ".git", "hooks", "pre-commit"), hookPath)
+
+	data, err := os.ReadFile(hookPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), hookMarker)
+	assert.Contains(t, string(data), "hooks run pre-commit")
+
+	// Reinstalling a managed hook is allowed
+	_, err = installHook(repoDir, "pre-commit", false)
+	assert.NoError(t, err)
+
+	// Foreign hooks are only replaced with force
+	assert.NoError(t, os.WriteFile(hookPath, []byte("#!/bin/sh\nexit 0\n"), 0755))
+	_, err = installHook(repoDir, "pre-commit", false)
+	assert.Error(t, err)
+	_, err = uninstallHook(repoDir, "pre-commit")
+	assert.Error(t, err)
+
+	_, err = installHook(repoDir, "pre-commit", true)
+	assert.NoError(t, err)
+
+	_, err = uninstallHook(repoDir, "pre-commit")
+	assert.NoError(t, err)
+	_, err = os.Stat(hookPath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+// TestOutgoingCommits tests collecting and checking the commits of a push
+func TestOutgoingCommits(t *testing.T) {
+	repoDir := initTestRepo(t)
+	base := commitAs(t, repoDir, "John Doe", "john.doe@company.com")
+	good := commitAs(t, repoDir, "John Doe", "john.doe@company.com")
+	bad := commitAs(t, repoDir, "John Personal", "john.personal@gmail.com")
+
+	zero := strings.Repeat("0", 40)
+	updates := fmt.Sprintf("refs/heads/main %s refs/heads/main %s\nrefs/heads/gone %s refs/heads/gone %s\n", bad, base, zero, good)
+
+	commits, err := outgoingCommits(repoDir, "origin", strings.NewReader(updates))
+	assert.NoError(t, err)
+	assert.Len(t, commits, 2)
+
+	cm := &ConfigManager{
+		Profiles: map[string]Profile{
+			"work":     {Name: "John Doe", Email: "john.doe@company.com"},
+			"personal": {Name: "John Personal", Email: "john.personal@gmail.com"},
+		},
+	}
+
+	err = cm.checkCommits("work", commits)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), bad)
+	assert.NotContains(t, err.Error(), good)
+
+	assert.NoError(t, cm.checkCommits("", commits))
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// localeFiles holds a message catalog per language besides English, mapping English messages to their translation
+//
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// messages is the catalog of the active language; English, the language of the source, needs none
+var messages map[string]string
+
+// detectLocale returns the language messages are shown in: GIT_PROFILE_LANG, or else the first of the POSIX LC_ALL,
+// LC_MESSAGES and LANG that is set, reduced to its language code (de_DE.UTF-8 becomes de)
+func detectLocale() string {
+	for _, key := range []string{"GIT_PROFILE_LANG", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(key); value != "" {
+			language, _, _ := strings.Cut(value, ".")
+			language, _, _ = strings.Cut(language, "_")
+			return strings.ToLower(language)
+		}
+	}
+	return "en"
+}
+
+// loadCatalog reads the message catalog of locale, which is empty for English and languages without one
+func loadCatalog(locale string) (map[string]string, error) {
+	data, err := localeFiles.ReadFile("locales/" + locale + ".json")
+	if err != nil {
+		return nil, nil
+	}
+
+	var catalog map[string]string
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("message catalog %s: %w", locale, err)
+	}
+	return catalog, nil
+}
+
+// setLocale switches messages to the language of locale, falling back to English when it has no catalog
+func setLocale(locale string) {
+	catalog, err := loadCatalog(locale)
+	if err != nil {
+		debugf("%v", err)
+	}
+	messages = catalog
+}
+
+// tr translates an English message or format string, returning it unchanged when the active catalog has no translation
+func tr(message string) string {
+	if translated, found := messages[message]; found {
+		return translated
+	}
+	return message
+}
+
+// errorf is fmt.Errorf with a translated format
+func errorf(format string, args ...any) error {
+	return fmt.Errorf(tr(format), args...)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDetectLocale tests picking the language from the environment
+func TestDetectLocale(t *testing.T) {
+	for _, key := range []string{"GIT_PROFILE_LANG", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		t.Setenv(key, "")
+	}
+	assert.Equal(t, "en", detectLocale())
+
+	t.Setenv("LANG", "de_DE.UTF-8")
+	assert.Equal(t, "de", detectLocale())
+	t.Setenv("LC_MESSAGES", "fr_FR")
+	assert.Equal(t, "fr", detectLocale())
+	t.Setenv("GIT_PROFILE_LANG", "en")
+	assert.Equal(t, "en", detectLocale())
+}
+
+// TestTranslate tests translating messages through the active catalog
+func TestTranslate(t *testing.T) {
+	defer setLocale("en")
+
+	setLocale("de")
+	assert.Equal(t, "Profil '%s' erfolgreich hinzugefügt!\n", tr("Profile '%s' added successfully!\n"))
+	assert.EqualError(t, errorf("profile '%s' not found", "work"), "Profil 'work' nicht gefunden")
+	assert.Equal(t, "not in any catalog", tr("not in any catalog"))
+
+	// Languages without a catalog fall back to English
+	setLocale("xx")
+	assert.Equal(t, "Profile '%s' added successfully!\n", tr("Profile '%s' added successfully!\n"))
+}
+
+// TestCatalogs tests that every catalog covers the messages of the source and keeps their format verbs
+func TestCatalogs(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	assert.NoError(t, err)
+
+	// Messages are the literals passed to tr, errorf and warnf that have words to translate
+	verbs := regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+	words := regexp.MustCompile(`[a-zA-Z]`)
+	var sourceMessages []string
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		parsed, err := parser.ParseFile(fset, file, nil, 0)
+		assert.NoError(t, err)
+		ast.Inspect(parsed, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			if fun, ok := call.Fun.(*ast.Ident); !ok || (fun.Name != "tr" && fun.Name != "errorf" && fun.Name != "warnf") {
+				return true
+			}
+			if literal, ok := call.Args[0].(*ast.BasicLit); ok && literal.Kind == token.STRING {
+				message, err := strconv.Unquote(literal.Value)
+				assert.NoError(t, err)
+				if words.MatchString(verbs.ReplaceAllString(message, "")) {
+					sourceMessages = append(sourceMessages, message)
+				}
+			}
+			return true
+		})
+	}
+	assert.NotEmpty(t, sourceMessages)
+
+	locales, err := localeFiles.ReadDir("locales")
+	assert.NoError(t, err)
+	for _, entry := range locales {
+		locale := strings.TrimSuffix(entry.Name(), ".json")
+		catalog, err := loadCatalog(locale)
+		assert.NoError(t, err)
+
+		for _, message := range sourceMessages {
+			assert.Contains(t, catalog, message, "%s has no translation of %q", locale, message)
+		}
+		for message, translated := range catalog {
+			assert.Equal(t, verbs.FindAllString(message, -1), verbs.FindAllString(translated, -1), "%s: %q", locale, message)
+			assert.Equal(t, strings.HasSuffix(message, "\n"), strings.HasSuffix(translated, "\n"), "%s: %q", locale, message)
+		}
+	}
+}
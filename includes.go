@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// includeDir returns the directory holding the per-profile include files written by 'rules install'
+func includeDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "git-profile", "includes"), nil
+}
+
+// includeConditions returns the includeIf conditions selecting a rule's profile
+func includeConditions(rule Rule) []string {
+	if rule.Dir != "" {
+		dir := rule.Dir
+		if !strings.HasSuffix(dir, "/") {
+			dir += "/"
+		}
+		return []string{"gitdir:" + dir}
+	}
+
+	// hasconfig matches the raw remote URL, so cover the HTTPS, SSH and SCP-style spellings
+	host, path, _ := strings.Cut(normalizeRemoteURL(rule.Remote), "/")
+	paths := []string{path, path + ".git"}
+	if strings.HasSuffix(path, "*") {
+		paths = []string{path, path + "/**"}
+	}
+
+	var conditions []string
+	for _, prefix := range []string{"https://" + host + "/", "ssh://git@" + host + "/", "git@" + host + ":"} {
+		for _, p := range paths {
+			conditions = append(conditions, "hasconfig:remote.*.url:"+prefix+p)
+		}
+	}
+	return conditions
+}
+
+// writeIncludeFile writes the profile's config entries into a standalone gitconfig file
+func writeIncludeFile(path string, name string, profile Profile) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	profile, err := resolveProfile(profile)
+	if err != nil {
+		return err
+	}
+	if err := materializeProfileFiles(name, profile); err != nil {
+		return err
+	}
+
+	for _, entry := range profileConfig(name, profile) {
+		if _, err := runGit("", "config", "--file", path, "--add", entry.Key, entry.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// installIncludes writes include files and includeIf sections for every rule into the config selected by configArgs
+func (cm *ConfigManager) installIncludes(configArgs []string, includesPath string) (int, error) {
+	if _, err := uninstallIncludes(configArgs, includesPath); err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(includesPath, 0755); err != nil {
+		return 0, err
+	}
+
+	for _, rule := range cm.Rules {
+		if filepath.Base(rule.Profile) != rule.Profile {
+			return 0, errorf("profile name '%s' can't be used as a file name", rule.Profile)
+		}
+		profile, exists := cm.Profiles[rule.Profile]
+		if !exists {
+			return 0, errorf("rule for %s references missing profile '%s'", rule.Target(), rule.Profile)
+		}
+		if err := writeIncludeFile(filepath.Join(includesPath, rule.Profile+".gitconfig"), rule.Profile, profile); err != nil {
+			return 0, err
+		}
+	}
+
+	if !gitVersionAtLeast(2, 36) {
+		for _, rule := range cm.Rules {
+			if rule.Remote != "" {
+				warnf("Remote-based rules need Git 2.36 or newer to take effect through includeIf.")
+				break
+			}
+		}
+	}
+
+	// Later includes override earlier ones, so write rules last-to-first to let the first match win
+	count := 0
+	for i := len(cm.Rules) - 1; i >= 0; i-- {
+		rule := cm.Rules[i]
+		includePath := filepath.Join(includesPath, rule.Profile+".gitconfig")
+		for _, condition := range includeConditions(rule) {
+			args := append([]string{"config"}, configArgs...)
+			args = append(args, "--add", "includeIf."+condition+".path", includePath)
+			if _, err := runGit("", args...); err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// uninstallIncludes removes includeIf sections pointing into includesPath from the config selected by configArgs
+func uninstallIncludes(configArgs []string, includesPath string) (int, error) {
+	entries, err := gitConfigEntries("", configArgs, `^includeif\..*\.path$`)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := make(map[string]bool)
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Value, includesPath+string(filepath.Separator)) {
+			continue
+		}
+
+		// --remove-section matches the section name case-sensitively, unlike the lowercased key Git reports
+		section := "includeIf." + strings.TrimSuffix(strings.TrimPrefix(entry.Key, "includeif."), ".path")
+		if removed[section] {
+			continue
+		}
+
+		args := append([]string{"config"}, configArgs...)
+		args = append(args, "--remove-section", section)
+		if _, err := runGit("", args...); err != nil {
+			return len(removed), err
+		}
+		removed[section] = true
+	}
+
+	return len(removed), nil
+}
+
+// exportIncludes writes one gitconfig file per named profile into dir and returns includeIf stanzas loading them:
+// one per rule, first match winning, and a commented-out one to adapt for profiles no rule selects
+func (cm *ConfigManager) exportIncludes(dir string, names []string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	paths := make(map[string]string)
+	for _, name := range names {
+		if filepath.Base(name) != name {
+			return "", errorf("profile name '%s' can't be used as a file name", name)
+		}
+		path := filepath.Join(dir, name+".gitconfig")
+		if err := writeIncludeFile(path, name, cm.Profiles[name]); err != nil {
+			return "", err
+		}
+		paths[name] = path
+	}
+
+	var sb strings.Builder
+	selected := make(map[string]bool)
+	// Later includes override earlier ones, so rules go last-to-first to let the first match win
+	for i := len(cm.Rules) - 1; i >= 0; i-- {
+		rule := cm.Rules[i]
+		path, exported := paths[rule.Profile]
+		if !exported {
+			continue
+		}
+		selected[rule.Profile] = true
+		fmt.Fprintf(&sb, "# %s\n", rule.Target())
+		for _, condition := range includeConditions(rule) {
+			fmt.Fprintf(&sb, "[includeIf \"%s\"]\n\tpath = %s\n", condition, displayPath(path))
+		}
+	}
+	for _, name := range names {
+		if !selected[name] {
+			fmt.Fprintf(&sb, "# No rule selects '%s'; point it at its repositories:\n# [includeIf \"gitdir:~/path/to/%s/\"]\n# \tpath = %s\n",
+				name, name, displayPath(paths[name]))
+		}
+	}
+	return sb.String(), nil
+}
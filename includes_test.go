@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIncludeConditions tests generating includeIf conditions for rules
+func TestIncludeConditions(t *testing.T) {
+	assert.Equal(t, []string{"gitdir:~/work/"}, includeConditions(Rule{Dir: "~/work", Profile: "work"}))
+
+	conditions := includeConditions(Rule{Remote: "github.com/acme-*", Profile: "work"})
+	assert.Contains(t, conditions, "hasconfig:remote.*.url:https://github.com/acme-*/**")
+	assert.Contains(t, conditions, "hasconfig:remote.*.url:git@github.com:acme-*/**")
+
+	conditions = includeConditions(Rule{Remote: "git@github.com:acme/api.git", Profile: "work"})
+	assert.Contains(t, conditions, "hasconfig:remote.*.url:ssh://git@github.com/acme/api.git")
+}
+
+// TestInstallIncludes tests that installed includeIf sections select the profile
+func TestInstallIncludes(t *testing.T) {
+	if !gitVersionAtLeast(2, 36) {
+		t.Skip("hasconfig includes need Git 2.36")
+	}
+
+	repoDir := initTestRepo(t)
+	includesPath := filepath.Join(repoDir, "includes")
+	configArgs := []string{"--file", filepath.Join(repoDir, ".git", "config")}
+
+	cm := &ConfigManager{
+		Profiles: map[string]Profile{
+			"work": {Name: "John Doe", Email: "john.doe@company.com"},
+			"oss":  {Name: "John Doe", Email: "john@oss.dev"},
+		},
+		Rules: []Rule{
+			{Remote: "github.com/acme-*", Profile: "work"},
+			{Remote: "github.com/*", Profile: "oss"},
+		},
+	}
+
+	_, err := runGit(repoDir, "remote", "add", "origin", "git@github.com:acme-platform/api.git")
+	assert.NoError(t, err)
+
+	count, err := cm.installIncludes(configArgs, includesPath)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, count)
+	assert.FileExists(t, filepath.Join(includesPath, "work.gitconfig"))
+
+	// The first matching rule wins
+	email, err := gitConfigGet(repoDir, "user.email")
+	assert.NoError(t, err)
+	assert.Equal(t, "john.doe@company.com", email)
+
+	// Reinstalling replaces rather than duplicates sections
+	count, err = cm.installIncludes(configArgs, includesPath)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, count)
+
+	removed, err := uninstallIncludes(configArgs, includesPath)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, removed)
+
+	email, err = gitConfigGet(repoDir, "user.email")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "john.doe@company.com", email)
+
+	_, err = os.Stat(filepath.Join(includesPath, "work.gitconfig"))
+	assert.NoError(t, err)
+}
+
+// TestExportIncludes tests exporting profiles as gitconfig files with includeIf sections to load them
+func TestExportIncludes(t *testing.T) {
+	exportDir, err := os.MkdirTemp("", "git-profile-export")
+	assert.NoError(t, err)
+	defer os.RemoveAll(exportDir)
+
+	cm := &ConfigManager{
+		Profiles: map[string]Profile{
+			"work":     {Name: "John Doe", Email: "john.doe@company.com"},
+			"oss":      {Name: "John Doe", Email: "john@oss.dev"},
+			"personal": {Name: "John Personal", Email: "john.personal@gmail.com"},
+		},
+		Rules: []Rule{
+			{Dir: "~/work/", Profile: "work"},
+			{Dir: "~/oss/", Profile: "oss"},
+		},
+	}
+
+	stanzas, err := cm.exportIncludes(exportDir, []string{"oss", "personal", "work"})
+	assert.NoError(t, err)
+
+	workPath := filepath.Join(exportDir, "work.gitconfig")
+	email, err := runGit("", "config", "--file", workPath, "user.email")
+	assert.NoError(t, err)
+	assert.Equal(t, "john.doe@company.com", email)
+	assert.FileExists(t, filepath.Join(exportDir, "personal.gitconfig"))
+
+	// Rules are listed last-to-first so the first one wins
+	assert.Equal(t, "# dir ~/oss/\n[includeIf \"gitdir:~/oss/\"]\n\tpath = "+displayPath(filepath.Join(exportDir, "oss.gitconfig"))+"\n"+
+		"# dir ~/work/\n[includeIf \"gitdir:~/work/\"]\n\tpath = "+displayPath(workPath)+"\n"+
+		"# No rule selects 'personal'; point it at its repositories:\n# [includeIf \"gitdir:~/path/to/personal/\"]\n# \tpath = "+
+		displayPath(filepath.Join(exportDir, "personal.gitconfig"))+"\n", stanzas)
+}
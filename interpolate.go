@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"os/user"
+	"strings"
+	"text/template"
+)
+
+// interpolationFuncs are the placeholders available in profile values, e.g. {{hostname}} or {{env "CORP_EMAIL"}}
+var interpolationFuncs = template.FuncMap{
+	"hostname": os.Hostname,
+	"os_user": func() (string, error) {
+		current, err := user.Current()
+		if err != nil {
+			return "", err
+		}
+		return current.Username, nil
+	},
+	"env": func(name string) (string, error) {
+		value, found := os.LookupEnv(name)
+		if !found {
+			return "", errorf("environment variable %s is not set", name)
+		}
+		return value, nil
+	},
+}
+
+// interpolate resolves the placeholders in value
+func interpolate(value string) (string, error) {
+	if !strings.Contains(value, "{{") {
+		return value, nil
+	}
+
+	tmpl, err := template.New("value").Funcs(interpolationFuncs).Option("missingkey=error").Parse(value)
+	if err != nil {
+		return "", errorf("invalid placeholder in '%s': %w", value, err)
+	}
+
+	var resolved strings.Builder
+	if err := tmpl.Execute(&resolved, nil); err != nil {
+		return "", errorf("resolving '%s': %w", value, err)
+	}
+	return resolved.String(), nil
+}
+
+// resolveProfile returns a copy of profile with the placeholders of its config values resolved
+func resolveProfile(profile Profile) (Profile, error) {
+	resolved := profile
+	resolved.URLRewrites = append([]URLRewrite(nil), profile.URLRewrites...)
+	resolved.EmailAliases = append([]string(nil), profile.EmailAliases...)
+
+	fields := []*string{
+		&resolved.Name,
+		&resolved.Email,
+		&resolved.Author.Name,
+		&resolved.Author.Email,
+		&resolved.Committer.Name,
+		&resolved.Committer.Email,
+		&resolved.Signing.Key,
+		&resolved.Signing.Program,
+		&resolved.Credential.Username,
+		&resolved.Credential.Helper,
+		&resolved.SSH.Key,
+		&resolved.GitHub.User,
+		&resolved.Settings.Editor,
+		&resolved.Settings.DefaultBranch,
+		&resolved.Network.HTTPProxy,
+		&resolved.Network.HTTPSProxy,
+		&resolved.Network.SSLCAInfo,
+		&resolved.CommitTemplate.Path,
+		&resolved.Excludes.Path,
+	}
+	for i := range resolved.EmailAliases {
+		fields = append(fields, &resolved.EmailAliases[i])
+	}
+	for i := range resolved.URLRewrites {
+		fields = append(fields, &resolved.URLRewrites[i].Base, &resolved.URLRewrites[i].InsteadOf)
+	}
+
+	for _, field := range fields {
+		value, err := interpolate(*field)
+		if err != nil {
+			return Profile{}, err
+		}
+		*field = value
+	}
+	return resolved, nil
+}
+
+// resolvedIdentity returns profile with its placeholders resolved for comparing with the identity Git has, or as saved
+// when they can't be resolved, so it matches nothing
+func resolvedIdentity(profile Profile) Profile {
+	resolved, err := resolveProfile(profile)
+	if err != nil {
+		debugf("%v", err)
+		return profile
+	}
+	return resolved
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestInterpolate tests resolving placeholders in profile values
+func TestInterpolate(t *testing.T) {
+	t.Setenv("CORP_EMAIL", "john.doe@company.com")
+	hostname, err := os.Hostname()
+	assert.NoError(t, err)
+
+	value, err := interpolate(`{{env "CORP_EMAIL"}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "john.doe@company.com", value)
+
+	value, err = interpolate("John Doe ({{hostname}})")
+	assert.NoError(t, err)
+	assert.Equal(t, "John Doe ("+hostname+")", value)
+
+	value, err = interpolate("plain@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "plain@example.com", value)
+
+	_, err = interpolate(`{{env "GIT_PROFILE_UNSET_VARIABLE"}}`)
+	assert.ErrorContains(t, err, "not set")
+	_, err = interpolate("{{unknown}}")
+	assert.Error(t, err)
+}
+
+// TestApplyInterpolatedProfile tests that placeholders are resolved when a profile is applied
+func TestApplyInterpolatedProfile(t *testing.T) {
+	t.Setenv("CORP_EMAIL", "john.doe@company.com")
+	repoDir := initTestRepo(t)
+
+	work := Profile{Name: "John Doe", Email: `{{env "CORP_EMAIL"}}`}
+	assert.NoError(t, applyProfile(repoDir, "work", work))
+
+	email, err := gitConfigGet(repoDir, "user.email")
+	assert.NoError(t, err)
+	assert.Equal(t, "john.doe@company.com", email)
+
+	// The stored profile keeps its placeholder
+	assert.Equal(t, `{{env "CORP_EMAIL"}}`, work.Email)
+}
+
+// TestInterpolatedIdentity tests that identity checks compare the resolved values of a profile with placeholders
+func TestInterpolatedIdentity(t *testing.T) {
+	t.Setenv("CORP_EMAIL", "john.doe@company.com")
+	repoDir := initTestRepo(t)
+	_, err := runGit(repoDir, "remote", "add", "origin", "git@github.com:acme/api.git")
+	assert.NoError(t, err)
+
+	work := Profile{Name: "John Doe", Email: `{{env "CORP_EMAIL"}}`}
+	cm := &ConfigManager{
+		Profiles: map[string]Profile{"work": work},
+		Rules:    []Rule{{Remote: "github.com/acme/*", Profile: "work"}},
+	}
+	assert.NoError(t, applyProfile(repoDir, "work", work))
+
+	assert.NoError(t, cm.checkIdentity("work", "john.doe@company.com"))
+	assert.NoError(t, cm.checkIdentity("", "john.doe@company.com"))
+	name, found := cm.findProfileByEmail("john.doe@company.com")
+	assert.True(t, found)
+	assert.Equal(t, "work", name)
+	active, _ := cm.activeProfiles(repoDir, "John Doe", "john.doe@company.com")
+	assert.Equal(t, []string{"work"}, active)
+	assert.NoError(t, cm.checkPolicy(repoDir))
+
+	pending, total, err := cm.pendingChanges(repoDir, "work")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Empty(t, pending)
+
+	// Aliases and the keys checked or written elsewhere are resolved too
+	t.Setenv("OLD_EMAIL", "john.doe@oldcorp.com")
+	work.EmailAliases = []string{`{{env "OLD_EMAIL"}}`}
+	work.Signing.Key = "key::ssh-ed25519 AAAAC3NzaWork"
+	work.Signing.Format = signingFormatSSH
+	cm.Profiles["work"] = work
+	name, found = cm.findProfileByEmail("john.doe@oldcorp.com")
+	assert.True(t, found)
+	assert.Equal(t, "work", name)
+	signers, errs := cm.allowedSigners()
+	assert.Empty(t, errs)
+	assert.Equal(t, `john.doe@company.com namespaces="git" ssh-ed25519 AAAAC3NzaWork work`, signers)
+
+	// Without the variable the profile matches nothing, and the hook says why
+	os.Unsetenv("CORP_EMAIL")
+	assert.ErrorContains(t, cm.checkIdentity("work", "john.doe@company.com"), "CORP_EMAIL")
+	_, found = cm.findProfileByEmail("john.doe@company.com")
+	assert.False(t, found)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// checkUnlocked refuses to action locked profiles among names, unless force is set
+func (cm *ConfigManager) checkUnlocked(action string, force bool, names ...string) error {
+	if force {
+		return nil
+	}
+
+	var locked []string
+	for _, name := range names {
+		if cm.Profiles[name].Locked {
+			locked = append(locked, name)
+		}
+	}
+	if len(locked) == 0 {
+		return nil
+	}
+	sort.Strings(locked)
+	return errorf("profile '%s' is locked; unlock it with 'git profile unlock' or pass --force to %s it anyway", strings.Join(locked, "', '"), action)
+}
+
+// setLocked locks or unlocks profiles, returning how many changed
+func (cm *ConfigManager) setLocked(names []string, locked bool) (int, error) {
+	for _, name := range names {
+		if _, exists := cm.Profiles[name]; !exists {
+			return 0, errorf("profile '%s' not found", name)
+		}
+		if source, shared := cm.sourceOf(name); shared {
+			return 0, errorf("profile '%s' comes from %s; set \"locked\" there instead", name, source)
+		}
+	}
+
+	changed := 0
+	for _, name := range names {
+		profile := cm.Profiles[name]
+		if profile.Locked != locked {
+			profile.Locked = locked
+			cm.Profiles[name] = profile
+			changed++
+		}
+	}
+	if changed > 0 {
+		cm.save()
+	}
+	return changed, nil
+}
+
+// newLockCmd builds the lock command
+func newLockCmd(configManager *ConfigManager) *cobra.Command {
+	var lockCmd = &cobra.Command{
+		Use:   "lock <profile>...",
+		Short: "Protect profiles from edit, rm and being overwritten by import",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if _, err := configManager.setLocked(args, true); err != nil {
+				fmt.Fprintln(stdout, tr("Locking failed:"), err)
+				os.Exit(1)
+			}
+			for _, name := range args {
+				fmt.Fprintf(notices, tr("🔒 Profile '%s' locked.\n"), name)
+			}
+		},
+	}
+
+	return lockCmd
+}
+
+// newUnlockCmd builds the unlock command
+func newUnlockCmd(configManager *ConfigManager) *cobra.Command {
+	var unlockCmd = &cobra.Command{
+		Use:   "unlock <profile>...",
+		Short: "Allow locked profiles to be edited and removed again",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if _, err := configManager.setLocked(args, false); err != nil {
+				fmt.Fprintln(stdout, tr("Unlocking failed:"), err)
+				os.Exit(1)
+			}
+			for _, name := range args {
+				fmt.Fprintf(notices, tr("🔓 Profile '%s' unlocked.\n"), name)
+			}
+		},
+	}
+
+	return unlockCmd
+}
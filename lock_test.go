@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLockedProfiles tests that locked profiles refuse changes unless forced
+func TestLockedProfiles(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	cm := &ConfigManager{ConfigPath: filepath.Join(tmpDir, ".git-profiles-test.json"), Profiles: map[string]Profile{
+		"corp":     {Name: "John Doe", Email: "john.doe@company.com"},
+		"personal": {Name: "John Doe", Email: "john@personal.dev"},
+	}}
+
+	changed, err := cm.setLocked([]string{"corp"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, changed)
+	assert.True(t, cm.Profiles["corp"].Locked)
+	_, err = cm.setLocked([]string{"missing"}, true)
+	assert.ErrorContains(t, err, "not found")
+
+	assert.ErrorContains(t, cm.checkUnlocked("remove", false, "personal", "corp"), "profile 'corp' is locked")
+	assert.NoError(t, cm.checkUnlocked("remove", true, "corp"))
+	assert.NoError(t, cm.checkUnlocked("edit", false, "personal"))
+
+	// A replacing import may not overwrite or drop the locked profile
+	importPath := filepath.Join(tmpDir, "import.json")
+	assert.NoError(t, os.WriteFile(importPath, []byte(`{"oss": {"name": "John Doe", "email": "john@oss.dev"}}`), 0644))
+	assert.ErrorContains(t, cm.Import(importPath, importOptions{Strategy: importReplace}), "profile 'corp' is locked")
+	assert.Contains(t, cm.Profiles, "corp")
+	assert.NoError(t, cm.Import(importPath, importOptions{Strategy: importMerge}))
+	assert.Contains(t, cm.Profiles, "oss")
+	assert.NoError(t, cm.Import(importPath, importOptions{Strategy: importReplace, Force: true}))
+	assert.NotContains(t, cm.Profiles, "corp")
+
+	changed, err = cm.setLocked([]string{"oss"}, false)
+	assert.NoError(t, err)
+	assert.Zero(t, changed)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// logOptions holds the global flags controlling logging
+type logOptions struct {
+	Verbosity int
+	File      string
+}
+
+var (
+	// logMode holds the global logging flags
+	logMode logOptions
+
+	// logger receives diagnostics: warnings by default, more with -v, everything in the --log-file
+	logger = slog.New(&consoleHandler{w: stderr, level: slog.LevelWarn})
+)
+
+// consoleHandler prints log records for people: warnings and errors with their glyph, others prefixed by level
+type consoleHandler struct {
+	w     io.Writer
+	level slog.Level
+	attrs []slog.Attr
+}
+
+func (h *consoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *consoleHandler) Handle(_ context.Context, record slog.Record) error {
+	var sb strings.Builder
+	switch {
+	case record.Level >= slog.LevelError:
+		sb.WriteString("❌ ")
+	case record.Level >= slog.LevelWarn:
+		sb.WriteString("⚠️  ")
+	case record.Level >= slog.LevelInfo:
+	default:
+		sb.WriteString("debug: ")
+	}
+	sb.WriteString(record.Message)
+
+	writeAttr := func(attr slog.Attr) bool {
+		fmt.Fprintf(&sb, " %s=%v", attr.Key, attr.Value)
+		return true
+	}
+	for _, attr := range h.attrs {
+		writeAttr(attr)
+	}
+	record.Attrs(writeAttr)
+
+	sb.WriteString("\n")
+	_, err := io.WriteString(h.w, sb.String())
+	return err
+}
+
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &consoleHandler{w: h.w, level: h.level, attrs: append(append([]slog.Attr{}, h.attrs...), attrs...)}
+}
+
+func (h *consoleHandler) WithGroup(string) slog.Handler {
+	return h
+}
+
+// teeHandler sends each record to every handler that wants it
+type teeHandler []slog.Handler
+
+func (t teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range t {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t teeHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+	for _, handler := range t {
+		if handler.Enabled(ctx, record.Level) {
+			errs = append(errs, handler.Handle(ctx, record.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (t teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for i, handler := range t {
+		handlers[i] = handler.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for i, handler := range t {
+		handlers[i] = handler.WithGroup(name)
+	}
+	return handlers
+}
+
+// verbosityLevel maps the number of -v flags to the lowest level shown on the console
+func verbosityLevel(verbosity int) slog.Level {
+	switch {
+	case verbosity >= 2:
+		return slog.LevelDebug
+	case verbosity == 1:
+		return slog.LevelInfo
+	}
+	return slog.LevelWarn
+}
+
+// setupLogging builds the logger from the flags; the log file, when set, records everything at debug level
+func setupLogging(options logOptions) error {
+	handlers := teeHandler{&consoleHandler{w: stderr, level: verbosityLevel(options.Verbosity)}}
+
+	if options.File != "" {
+		file, err := os.OpenFile(expandHome(options.File), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return errorf("opening log file: %w", err)
+		}
+		handlers = append(handlers, slog.NewTextHandler(file, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	}
+
+	logger = slog.New(handlers)
+	return nil
+}
+
+// debugf logs a formatted debug message
+func debugf(format string, args ...any) {
+	logger.Debug(fmt.Sprintf(format, args...))
+}
+
+// infof logs a formatted informational message
+func infof(format string, args ...any) {
+	logger.Info(fmt.Sprintf(format, args...))
+}
+
+// warnf logs a formatted warning
+func warnf(format string, args ...any) {
+	logger.Warn(fmt.Sprintf(tr(format), args...))
+}
+
+// fatal logs err and exits, for failures the program can't continue from
+func fatal(err error) {
+	logger.Error(err.Error())
+	os.Exit(1)
+}
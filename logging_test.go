@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConsoleLogging tests the console rendering and the levels raised by -v
+func TestConsoleLogging(t *testing.T) {
+	var buf bytes.Buffer
+	console := slog.New(&consoleHandler{w: &buf, level: verbosityLevel(0)})
+	console.Info("applying")
+	console.Warn("signing key expired", "profile", "work")
+	console.Error("config unreadable")
+	assert.Equal(t, "⚠️  signing key expired profile=work\n❌ config unreadable\n", buf.String())
+
+	buf.Reset()
+	console = slog.New(&consoleHandler{w: &buf, level: verbosityLevel(2)})
+	console.Debug("setting user.email")
+	console.Info("applying")
+	assert.Equal(t, "debug: setting user.email\napplying\n", buf.String())
+
+	assert.Equal(t, slog.LevelInfo, verbosityLevel(1))
+}
+
+// TestLogFile tests that --log-file records debug logs while the console stays quiet
+func TestLogFile(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	defer func(original *slog.Logger) { logger = original }(logger)
+
+	logPath := filepath.Join(tmpDir, "git-profile.log")
+	assert.NoError(t, setupLogging(logOptions{File: logPath}))
+	debugf("setting %s=%s", "user.email", "john.doe@company.com")
+	infof("Applying profile '%s'", "work")
+
+	data, err := os.ReadFile(logPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `level=DEBUG msg="setting user.email=john.doe@company.com"`)
+	assert.Contains(t, string(data), `level=INFO msg="Applying profile 'work'"`)
+
+	assert.Error(t, setupLogging(logOptions{File: filepath.Join(tmpDir, "missing", "git-profile.log")}))
+}
@@ -4,11 +4,16 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
-	"log"
+	"io"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
@@ -22,24 +27,221 @@ var (
 
 // Profile represents a Git profile with name, email, and optional additional config
 type Profile struct {
-	Name    string `json:"name"`
-	Email   string `json:"email"`
-	Signing struct {
-		Key string `json:"key,omitempty"`
+	Name         string   `json:"name"`
+	Email        string   `json:"email"`
+	EmailAliases []string `json:"email_aliases,omitempty"`
+	Author       Identity `json:"author,omitempty"`
+	Committer    Identity `json:"committer,omitempty"`
+	Signing      struct {
+		Key     string `json:"key,omitempty"`
+		Format  string `json:"format,omitempty"`
+		Program string `json:"program,omitempty"`
+		Commits bool   `json:"commits,omitempty"`
+		Tags    bool   `json:"tags,omitempty"`
 	} `json:"signing,omitempty"`
+	Credential struct {
+		Username string `json:"username,omitempty"`
+		Helper   string `json:"helper,omitempty"`
+	} `json:"credential,omitempty"`
+	SSH struct {
+		Key string `json:"key,omitempty"`
+	} `json:"ssh,omitempty"`
+	GitHub struct {
+		Host string `json:"host,omitempty"`
+		User string `json:"user,omitempty"`
+	} `json:"github,omitempty"`
+	Settings struct {
+		Editor        string `json:"editor,omitempty"`
+		DefaultBranch string `json:"default_branch,omitempty"`
+		PullRebase    string `json:"pull_rebase,omitempty"`
+	} `json:"settings,omitempty"`
+	Network struct {
+		HTTPProxy  string `json:"http_proxy,omitempty"`
+		HTTPSProxy string `json:"https_proxy,omitempty"`
+		SSLCAInfo  string `json:"ssl_ca_info,omitempty"`
+	} `json:"network,omitempty"`
+	CommitTemplate ProfileFile       `json:"commit_template,omitempty"`
+	Excludes       ProfileFile       `json:"excludes,omitempty"`
+	Host           string            `json:"host,omitempty"`
+	Workspace      string            `json:"workspace,omitempty"`
+	Secrets        map[string]string `json:"secrets,omitempty"`
+	URLRewrites    []URLRewrite      `json:"url_rewrites,omitempty"`
+	Remotes        []string          `json:"remotes,omitempty"`
+	Tags           []string          `json:"tags,omitempty"`
+	Hooks          ApplyHooks        `json:"hooks,omitempty"`
+	Locked         bool              `json:"locked,omitempty"`
+	Archived       bool              `json:"archived,omitempty"`
+
+	Created  *time.Time `json:"created,omitempty"`
+	Updated  *time.Time `json:"updated,omitempty"`
+	LastUsed *time.Time `json:"last_used,omitempty"`
+}
+
+// Identity overrides the profile's name and email for the author or committer of commits; empty fields fall back to them
+type Identity struct {
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// IsSet reports whether the identity overrides anything
+func (i Identity) IsSet() bool {
+	return i.Name != "" || i.Email != ""
+}
+
+// URLRewrite is a url.<base>.insteadOf rule, routing URLs starting with InsteadOf through Base
+type URLRewrite struct {
+	Base      string `json:"base"`
+	InsteadOf string `json:"instead_of"`
+}
+
+// Signing formats understood by Git's gpg.format
+const (
+	signingFormatOpenPGP = "openpgp"
+	signingFormatSSH     = "ssh"
+	signingFormatX509    = "x509"
+)
+
+// SigningFormat returns the configured signing format, detecting SSH keys when none is set
+func (p Profile) SigningFormat() string {
+	switch {
+	case p.Signing.Format != "":
+		return p.Signing.Format
+	case isSSHSigningKey(p.Signing.Key):
+		return signingFormatSSH
+	}
+	return signingFormatOpenPGP
+}
+
+// Proxy returns the proxy apply writes as http.proxy: the HTTPS proxy, or the HTTP one when there is none; add, edit
+// and import refuse profiles setting both
+func (p Profile) Proxy() string {
+	if p.Network.HTTPSProxy != "" {
+		return p.Network.HTTPSProxy
+	}
+	return p.Network.HTTPProxy
+}
+
+// AuthorIdentity returns the name and email commits are authored with
+func (p Profile) AuthorIdentity() Identity {
+	return p.withDefaults(p.Author)
+}
+
+// CommitterIdentity returns the name and email commits are committed with
+func (p Profile) CommitterIdentity() Identity {
+	return p.withDefaults(p.Committer)
+}
+
+// withDefaults fills the empty fields of an identity override from the profile
+func (p Profile) withDefaults(identity Identity) Identity {
+	if identity.Name == "" {
+		identity.Name = p.Name
+	}
+	if identity.Email == "" {
+		identity.Email = p.Email
+	}
+	return identity
+}
+
+// OwnsEmail reports whether email is the profile's email, its author or committer email, or one of its aliases, ignoring case
+func (p Profile) OwnsEmail(email string) bool {
+	for _, own := range []string{p.Email, p.Author.Email, p.Committer.Email} {
+		if own != "" && strings.EqualFold(own, email) {
+			return true
+		}
+	}
+	for _, alias := range p.EmailAliases {
+		if strings.EqualFold(alias, email) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasTag reports whether the profile carries tag, ignoring case
+func (p Profile) HasTag(tag string) bool {
+	for _, t := range p.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
 }
 
 // ConfigManager handles loading and saving profiles
 type ConfigManager struct {
 	ConfigPath string
 	Profiles   map[string]Profile
+	Rules      []Rule
+	Repos      map[string]RegisteredRepo
+	Templates  map[string]Profile
+	Sources    []string
+	BackupKeep int
+
+	// KeyExpiryWarnDays is how many days ahead of its expiry a signing key is warned about
+	KeyExpiryWarnDays int
+
+	// Hooks run around every apply, before the applied profile's own hooks
+	Hooks ApplyHooks
+
+	// FragmentsDir holds extra profile files, each profile being saved back to the file it came from
+	FragmentsDir string
+
+	// Picker chooses how profiles are selected interactively: auto, fzf or prompt
+	Picker string
+
+	// sourced holds the profiles merged in from Sources, which aren't saved locally
+	sourced map[string]sourcedProfile
+
+	// fragments and fragmentOwner track the files of FragmentsDir and which profiles they own
+	fragments     []profileFragment
+	fragmentOwner map[string]string
+
+	// loaded and loadedHash are the config file as this process last read or wrote it, to notice changes made meanwhile
+	loaded     configFile
+	loadedHash string
+}
+
+// configFile is the on-disk layout of the config file
+type configFile struct {
+	Version    int                       `json:"version,omitempty"`
+	Profiles   map[string]Profile        `json:"profiles"`
+	Rules      []Rule                    `json:"rules,omitempty"`
+	Repos      map[string]RegisteredRepo `json:"repos,omitempty"`
+	Templates  map[string]Profile        `json:"templates,omitempty"`
+	Sources    []string                  `json:"sources,omitempty"`
+	BackupKeep int                       `json:"backup_keep,omitempty"`
+	Hooks      ApplyHooks                `json:"hooks,omitempty"`
+
+	KeyExpiryWarnDays int `json:"key_expiry_warn_days,omitempty"`
+
+	// migratedFrom is the version the file had before it was upgraded on load
+	migratedFrom int
+}
+
+// parseConfig decodes a config file of any version, migrating older layouts to the current one
+func parseConfig(data []byte) (configFile, error) {
+	migrated, version, err := migrateConfig(data)
+	if err != nil {
+		return configFile{}, err
+	}
+
+	var config configFile
+	if err := decodeStrict(migrated, &config); err != nil {
+		return configFile{}, errorf("invalid config: %w", err)
+	}
+	config.migratedFrom = version
+
+	if config.Profiles == nil {
+		config.Profiles = make(map[string]Profile)
+	}
+	return config, nil
 }
 
 // NewConfigManager creates a new config manager
 func NewConfigManager() *ConfigManager {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		log.Fatal(err)
+		fatal(err)
 	}
 
 	configPath := filepath.Join(homeDir, ".git-profiles.json")
@@ -48,6 +250,9 @@ func NewConfigManager() *ConfigManager {
 		ConfigPath: configPath,
 		Profiles:   make(map[string]Profile),
 	}
+	if fragmentsDir, err := defaultFragmentsDir(); err == nil {
+		cm.FragmentsDir = fragmentsDir
+	}
 
 	cm.load()
 	return cm
@@ -56,43 +261,179 @@ func NewConfigManager() *ConfigManager {
 // load reads existing profiles from config file
 func (cm *ConfigManager) load() {
 	if _, err := os.Stat(cm.ConfigPath); os.IsNotExist(err) {
+		cm.mergeFragments()
 		return
 	}
 
 	data, err := os.ReadFile(cm.ConfigPath)
 	if err != nil {
-		log.Fatal(err)
+		fatal(err)
 	}
+	cm.remember(data)
 
 	if len(data) > 0 {
-		if err := json.Unmarshal(data, &cm.Profiles); err != nil {
-			log.Fatal(err)
+		config, err := parseConfig(data)
+		if err != nil {
+			// Point hand-edited files at the exact problems
+			if problems, _ := validateConfigData(data); len(problems) > 0 {
+				for _, problem := range problems {
+					fmt.Fprintf(stdout, "❌ %s: %v\n", cm.ConfigPath, problem)
+				}
+				os.Exit(1)
+			}
+			fatal(err)
+		}
+		cm.Profiles = config.Profiles
+		cm.Rules = config.Rules
+		cm.Repos = config.Repos
+		cm.Templates = config.Templates
+		cm.Sources = config.Sources
+		cm.BackupKeep = config.BackupKeep
+		cm.KeyExpiryWarnDays = config.KeyExpiryWarnDays
+		cm.Hooks = config.Hooks
+
+		// Rewrite files from older versions once, keeping the original as a backup
+		if config.migratedFrom < currentConfigVersion {
+			cm.backupBefore("migrate")
+			cm.save()
 		}
 	}
+
+	cm.mergeFragments()
+	cm.mergeSources()
 }
 
 // save writes profiles to config file
 func (cm *ConfigManager) save() {
-	data, err := json.MarshalIndent(cm.Profiles, "", "  ")
+	config := configFile{
+		Version:    currentConfigVersion,
+		Profiles:   cm.localProfiles(),
+		Rules:      cm.Rules,
+		Repos:      cm.Repos,
+		Templates:  cm.Templates,
+		Sources:    cm.Sources,
+		BackupKeep: cm.BackupKeep,
+		Hooks:      cm.Hooks,
+
+		KeyExpiryWarnDays: cm.KeyExpiryWarnDays,
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
-		log.Fatal(err)
+		fatal(err)
 	}
 
+	// Another process may have saved since this one loaded; build on its changes instead of overwriting them
+	if data, err = cm.mergeConcurrentChanges(data); err != nil {
+		fatal(err)
+	}
 	if err := os.WriteFile(cm.ConfigPath, data, 0644); err != nil {
-		log.Fatal(err)
+		fatal(err)
 	}
+	cm.remember(data)
+	cm.saveFragments()
+}
+
+// findProfileByEmail returns the name of the first profile (alphabetically) using email, preferring profiles where it
+// is the main email over those listing it as an alias
+func (cm *ConfigManager) findProfileByEmail(email string) (string, bool) {
+	var names, aliased []string
+	for name, saved := range cm.Profiles {
+		profile := resolvedIdentity(saved)
+		switch {
+		case strings.EqualFold(profile.Email, email):
+			names = append(names, name)
+		case profile.OwnsEmail(email):
+			aliased = append(aliased, name)
+		}
+	}
+
+	if len(names) == 0 {
+		names = aliased
+	}
+	if len(names) == 0 {
+		return "", false
+	}
+	sort.Strings(names)
+	return names[0], true
+}
+
+// markUsed records that a profile was just applied
+func (cm *ConfigManager) markUsed(name string) {
+	profile, exists := cm.Profiles[name]
+	if !exists {
+		return
+	}
+
+	now := time.Now()
+	profile.LastUsed = &now
+	cm.Profiles[name] = profile
+	cm.save()
+}
+
+// sortByRecentUse orders profile names most recently used first, then alphabetically
+func (cm *ConfigManager) sortByRecentUse(names []string) {
+	sort.Slice(names, func(i, j int) bool {
+		left, right := cm.Profiles[names[i]].LastUsed, cm.Profiles[names[j]].LastUsed
+		switch {
+		case left != nil && right != nil && !left.Equal(*right):
+			return left.After(*right)
+		case (left == nil) != (right == nil):
+			return left != nil
+		}
+		return names[i] < names[j]
+	})
+}
+
+// Orders accepted by ls --sort
+const (
+	sortByName     = "name"
+	sortByEmail    = "email"
+	sortByLastUsed = "last-used"
+)
+
+// sortProfiles orders profile names by name, email or most recent use, breaking ties by name
+func (cm *ConfigManager) sortProfiles(names []string, by string) error {
+	switch by {
+	case sortByName, "":
+		sort.Strings(names)
+	case sortByEmail:
+		sort.Slice(names, func(i, j int) bool {
+			left, right := strings.ToLower(cm.Profiles[names[i]].Email), strings.ToLower(cm.Profiles[names[j]].Email)
+			if left != right {
+				return left < right
+			}
+			return names[i] < names[j]
+		})
+	case sortByLastUsed:
+		cm.sortByRecentUse(names)
+	default:
+		return errorf("unknown sort order '%s' (use %s, %s or %s)", by, sortByName, sortByEmail, sortByLastUsed)
+	}
+	return nil
+}
+
+// formatTime renders an optional timestamp for display
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return tr("never")
+	}
+	return t.Local().Format("2006-01-02 15:04")
 }
 
 // interactiveProfileInput prompts user for profile details
 func interactiveProfileInput(existing *Profile) Profile {
 	reader := bufio.NewReader(os.Stdin)
 	profile := Profile{}
+	if existing != nil {
+		profile = *existing
+	}
 
 	// Name input
 	if existing != nil && existing.Name != "" {
-		fmt.Printf("\nEnter name [current: %s, press Enter to keep]: ", existing.Name)
+		fmt.Fprintf(stdout, tr("\nEnter name [current: %s, press Enter to keep]: "), existing.Name)
 	} else {
-		fmt.Print("Enter name: ")
+		fmt.Fprint(stdout, tr("Enter name: "))
 	}
 	name, _ := reader.ReadString('\n')
 	name = strings.TrimSpace(name)
@@ -104,9 +445,9 @@ func interactiveProfileInput(existing *Profile) Profile {
 
 	// Email input
 	if existing != nil && existing.Email != "" {
-		fmt.Printf("Enter email [current: %s, press Enter to keep]: ", existing.Email)
+		fmt.Fprintf(stdout, tr("Enter email [current: %s, press Enter to keep]: "), existing.Email)
 	} else {
-		fmt.Print("Enter email: ")
+		fmt.Fprint(stdout, tr("Enter email: "))
 	}
 	email, _ := reader.ReadString('\n')
 	email = strings.TrimSpace(email)
@@ -116,8 +457,27 @@ func interactiveProfileInput(existing *Profile) Profile {
 		profile.Email = email
 	}
 
+	// Optional other emails the same person commits with
+	if existing != nil && len(existing.EmailAliases) > 0 {
+		fmt.Fprintf(stdout, tr("Enter email aliases, comma-separated [current: %s, press Enter to keep]: "), strings.Join(existing.EmailAliases, ", "))
+	} else {
+		fmt.Fprint(stdout, tr("Enter email aliases, comma-separated (optional, e.g. an old corporate or noreply address): "))
+	}
+	aliases, _ := reader.ReadString('\n')
+	if aliasList := splitList(aliases); len(aliasList) > 0 {
+		profile.EmailAliases = aliasList
+	} else if existing != nil {
+		profile.EmailAliases = existing.EmailAliases
+	}
+
+	// Optional separate author or committer, e.g. committing on behalf of someone or as a bot
+	profile.Author.Name = promptString(reader, tr("author name, if commits are authored as someone else"), profile.Author.Name)
+	profile.Author.Email = promptValidated(reader, tr("author email"), profile.Author.Email, validateEmail)
+	profile.Committer.Name = promptString(reader, tr("committer name, if commits are committed as someone else (e.g. a bot)"), profile.Committer.Name)
+	profile.Committer.Email = promptValidated(reader, tr("committer email"), profile.Committer.Email, validateEmail)
+
 	// Optional signing key
-	fmt.Print("Enter signing key (optional, press Enter to skip): ")
+	fmt.Fprint(stdout, tr("Enter signing key (optional, press Enter to skip): "))
 	signingKey, _ := reader.ReadString('\n')
 	signingKey = strings.TrimSpace(signingKey)
 	if signingKey != "" {
@@ -126,13 +486,298 @@ func interactiveProfileInput(existing *Profile) Profile {
 		profile.Signing.Key = existing.Signing.Key
 	}
 
+	// Signing format, detected from the key unless set explicitly
+	if profile.Signing.Key != "" {
+		fmt.Fprintf(stdout, tr("Enter signing format: openpgp, ssh or x509 [current: %s, press Enter to keep]: "), profile.SigningFormat())
+		format, _ := reader.ReadString('\n')
+		switch format = strings.ToLower(strings.TrimSpace(format)); format {
+		case signingFormatOpenPGP, signingFormatSSH, signingFormatX509:
+			profile.Signing.Format = format
+		}
+		if profile.Signing.Format == signingFormatOpenPGP && !isSSHSigningKey(profile.Signing.Key) {
+			profile.Signing.Format = ""
+		}
+	} else {
+		profile.Signing.Format = ""
+	}
+
+	// Optional signing program for OpenPGP (e.g. a smartcard wrapper) or S/MIME (e.g. smimesign) keys
+	if profile.Signing.Key != "" && profile.SigningFormat() != signingFormatSSH {
+		label := tr("gpg program")
+		if profile.SigningFormat() == signingFormatX509 {
+			label = tr("x509 program (e.g. smimesign)")
+		}
+		if profile.Signing.Program != "" {
+			fmt.Fprintf(stdout, tr("Enter %s [current: %s, enter - to use the default]: "), label, profile.Signing.Program)
+		} else {
+			fmt.Fprintf(stdout, tr("Enter %s (optional, press Enter to use the default): "), label)
+		}
+		program, _ := reader.ReadString('\n')
+		switch program = strings.TrimSpace(program); program {
+		case "":
+		case "-":
+			profile.Signing.Program = ""
+		default:
+			profile.Signing.Program = program
+		}
+	} else {
+		profile.Signing.Program = ""
+	}
+
+	// Signing toggles only make sense with a key
+	if profile.Signing.Key != "" {
+		profile.Signing.Commits = promptBool(reader, tr("Sign commits by default?"), profile.Signing.Commits)
+		profile.Signing.Tags = promptBool(reader, tr("Sign tags by default?"), profile.Signing.Tags)
+	} else {
+		profile.Signing.Commits, profile.Signing.Tags = false, false
+	}
+
+	// Optional forge host and workspace, used to detect the profile from remote URLs
+	profile.Host = strings.ToLower(promptString(reader, tr("forge host (e.g. github.com, gitlab.com, bitbucket.org)"), profile.Host))
+	if profile.Host != "" {
+		profile.Workspace = promptString(reader, tr("workspace, group or organization on the host"), profile.Workspace)
+	} else {
+		profile.Workspace = ""
+	}
+
+	// Optional SSH key, used for the profile's ~/.ssh/config host alias
+	profile.SSH.Key = promptString(reader, tr("SSH key file for the host alias (e.g. ~/.ssh/id_ed25519_work)"), profile.SSH.Key)
+
+	// Optional HTTPS credentials, so pushes authenticate as the profile's account
+	profile.Credential.Username = promptString(reader, tr("credential username"), profile.Credential.Username)
+	profile.Credential.Helper = promptString(reader, tr("credential helper (e.g. osxkeychain, store)"), profile.Credential.Helper)
+
+	// Optional GitHub CLI account switched to on apply
+	profile.GitHub.User = promptString(reader, tr("GitHub account login for gh"), profile.GitHub.User)
+	if profile.GitHub.User != "" {
+		profile.GitHub.Host = promptString(reader, tr("GitHub host (for GitHub Enterprise, default github.com)"), profile.GitHub.Host)
+	} else {
+		profile.GitHub.Host = ""
+	}
+
+	// Optional url.<base>.insteadOf rewrites, e.g. routing clones through an SSH host alias
+	if existing != nil && len(existing.URLRewrites) > 0 {
+		fmt.Fprintf(stdout, tr("Enter URL rewrites as base=prefix, comma-separated [current: %s, press Enter to keep]: "), formatURLRewrites(existing.URLRewrites))
+	} else {
+		fmt.Fprint(stdout, tr("Enter URL rewrites as base=prefix, comma-separated (optional, e.g. git@github.com-work:=git@github.com:acme/): "))
+	}
+	rewrites, _ := reader.ReadString('\n')
+	if rules := parseURLRewrites(rewrites); len(rules) > 0 {
+		profile.URLRewrites = rules
+	} else if existing != nil {
+		profile.URLRewrites = existing.URLRewrites
+	}
+
+	// Optional everyday Git settings that tend to differ between environments
+	profile.Settings.Editor = promptString(reader, tr("core.editor (e.g. code --wait, vim)"), profile.Settings.Editor)
+	profile.Settings.DefaultBranch = promptValidated(reader, tr("init.defaultBranch (e.g. main)"), profile.Settings.DefaultBranch, validateBranchName)
+	profile.Settings.PullRebase = promptValidated(reader, tr("pull.rebase (true, false, merges or interactive)"), profile.Settings.PullRebase, validatePullRebase)
+
+	// Optional corporate proxy and CA bundle, removed again when switching to a profile without them
+	for {
+		profile.Network.HTTPProxy = promptValidated(reader, tr("HTTP proxy (e.g. http://proxy.corp.example:3128)"), profile.Network.HTTPProxy, validateProxyURL)
+		profile.Network.HTTPSProxy = promptValidated(reader, tr("HTTPS proxy (instead of the HTTP proxy, for proxies only reachable over TLS)"), profile.Network.HTTPSProxy, validateProxyURL)
+		err := validateProxies(profile)
+		if err == nil {
+			break
+		}
+		fmt.Fprintf(stdout, tr("Invalid value: %v\n"), err)
+	}
+	profile.Network.SSLCAInfo = promptValidated(reader, tr("http.sslCAInfo CA bundle (e.g. ~/certs/corp-ca.pem)"), profile.Network.SSLCAInfo, validateCAFile)
+
+	// Optional commit message template, referenced by path or embedded in the profile
+	profile.CommitTemplate = promptProfileFile(reader, tr("commit.template file (e.g. ~/.gitmessage-work)"), profile.CommitTemplate)
+
+	// Optional gitignore fragment used as core.excludesFile
+	profile.Excludes = promptProfileFile(reader, tr("core.excludesFile gitignore fragment (e.g. ~/.gitignore-work)"), profile.Excludes)
+
+	// Optional remote URL patterns used to suggest this profile
+	if existing != nil && len(existing.Remotes) > 0 {
+		fmt.Fprintf(stdout, tr("Enter remote URL patterns, comma-separated [current: %s, press Enter to keep]: "), strings.Join(existing.Remotes, ", "))
+	} else {
+		fmt.Fprint(stdout, tr("Enter remote URL patterns, comma-separated (optional, e.g. github.com/mycorp/*): "))
+	}
+	remotes, _ := reader.ReadString('\n')
+	if patterns := splitList(remotes); len(patterns) > 0 {
+		profile.Remotes = patterns
+	} else if existing != nil {
+		profile.Remotes = existing.Remotes
+	}
+
+	// Optional tags used to group and filter profiles
+	if existing != nil && len(existing.Tags) > 0 {
+		fmt.Fprintf(stdout, tr("Enter tags, comma-separated [current: %s, press Enter to keep]: "), strings.Join(existing.Tags, ", "))
+	} else {
+		fmt.Fprint(stdout, tr("Enter tags, comma-separated (optional, e.g. work, client-a): "))
+	}
+	tags, _ := reader.ReadString('\n')
+	if tagList := splitList(tags); len(tagList) > 0 {
+		profile.Tags = tagList
+	} else if existing != nil {
+		profile.Tags = existing.Tags
+	}
+
 	return profile
 }
 
+// promptString asks for an optional value, keeping current when the answer is empty and clearing it on "-"
+func promptString(reader *bufio.Reader, label string, current string) string {
+	return promptValidated(reader, label, current, nil)
+}
+
+// promptValidated is promptString that asks again until validate accepts the answer
+func promptValidated(reader *bufio.Reader, label string, current string, validate func(string) error) string {
+	for {
+		if current != "" {
+			fmt.Fprintf(stdout, tr("Enter %s [current: %s, enter - to clear]: "), label, current)
+		} else {
+			fmt.Fprintf(stdout, tr("Enter %s (optional, press Enter to skip): "), label)
+		}
+
+		answer, err := reader.ReadString('\n')
+		switch answer = strings.TrimSpace(answer); answer {
+		case "":
+			return current
+		case "-":
+			return ""
+		}
+		if validate == nil {
+			return answer
+		}
+		validationErr := validate(answer)
+		if validationErr == nil {
+			return answer
+		}
+		fmt.Fprintf(stdout, tr("Invalid value: %v\n"), validationErr)
+		if err != nil {
+			return current
+		}
+	}
+}
+
+// validateBranchName checks that name is a valid Git branch name
+func validateBranchName(name string) error {
+	if _, err := runGit("", "check-ref-format", "--branch", name); err != nil {
+		return errorf("'%s' is not a valid branch name", name)
+	}
+	return nil
+}
+
+// validateProxyURL checks that value is a proxy URL libcurl understands
+func validateProxyURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Host == "" {
+		return errorf("'%s' is not a proxy URL such as http://proxy.example.com:3128", value)
+	}
+	switch parsed.Scheme {
+	case "http", "https", "socks4", "socks4a", "socks5", "socks5h":
+		return nil
+	}
+	return errorf("proxy scheme '%s' must be http, https, socks4, socks4a, socks5 or socks5h", parsed.Scheme)
+}
+
+// validateCAFile checks that a CA bundle exists
+func validateCAFile(path string) error {
+	if info, err := os.Stat(expandHome(path)); err != nil || info.IsDir() {
+		return errorf("CA bundle %s is not a file", path)
+	}
+	return nil
+}
+
+// validatePullRebase checks that value is accepted by pull.rebase
+func validatePullRebase(value string) error {
+	switch value {
+	case "true", "false", "merges", "interactive":
+		return nil
+	}
+	return errorf("pull.rebase must be true, false, merges or interactive")
+}
+
+// promptBool asks a yes/no question, keeping current when the answer is empty
+func promptBool(reader *bufio.Reader, label string, current bool) bool {
+	hint := "y/N"
+	if current {
+		hint = "Y/n"
+	}
+	fmt.Fprintf(stdout, "%s [%s]: ", label, hint)
+
+	answer, _ := reader.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	}
+	return current
+}
+
+// splitList splits comma-separated input into trimmed, non-empty items
+func splitList(input string) []string {
+	var items []string
+	for _, item := range strings.Split(input, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// formatSettings renders the optional Git settings a profile sets, e.g. "pull.rebase=true"
+func formatSettings(profile Profile) string {
+	var items []string
+	for _, setting := range []configEntry{
+		{"core.editor", profile.Settings.Editor},
+		{"init.defaultBranch", profile.Settings.DefaultBranch},
+		{"pull.rebase", profile.Settings.PullRebase},
+	} {
+		if setting.Value != "" {
+			items = append(items, setting.Key+"="+setting.Value)
+		}
+	}
+	return strings.Join(items, ", ")
+}
+
+// formatNetwork renders the proxy and CA settings a profile sets, hiding proxy passwords
+func formatNetwork(profile Profile) string {
+	var items []string
+	if proxy := profile.Proxy(); proxy != "" {
+		if parsed, err := url.Parse(proxy); err == nil {
+			proxy = parsed.Redacted()
+		}
+		items = append(items, "http.proxy="+proxy)
+	}
+	if profile.Network.SSLCAInfo != "" {
+		items = append(items, "http.sslCAInfo="+profile.Network.SSLCAInfo)
+	}
+	return strings.Join(items, ", ")
+}
+
+// parseURLRewrites parses comma-separated base=prefix pairs into URL rewrite rules
+func parseURLRewrites(input string) []URLRewrite {
+	var rewrites []URLRewrite
+	for _, item := range splitList(input) {
+		base, insteadOf, found := strings.Cut(item, "=")
+		if base, insteadOf = strings.TrimSpace(base), strings.TrimSpace(insteadOf); found && base != "" && insteadOf != "" {
+			rewrites = append(rewrites, URLRewrite{Base: base, InsteadOf: insteadOf})
+		}
+	}
+	return rewrites
+}
+
+// formatURLRewrites renders URL rewrite rules in the base=prefix form accepted by parseURLRewrites
+func formatURLRewrites(rewrites []URLRewrite) string {
+	var items []string
+	for _, rewrite := range rewrites {
+		items = append(items, rewrite.Base+"="+rewrite.InsteadOf)
+	}
+	return strings.Join(items, ", ")
+}
+
 // getActiveProfile retrieves the currently active Git profile from the global Git config
 func getActiveProfile() (string, string, error) {
 	nameCmd := exec.Command("git", "config", "user.name")
 	nameOutput, err := nameCmd.Output()
+	traceGit("", nameCmd.Args[1:], err, "")
 	if err != nil {
 		return "", "", err
 	}
@@ -140,6 +785,7 @@ func getActiveProfile() (string, string, error) {
 
 	emailCmd := exec.Command("git", "config", "user.email")
 	emailOutput, err := emailCmd.Output()
+	traceGit("", emailCmd.Args[1:], err, "")
 	if err != nil {
 		return "", "", err
 	}
@@ -148,7 +794,304 @@ func getActiveProfile() (string, string, error) {
 	return name, email, nil
 }
 
+// writeProfileFields prints the details of a profile below its header, flagging missing key files in yellow
+func (cm *ConfigManager) writeProfileFields(w io.Writer, name string, color bool) {
+	profile := cm.Profiles[name]
+	missing := profile.missingKeys()
+	keyLine := func(path string) string {
+		for _, m := range missing {
+			if m == path {
+				return paint(color, colorYellow, path+tr(" (missing)"))
+			}
+		}
+		return path
+	}
+
+	fmt.Fprintf(w, tr("  🖖 Name:  %s\n"), profile.Name)
+	fmt.Fprintf(w, tr("  📧 Email: %s\n"), profile.Email)
+	if len(profile.EmailAliases) > 0 {
+		fmt.Fprintf(w, tr("  📨 Aliases: %s\n"), strings.Join(profile.EmailAliases, ", "))
+	}
+	if profile.Author.IsSet() {
+		author := profile.AuthorIdentity()
+		fmt.Fprintf(w, tr("  🖋️  Author: %s <%s>\n"), author.Name, author.Email)
+	}
+	if profile.Committer.IsSet() {
+		committer := profile.CommitterIdentity()
+		fmt.Fprintf(w, tr("  🤖 Committer: %s <%s>\n"), committer.Name, committer.Email)
+	}
+	if profile.Signing.Key != "" {
+		fmt.Fprintf(w, tr("  🔑 Signing Key: %s (%s)\n"), keyLine(profile.Signing.Key), profile.SigningFormat())
+	}
+	if profile.Signing.Program != "" {
+		fmt.Fprintf(w, tr("  🔧 Signing Program: %s\n"), profile.Signing.Program)
+	}
+	if profile.Signing.Commits || profile.Signing.Tags {
+		fmt.Fprintf(w, tr("  ✍️  Signs: commits=%t, tags=%t\n"), profile.Signing.Commits, profile.Signing.Tags)
+	}
+	if profile.Credential.Username != "" || profile.Credential.Helper != "" {
+		fmt.Fprintf(w, tr("  🔐 Credential: username=%s, helper=%s\n"), profile.Credential.Username, profile.Credential.Helper)
+	}
+	if profile.Host != "" {
+		fmt.Fprintf(w, tr("  🌐 Host: %s\n"), strings.TrimSuffix(profile.Host+"/"+profile.Workspace, "/"))
+	}
+	if profile.SSH.Key != "" {
+		fmt.Fprintf(w, tr("  🗝️  SSH: %s (Host %s)\n"), keyLine(profile.SSH.Key), profile.sshAlias(name))
+	}
+	if profile.GitHub.User != "" {
+		fmt.Fprintf(w, tr("  🐙 GitHub: %s\n"), profile.GitHub.User)
+	}
+	if settings := formatSettings(profile); settings != "" {
+		fmt.Fprintf(w, tr("  ⚙️  Settings: %s\n"), settings)
+	}
+	if network := formatNetwork(profile); network != "" {
+		fmt.Fprintf(w, tr("  🛰️  Network: %s\n"), network)
+	}
+	if profile.CommitTemplate.IsSet() {
+		fmt.Fprintf(w, tr("  📝 Commit Template: %s\n"), profile.CommitTemplate.location(name, commitTemplateFile))
+	}
+	if profile.Excludes.IsSet() {
+		fmt.Fprintf(w, tr("  🙈 Excludes File: %s\n"), profile.Excludes.location(name, excludesFile))
+	}
+	if len(profile.URLRewrites) > 0 {
+		fmt.Fprintf(w, tr("  🔀 URL Rewrites: %s\n"), formatURLRewrites(profile.URLRewrites))
+	}
+	if len(profile.Tags) > 0 {
+		fmt.Fprintf(w, tr("  🏷️  Tags: %s\n"), strings.Join(profile.Tags, ", "))
+	}
+	for _, stage := range []string{hookPreApply, hookPostApply} {
+		for _, command := range profile.Hooks.commands(stage) {
+			fmt.Fprintf(w, tr("  🪝 Hook %s: %s\n"), stage, command)
+		}
+	}
+	if profile.Created != nil || profile.LastUsed != nil {
+		fmt.Fprintf(w, tr("  🕒 Created: %s, Updated: %s, Last used: %s\n"),
+			formatTime(profile.Created), formatTime(profile.Updated), formatTime(profile.LastUsed))
+	}
+}
+
+// writeProfileTable renders profiles one per line; only the trailing ACTIVE column is colored so alignment holds
+func (cm *ConfigManager) writeProfileTable(w io.Writer, names []string, active []string, violation error, color bool) {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, tr("NAME\tUSER\tEMAIL\tSIGNING\tTAGS\tACTIVE"))
+	for _, name := range names {
+		profile := cm.Profiles[name]
+
+		signing := "-"
+		if profile.Signing.Key != "" {
+			signing = profile.SigningFormat()
+			if len(profile.missingKeys()) > 0 {
+				signing += tr(" (missing key)")
+			}
+		}
+		tags := "-"
+		if len(profile.Tags) > 0 {
+			tags = strings.Join(profile.Tags, ",")
+		}
+		activeCell := ""
+		if slices.Contains(active, name) {
+			activeCell = paint(color, colorGreen, tr("yes"))
+			if violation != nil {
+				activeCell = paint(color, colorRed, tr("yes (violates policy)"))
+			}
+		}
+
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\n", name, profile.Name, profile.Email, signing, tags, activeCell)
+	}
+	writer.Flush()
+}
+
+// configEntry is a single Git config key and value
+type configEntry struct {
+	Key   string
+	Value string
+}
+
+// profileConfig lists the Git config entries set when a profile is applied
+func profileConfig(name string, profile Profile) []configEntry {
+	entries := []configEntry{
+		{"user.name", profile.Name},
+		{"user.email", profile.Email},
+	}
+
+	// author.* and committer.* take precedence over user.* for their half of each commit (Git 2.22+)
+	for _, role := range []struct {
+		prefix   string
+		identity Identity
+	}{{"author", profile.Author}, {"committer", profile.Committer}} {
+		if role.identity.Name != "" {
+			entries = append(entries, configEntry{role.prefix + ".name", role.identity.Name})
+		}
+		if role.identity.Email != "" {
+			entries = append(entries, configEntry{role.prefix + ".email", role.identity.Email})
+		}
+	}
+
+	switch {
+	case profile.Signing.Key == "":
+	case profile.SigningFormat() == signingFormatSSH:
+		key := profile.Signing.Key
+		if !isSSHKeyLiteral(key) && !strings.HasPrefix(key, "key::") {
+			key = expandHome(key)
+		}
+		entries = append(entries, configEntry{"user.signingkey", key}, configEntry{"gpg.format", "ssh"})
+		if path, err := allowedSignersPath(); err == nil {
+			entries = append(entries, configEntry{"gpg.ssh.allowedSignersFile", path})
+		}
+	case profile.SigningFormat() == signingFormatX509:
+		entries = append(entries, configEntry{"user.signingkey", profile.Signing.Key}, configEntry{"gpg.format", "x509"})
+		if profile.Signing.Program != "" {
+			entries = append(entries, configEntry{"gpg.x509.program", profile.Signing.Program})
+		}
+	default:
+		entries = append(entries, configEntry{"user.signingkey", profile.Signing.Key})
+		if profile.Signing.Program != "" {
+			entries = append(entries, configEntry{"gpg.program", profile.Signing.Program})
+		}
+	}
+
+	if profile.Signing.Commits {
+		entries = append(entries, configEntry{"commit.gpgsign", "true"})
+	}
+	if profile.Signing.Tags {
+		entries = append(entries, configEntry{"tag.gpgSign", "true"})
+	}
+
+	if profile.Credential.Username != "" {
+		entries = append(entries, configEntry{"credential.username", profile.Credential.Username})
+	}
+	if profile.Credential.Helper != "" {
+		entries = append(entries, configEntry{"credential.helper", profile.Credential.Helper})
+	}
+
+	if profile.Settings.Editor != "" {
+		entries = append(entries, configEntry{"core.editor", profile.Settings.Editor})
+	}
+	if profile.Settings.DefaultBranch != "" {
+		entries = append(entries, configEntry{"init.defaultBranch", profile.Settings.DefaultBranch})
+	}
+	if profile.Settings.PullRebase != "" {
+		entries = append(entries, configEntry{"pull.rebase", profile.Settings.PullRebase})
+	}
+
+	// Git sends HTTP and HTTPS traffic through the one http.proxy; remotes are nearly always HTTPS, so its proxy wins
+	if proxy := profile.Proxy(); proxy != "" {
+		entries = append(entries, configEntry{"http.proxy", proxy})
+	}
+	if profile.Network.SSLCAInfo != "" {
+		entries = append(entries, configEntry{"http.sslCAInfo", expandHome(profile.Network.SSLCAInfo)})
+	}
+
+	if profile.CommitTemplate.IsSet() {
+		entries = append(entries, configEntry{"commit.template", profile.CommitTemplate.location(name, commitTemplateFile)})
+	}
+
+	if profile.Excludes.IsSet() {
+		entries = append(entries, configEntry{"core.excludesFile", profile.Excludes.location(name, excludesFile)})
+	}
+
+	for _, rewrite := range profile.URLRewrites {
+		entries = append(entries, configEntry{"url." + rewrite.Base + ".insteadOf", rewrite.InsteadOf})
+	}
+
+	return append(entries, configEntry{assignedProfileKey, name})
+}
+
+// appliedScope returns the config arguments selecting where apply wrote with configArgs, the local config by default
+func appliedScope(configArgs []string) []string {
+	if len(configArgs) == 0 {
+		return []string{"--local"}
+	}
+	return configArgs
+}
+
+// applyCommands returns the git config arguments that write a resolved profile, in the order apply runs them;
+// configArgs (e.g. --worktree) select the config file written instead of the local one
+func applyCommands(name string, profile Profile, configArgs ...string) [][]string {
+	var commands [][]string
+	written := make(map[string]bool)
+	config := append([]string{"config"}, configArgs...)
+	for _, entry := range profileConfig(name, profile) {
+		// Keys such as url.<base>.insteadOf may carry several values
+		args := append(slices.Clone(config), entry.Key, entry.Value)
+		if written[entry.Key] {
+			args = append(slices.Clone(config), "--add", entry.Key, entry.Value)
+		}
+		commands = append(commands, args)
+		if entry.Key == assignedProfileKey || written[entry.Key] {
+			continue
+		}
+		written[entry.Key] = true
+		commands = append(commands, append(slices.Clone(config), "--add", appliedKeysKey, entry.Key))
+	}
+	return commands
+}
+
+// applyProfile writes the profile identity into the Git config of the repository at dir (or the config selected by
+// configArgs), replacing whatever a previously applied profile wrote there
+func applyProfile(dir string, name string, profile Profile, configArgs ...string) error {
+	infof("Applying profile '%s' in %s", name, displayPath(repoTopLevel(dir)))
+	if err := unapplyProfile(dir, configArgs...); err != nil {
+		return err
+	}
+
+	profile, err := resolveProfile(profile)
+	if err != nil {
+		return err
+	}
+	if err := materializeProfileFiles(name, profile); err != nil {
+		return err
+	}
+
+	for _, args := range applyCommands(name, profile, configArgs...) {
+		if _, err := runGit(dir, args...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// appliedKeys returns the local (or configArgs) config keys the last apply wrote in the repository at dir, bookkeeping keys included
+func appliedKeys(dir string, configArgs ...string) ([]string, error) {
+	scope := appliedScope(configArgs)
+	entries, err := gitConfigEntries(dir, scope, `^git-profile\.key$`)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{appliedKeysKey, assignedProfileKey}
+	for _, entry := range entries {
+		keys = append(keys, entry.Value)
+	}
+
+	// Repositories applied before keys were tracked only carry the identity
+	if len(entries) == 0 && len(configArgs) == 0 {
+		if assigned, _ := gitConfigGet(dir, assignedProfileKey); assigned != "" {
+			keys = append(keys, "user.name", "user.email", "user.signingkey")
+		}
+	}
+	return keys, nil
+}
+
+// unapplyProfile removes every config key written by the last apply in the repository at dir, from the local config
+// or the one selected by configArgs
+func unapplyProfile(dir string, configArgs ...string) error {
+	keys, err := appliedKeys(dir, configArgs...)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := gitConfigUnset(dir, appliedScope(configArgs), key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func main() {
+	setLocale(detectLocale())
 	configManager := NewConfigManager()
 
 	var rootCmd = &cobra.Command{
@@ -157,24 +1100,114 @@ func main() {
 		Version: fmt.Sprintf("%s (commit: %s, built: %s)", version, commit, date),
 	}
 
+	// Usage lines read 'git profile ...' when git started us, as it does for a native subcommand
+	if invokedViaGit() {
+		rootCmd.Annotations = map[string]string{cobra.CommandDisplayNameAnnotation: "git profile"}
+	}
 	rootCmd.SetVersionTemplate("🦑 Git Profile CLI\nVersion: {{.Version}}")
+	rootCmd.SetOut(stdout)
+	rootCmd.SetErr(stderr)
+	rootCmd.PersistentFlags().StringVar(&configManager.Picker, "picker", pickerAuto, "How to select profiles: auto (fzf when installed), fzf or prompt")
+	rootCmd.PersistentFlags().BoolVarP(&outputMode.Quiet, "quiet", "q", false, "Only print essential output: results, warnings and errors")
+	rootCmd.PersistentFlags().BoolVar(&outputMode.Plain, "plain", false, "ASCII-only output without emoji or colors")
+	rootCmd.PersistentFlags().BoolVar(&outputMode.Plain, "no-emoji", false, "Same as --plain")
+	rootCmd.PersistentFlags().CountVarP(&logMode.Verbosity, "verbose", "v", "Show more diagnostics (-v for info, -vv for debug)")
+	rootCmd.PersistentFlags().BoolVar(&noUpdateCheck, "no-update-check", noUpdateCheck, "Don't check for a newer release (or set GIT_PROFILE_NO_UPDATE_CHECK)")
+	rootCmd.PersistentFlags().StringVar(&logMode.File, "log-file", os.Getenv("GIT_PROFILE_LOG_FILE"), "Append debug logs to this file (default $GIT_PROFILE_LOG_FILE)")
+	var rpcMode bool
+	rootCmd.Flags().BoolVar(&rpcMode, "rpc", false, "Speak newline-delimited JSON-RPC over stdin and stdout, for editor extensions")
+	rootCmd.Run = func(cmd *cobra.Command, args []string) {
+		if !rpcMode {
+			if configManager.isFirstRun() && isTerminal() {
+				if err := configManager.runOnboarding(rootCmd, os.Stdin); err != nil {
+					fmt.Fprintln(stdout, tr("Setup failed:"), err)
+					os.Exit(1)
+				}
+				return
+			}
+			cmd.Help()
+			if !invokedViaGit() && !gitProfileReachable() {
+				fmt.Fprintln(notices, tr("\n💡 'git profile' doesn't reach this binary yet; run 'git-profile install-alias' to set it up."))
+			}
+			return
+		}
+		// The protocol owns stdout; diagnostics still go to stderr
+		if err := newProfileServer(configManager).serveRPC(os.Stdin, os.Stdout); err != nil {
+			fatal(err)
+		}
+	}
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := setupLogging(logMode); err != nil {
+			return err
+		}
+		debugf("loaded %d profiles from %s", len(configManager.Profiles), configManager.ConfigPath)
+		applyOutputOptions()
+		_, err := configManager.usesFZF()
+		return err
+	}
 
+	var exportSelect bool
+	var exportProfiles []string
+	var exportFormat, exportDir string
 	var exportCmd = &cobra.Command{
 		Use:   "export [output-file]",
 		Short: "Export Git profiles to a JSON file",
+		Args:  cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			var outputPath string
 			if len(args) > 0 {
 				outputPath = args[0]
 			}
 
-			if err := configManager.Export(outputPath); err != nil {
-				fmt.Println("Export failed:", err)
+			names := exportProfiles
+			if exportSelect || len(names) > 0 {
+				var ok bool
+				names, ok = configManager.profileArgsOrSelect(names, tr("Select profiles to export"), tr("pass the profiles with --profile"))
+				if !ok {
+					return
+				}
+			}
+
+			switch exportFormat {
+			case exportFormatJSON:
+				if err := configManager.Export(outputPath, names...); err != nil {
+					fmt.Fprintln(stdout, tr("Export failed:"), err)
+					os.Exit(1)
+				}
+			case exportFormatGitconfig:
+				if outputPath != "" {
+					fmt.Fprintln(stdout, tr("Export failed: --format gitconfig writes one file per profile; pass --dir instead of an output file"))
+					os.Exit(1)
+				}
+				if len(names) == 0 {
+					for name := range configManager.Profiles {
+						names = append(names, name)
+					}
+				}
+				sort.Strings(names)
+
+				dir := expandHome(exportDir)
+				stanzas, err := configManager.exportIncludes(dir, names)
+				if err != nil {
+					fmt.Fprintln(stdout, tr("Export failed:"), err)
+					os.Exit(1)
+				}
+				fmt.Fprintf(notices, tr("Profiles exported to: %s\n"), displayPath(dir))
+				fmt.Fprint(notices, tr("Add these sections to ~/.gitconfig to load them:\n\n"))
+				fmt.Fprint(stdout, stanzas)
+			default:
+				fmt.Fprintf(stdout, tr("Export failed: unknown format '%s' (use %s or %s)\n"), exportFormat, exportFormatJSON, exportFormatGitconfig)
 				os.Exit(1)
 			}
 		},
 	}
 
+	exportCmd.Flags().BoolVarP(&exportSelect, "select", "s", false, "Choose the profiles to export from a checklist")
+	exportCmd.Flags().StringSliceVarP(&exportProfiles, "profile", "p", nil, "Only export these profiles (repeatable or comma-separated)")
+	exportCmd.Flags().StringVar(&exportFormat, "format", exportFormatJSON, "Export format: json, or gitconfig for one include file per profile plus includeIf sections")
+	exportCmd.Flags().StringVar(&exportDir, "dir", "~/.config/git/profiles", "Directory to write the gitconfig files to with --format gitconfig")
+
+	var importOptions importOptions
 	var importCmd = &cobra.Command{
 		Use:   "import <input-file>",
 		Short: "Import Git profiles from a JSON file",
@@ -182,100 +1215,218 @@ func main() {
 		Run: func(cmd *cobra.Command, args []string) {
 			inputPath := args[0]
 
-			if err := configManager.Import(inputPath); err != nil {
-				fmt.Println("Import failed:", err)
+			if err := configManager.Import(inputPath, importOptions); err != nil {
+				fmt.Fprintln(stdout, tr("Import failed:"), err)
 				os.Exit(1)
 			}
 		},
 	}
+	importCmd.Flags().StringVar(&importOptions.Strategy, "strategy", "", "Import without prompting: merge (keep existing profiles) or replace")
+	importCmd.Flags().BoolVar(&importOptions.Force, "force", false, "Let --strategy replace overwrite and remove locked profiles")
+	importCmd.Flags().BoolVar(&importOptions.DryRun, "dry-run", false, "Print which profiles would be added, overwritten or removed without changing anything")
+	importOptions.Checks.addFlags(importCmd)
 
 	rootCmd.AddCommand(exportCmd, importCmd)
 
+	var listTag, listColor, listSort string
+	var listTable, listArchived, listPorcelain bool
 	var listCmd = &cobra.Command{
 		Use:   "ls",
 		Short: "List all saved Git profiles",
 		Run: func(cmd *cobra.Command, args []string) {
-			if len(configManager.Profiles) == 0 {
-				fmt.Println("No profiles found. Use 'git profile add' to create a profile.")
+			defer printUpdateHint()
+
+			if len(configManager.Profiles) == 0 && !listPorcelain {
+				if configManager.isFirstRun() && isTerminal() {
+					if err := configManager.runOnboarding(rootCmd, os.Stdin); err != nil {
+						fmt.Fprintln(stdout, tr("Setup failed:"), err)
+						os.Exit(1)
+					}
+					return
+				}
+				fmt.Fprintln(stdout, tr("No profiles found. Use 'git profile add' to create a profile."))
 				return
 			}
 
-			activeName, activeEmail, err := getActiveProfile()
+			color, err := useColor(listColor)
 			if err != nil {
-				fmt.Println("Error retrieving active profile:", err)
+				fmt.Fprintln(stdout, tr("Error listing profiles:"), err)
+				os.Exit(1)
+			}
+
+			// Scripts get every profile listed, with none active, when no identity is configured
+			activeName, activeEmail, err := getActiveProfile()
+			if err != nil && !listPorcelain {
+				fmt.Fprintln(stdout, tr("Error retrieving active profile:"), err)
 				return
 			}
 
+			// Profiles sharing the identity can only be told apart by the assignment apply records
+			active, ambiguous := configManager.activeProfiles(".", activeName, activeEmail)
+			if ambiguous && !listPorcelain {
+				warnf("identity %s <%s> matches profiles '%s'; apply one of them to record which is in use", activeName, activeEmail, strings.Join(active, "', '"))
+			}
+
+			// A violated policy marks the active profile in red instead of green
+			violation := configManager.checkPolicy(".")
+			if violation != nil && !listPorcelain {
+				warnf("%v", violation)
+			}
+
+			var names []string
 			for name, profile := range configManager.Profiles {
-				activeMarker := ""
-				if profile.Name == activeName && profile.Email == activeEmail {
-					activeMarker = " (active)"
+				if (listTag == "" || profile.HasTag(listTag)) && (listArchived || !profile.Archived) {
+					names = append(names, name)
 				}
-				fmt.Printf("💻 Profile: %s%s\n", name, activeMarker)
-				fmt.Printf("  🖖 Name:  %s\n", profile.Name)
-				fmt.Printf("  📧 Email: %s\n", profile.Email)
-				if profile.Signing.Key != "" {
-					fmt.Printf("  🔑 Signing Key: %s\n", profile.Signing.Key)
+			}
+			if err := configManager.sortProfiles(names, listSort); err != nil {
+				fmt.Fprintln(stdout, tr("Error listing profiles:"), err)
+				os.Exit(1)
+			}
+
+			if listPorcelain {
+				configManager.writeProfilePorcelain(stdout, names, active, ambiguous, violation)
+				return
+			}
+			if listTable {
+				configManager.writeProfileTable(stdout, names, active, violation, color)
+				return
+			}
+
+			for _, name := range names {
+				activeMarker, headerColor := "", ""
+				if slices.Contains(active, name) {
+					activeMarker, headerColor = tr(" (active)"), colorGreen
+					if ambiguous {
+						activeMarker = tr(" (active?)")
+					}
+					if violation != nil {
+						activeMarker, headerColor = tr(" (active, violates policy)"), colorRed
+					}
+				}
+				if source, shared := configManager.sourceOf(name); shared {
+					activeMarker += fmt.Sprintf(tr(" (shared: %s)"), source)
+				}
+				if path, owned := configManager.fragmentOf(name); owned {
+					activeMarker += fmt.Sprintf(tr(" (from %s)"), filepath.Base(path))
+				}
+				if configManager.Profiles[name].Locked {
+					activeMarker += tr(" (locked)")
+				}
+				if configManager.Profiles[name].Archived {
+					activeMarker += tr(" (archived)")
 				}
-				fmt.Println()
+				if expiry, soon := configManager.signingKeyExpiry(resolvedIdentity(configManager.Profiles[name])); soon {
+					activeMarker += fmt.Sprintf(tr(" (signing key %s)"), expiry)
+				}
+				header := fmt.Sprintf(tr("Profile: %s%s"), name, activeMarker)
+				if headerColor != "" {
+					header = paint(color, headerColor, header)
+				}
+				fmt.Fprintf(stdout, "💻 %s\n", header)
+				configManager.writeProfileFields(stdout, name, color)
+				fmt.Fprintln(stdout)
 			}
 		},
 	}
-
+	listCmd.Flags().StringVar(&listTag, "tag", "", "Only list profiles with this tag")
+	listCmd.Flags().BoolVar(&listArchived, "archived", false, "Also list archived profiles")
+	listCmd.Flags().StringVar(&listSort, "sort", sortByName, "Order profiles by name, email or last-used")
+	listCmd.Flags().BoolVar(&listTable, "table", false, "Print one aligned line per profile instead of detailed blocks")
+	listCmd.Flags().BoolVar(&listPorcelain, "porcelain", false, "Print the stable, tab-separated format for scripts")
+	listCmd.Flags().StringVar(&listColor, "color", colorAuto, "Highlight the active profile and problems: auto (when writing to a terminal), always or never")
+
+	var fromTemplate string
+	var addChecks profileChecks
 	var addCmd = &cobra.Command{
-		Use:   "add",
+		Use:   "add [name]",
 		Short: "Add a new Git profile (interactive)",
+		Args:  cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			// Interactive profile name selection
-			prompt := promptui.Prompt{
-				Label: "Enter profile name",
-				Validate: func(input string) error {
-					if input == "" {
-						return fmt.Errorf("profile name cannot be empty")
-					}
-					if _, exists := configManager.Profiles[input]; exists {
-						return fmt.Errorf("profile '%s' already exists", input)
-					}
-					return nil
-				},
+			validateName := func(input string) error {
+				if err := validateProfileName(input); err != nil {
+					return err
+				}
+				if _, exists := configManager.Profiles[input]; exists {
+					return errorf("profile '%s' already exists", input)
+				}
+				return nil
 			}
 
-			profileName, err := prompt.Run()
-			if err != nil {
-				fmt.Println("Cancelled.")
-				return
+			var profileName string
+			if len(args) > 0 {
+				profileName = args[0]
+				if err := validateName(profileName); err != nil {
+					fmt.Fprintln(stdout, tr("Add failed:"), err)
+					os.Exit(1)
+				}
+			} else {
+				if err := requireTerminal(tr("pass the profile name: git profile add <name>")); err != nil {
+					fmt.Fprintln(stdout, tr("Add failed:"), err)
+					os.Exit(1)
+				}
+
+				// Interactive profile name selection
+				prompt := promptui.Prompt{
+					Label:    tr("Enter profile name"),
+					Validate: validateName,
+				}
+
+				name, err := prompt.Run()
+				if err != nil {
+					fmt.Fprintln(notices, tr("Cancelled."))
+					return
+				}
+				profileName = name
 			}
 
-			// Interactive profile details input
-			profile := interactiveProfileInput(nil)
+			// Interactive profile details input, only asking for the identity when starting from a template
+			var profile Profile
+			if fromTemplate != "" {
+				template, err := configManager.profileFromTemplate(fromTemplate)
+				if err != nil {
+					fmt.Fprintln(stdout, tr("Add failed:"), err)
+					os.Exit(1)
+				}
+				profile = templateProfileInput(template)
+			} else {
+				profile = interactiveProfileInput(nil)
+			}
+			if err := addChecks.check(profileName, profile); err != nil {
+				fmt.Fprintln(stdout, tr("Add failed:"), err)
+				os.Exit(1)
+			}
 
 			// Save the profile
+			now := time.Now()
+			profile.Created, profile.Updated = &now, &now
 			configManager.Profiles[profileName] = profile
 			configManager.save()
+			configManager.warnDuplicates(profileName)
 
-			fmt.Printf("Profile '%s' added successfully!\n", profileName)
+			fmt.Fprintf(notices, tr("Profile '%s' added successfully!\n"), profileName)
 		},
 	}
 
+	addCmd.Flags().StringVar(&fromTemplate, "from-template", "", "Pre-fill the profile from a template, asking only for name and email")
+	addChecks.addFlags(addCmd)
+
+	var editChecks profileChecks
+	var editForce bool
+
 	var editCmd = &cobra.Command{
-		Use:   "edit",
+		Use:   "edit [profile]",
 		Short: "Edit an existing Git profile (interactive)",
+		Args:  cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			// Select profile to edit
-			var profileNames []string
-			for name := range configManager.Profiles {
-				profileNames = append(profileNames, name)
-			}
-
-			prompt := promptui.Select{
-				Label: "Select profile to edit",
-				Items: profileNames,
+			selectedProfile, ok := configManager.profileArgOrSelect(args, tr("Select profile to edit"), tr("pass the profile: git profile edit <profile>"))
+			if !ok {
+				return
 			}
 
-			_, selectedProfile, err := prompt.Run()
-			if err != nil {
-				fmt.Println("Cancelled.")
-				return
+			if err := configManager.checkUnlocked("edit", editForce, selectedProfile); err != nil {
+				fmt.Fprintln(stdout, tr("Edit failed:"), err)
+				os.Exit(1)
 			}
 
 			// Existing profile
@@ -283,105 +1434,157 @@ func main() {
 
 			// Interactive edit
 			updatedProfile := interactiveProfileInput(&existingProfile)
+			if err := editChecks.check(selectedProfile, updatedProfile); err != nil {
+				fmt.Fprintln(stdout, tr("Edit failed:"), err)
+				os.Exit(1)
+			}
 
 			// Save updated profile
+			configManager.backupBefore("edit")
+			now := time.Now()
+			updatedProfile.Updated = &now
 			configManager.Profiles[selectedProfile] = updatedProfile
 			configManager.save()
 
-			fmt.Printf("Profile '%s' updated successfully!\n", selectedProfile)
+			fmt.Fprintf(notices, tr("Profile '%s' updated successfully!\n"), selectedProfile)
 		},
 	}
 
+	editChecks.addFlags(editCmd)
+	editCmd.Flags().BoolVar(&editForce, "force", false, "Edit the profile even if it is locked")
+
+	var removeYes, removeForce, removeClearRefs bool
+	var removeReassignTo string
 	var removeCmd = &cobra.Command{
-		Use:   "rm",
-		Short: "Remove a Git profile (interactive)",
+		Use:   "rm [profile...]",
+		Short: "Remove Git profiles (interactive)",
 		Run: func(cmd *cobra.Command, args []string) {
-			// Select profile to remove
-			var profileNames []string
-			for name := range configManager.Profiles {
-				profileNames = append(profileNames, name)
-			}
-
-			prompt := promptui.Select{
-				Label: "Select profile to remove",
-				Items: profileNames,
-			}
-
-			_, selectedProfile, err := prompt.Run()
-			if err != nil {
-				fmt.Println("Cancelled.")
+			selectedProfiles, ok := configManager.profileArgsOrSelect(args, tr("Select profiles to remove"), tr("pass the profiles and --yes: git profile rm <profile>... --yes"))
+			if !ok {
 				return
 			}
 
-			// Confirmation prompt
-			confirmPrompt := promptui.Prompt{
-				Label:     fmt.Sprintf("Are you sure you want to remove profile '%s'", selectedProfile),
-				IsConfirm: true,
+			for _, name := range selectedProfiles {
+				if source, shared := configManager.sourceOf(name); shared {
+					fmt.Fprintf(stdout, tr("Profile '%s' comes from %s and can't be removed locally.\n"), name, source)
+					os.Exit(1)
+				}
 			}
-
-			_, confirmErr := confirmPrompt.Run()
-			if confirmErr != nil {
-				fmt.Println("Removal cancelled.")
-				return
+			if err := configManager.checkUnlocked("remove", removeForce, selectedProfiles...); err != nil {
+				fmt.Fprintln(stdout, tr("Removal failed:"), err)
+				os.Exit(1)
 			}
 
-			// Remove profile
-			delete(configManager.Profiles, selectedProfile)
-			configManager.save()
+			// Repositories and rules using the profiles are reassigned or cleared rather than left pointing at nothing
+			refs := configManager.referencesTo(selectedProfiles)
+			action, target := "", removeReassignTo
+			switch {
+			case removeReassignTo != "" && removeClearRefs:
+				fmt.Fprintln(stdout, tr("Removal failed: pass either --reassign or --clear"))
+				os.Exit(1)
+			case removeReassignTo != "":
+				if _, exists := configManager.Profiles[target]; !exists || slices.Contains(selectedProfiles, target) {
+					fmt.Fprintf(stdout, tr("Removal failed: can't reassign to '%s', which isn't a remaining profile\n"), target)
+					os.Exit(1)
+				}
+				if err := configManager.checkNotArchived(target); err != nil {
+					fmt.Fprintln(stdout, tr("Removal failed:"), err)
+					os.Exit(1)
+				}
+				action = removeReassign
+			case removeClearRefs:
+				action = removeClear
+			case !refs.IsEmpty():
+				fmt.Fprintln(stdout, tr("These still use the profiles being removed:"))
+				refs.print(stdout)
+				if err := requireTerminal(tr("pass --reassign <profile> or --clear")); err != nil {
+					fmt.Fprintln(stdout, tr("Removal failed:"), err)
+					os.Exit(1)
+				}
+				var err error
+				if action, target, err = configManager.chooseReferenceAction(selectedProfiles); err != nil {
+					fmt.Fprintln(notices, tr("Removal cancelled."))
+					return
+				}
+			}
 
-			fmt.Printf("Profile '%s' removed successfully!\n", selectedProfile)
-		},
-	}
+			// Confirmation prompt
+			if !removeYes {
+				if err := requireTerminal(tr("pass --yes to remove without confirmation")); err != nil {
+					fmt.Fprintln(stdout, tr("Removal failed:"), err)
+					os.Exit(1)
+				}
+				label := fmt.Sprintf(tr("Are you sure you want to remove profile '%s'"), selectedProfiles[0])
+				if len(selectedProfiles) > 1 {
+					label = fmt.Sprintf(tr("Are you sure you want to remove %d profiles (%s)"), len(selectedProfiles), strings.Join(selectedProfiles, ", "))
+				}
+				confirmPrompt := promptui.Prompt{
+					Label:     label,
+					IsConfirm: true,
+				}
 
-	var applyCmd = &cobra.Command{
-		Use:   "apply",
-		Short: "Apply a specific Git profile (interactive)",
-		Run: func(cmd *cobra.Command, args []string) {
-			// Select profile to apply
-			var profileNames []string
-			for name := range configManager.Profiles {
-				profileNames = append(profileNames, name)
+				_, confirmErr := confirmPrompt.Run()
+				if confirmErr != nil {
+					fmt.Fprintln(notices, tr("Removal cancelled."))
+					return
+				}
 			}
 
-			prompt := promptui.Select{
-				Label: "Select profile to apply",
-				Items: profileNames,
+			// Remove profiles along with their keystore entries
+			configManager.backupBefore("rm")
+			var failures []error
+			switch {
+			case refs.IsEmpty():
+			case action == removeReassign:
+				failures = configManager.reassignReferences(selectedProfiles, target)
+				fmt.Fprintf(notices, tr("Reassigned %d repositories and %d rules to '%s'.\n"), len(refs.Repos)-len(failures), len(refs.Rules), target)
+			case action == removeClear:
+				failures = configManager.clearReferences(selectedProfiles)
+				fmt.Fprintf(notices, tr("Cleared %d repositories and %d rules.\n"), len(refs.Repos)-len(failures), len(refs.Rules))
 			}
-
-			_, selectedProfile, err := prompt.Run()
-			if err != nil {
-				fmt.Println("Cancelled.")
-				return
+			for _, err := range failures {
+				warnf("%v", err)
 			}
-
-			profile := configManager.Profiles[selectedProfile]
-
-			gitCommands := [][]string{
-				{"config", "user.name", profile.Name},
-				{"config", "user.email", profile.Email},
+			for _, name := range selectedProfiles {
+				configManager.removeProfileSecrets(name)
+				delete(configManager.Profiles, name)
 			}
+			configManager.save()
 
-			for _, gitCmd := range gitCommands {
-				cmd := exec.Command("git", gitCmd...)
-				if err := cmd.Run(); err != nil {
-					fmt.Printf("Error applying profile: %v\n", err)
-					return
-				}
+			for _, name := range selectedProfiles {
+				fmt.Fprintf(notices, tr("Profile '%s' removed successfully!\n"), name)
 			}
-
-			fmt.Printf("Profile '%s' applied successfully!\n", selectedProfile)
 		},
 	}
-
-	rootCmd.AddCommand(listCmd, addCmd, editCmd, removeCmd, applyCmd)
+	removeCmd.Flags().BoolVarP(&removeYes, "yes", "y", false, "Skip the confirmation prompt")
+	removeCmd.Flags().BoolVar(&removeForce, "force", false, "Remove profiles even if they are locked")
+	removeCmd.Flags().StringVar(&removeReassignTo, "reassign", "", "Reapply this profile to the repositories using the removed ones and point their rules at it")
+	removeCmd.Flags().BoolVar(&removeClearRefs, "clear", false, "Unapply the repositories using the removed profiles and delete their rules")
+
+	rootCmd.AddCommand(listCmd, addCmd, editCmd, removeCmd, newApplyCmd(configManager), newUnapplyCmd(configManager))
+	rootCmd.AddCommand(newHooksCmd(configManager), newAuditCmd(configManager), newFixAuthorCmd(configManager))
+	rootCmd.AddCommand(newDoctorCmd(configManager), newRulesCmd(configManager), newCheckCmd(configManager))
+	rootCmd.AddCommand(newReportCmd(configManager), newReposCmd(configManager), newScanCmd(configManager))
+	rootCmd.AddCommand(newSignersCmd(configManager), newSecretCmd(configManager), newVerifyCmd(configManager))
+	rootCmd.AddCommand(newNoreplyCmd(configManager), newSSHCmd(configManager), newTemplateCmd(configManager))
+	rootCmd.AddCommand(newSourceCmd(configManager), newSyncCmd(configManager), newValidateCmd(configManager), newTUICmd(configManager))
+	rootCmd.AddCommand(newBackupCmd(configManager), newRestoreCmd(configManager), newHistoryCmd(configManager), newShowCmd(configManager))
+	rootCmd.AddCommand(newWhichCmd(configManager), newStatusCmd(configManager), newCurrentCmd(configManager), newMigrateCmd(configManager))
+	rootCmd.AddCommand(newServeCmd(configManager), newInstallAliasCmd(configManager), newGenDocsCmd(rootCmd))
+	rootCmd.AddCommand(newSetupCmd(configManager, rootCmd), newExecCmd(configManager), newLockCmd(configManager), newUnlockCmd(configManager))
+	rootCmd.AddCommand(newArchiveCmd(configManager), newUnarchiveCmd(configManager), newEnvCmd(configManager), newWatchCmd(configManager))
+	reserveCommandNames(rootCmd)
 
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+		fmt.Fprintln(stdout, err)
 		os.Exit(1)
 	}
+	if versionFlag := rootCmd.Flags().Lookup("version"); versionFlag != nil && versionFlag.Changed {
+		printUpdateHint()
+	}
 }
 
-func (cm *ConfigManager) Export(outputPath string) error {
+func (cm *ConfigManager) Export(outputPath string, names ...string) error {
 	// If no path provided, use default in home directory
 	if outputPath == "" {
 		homeDir, err := os.UserHomeDir()
@@ -396,8 +1599,21 @@ func (cm *ConfigManager) Export(outputPath string) error {
 		outputPath += ".json"
 	}
 
+	// Only the named profiles, or all of them
+	profiles := cm.Profiles
+	if len(names) > 0 {
+		profiles = make(map[string]Profile, len(names))
+		for _, name := range names {
+			profile, exists := cm.Profiles[name]
+			if !exists {
+				return errorf("profile '%s' not found", name)
+			}
+			profiles[name] = profile
+		}
+	}
+
 	// Marshal profiles to JSON
-	data, err := json.MarshalIndent(cm.Profiles, "", "  ")
+	data, err := json.MarshalIndent(profiles, "", "  ")
 	if err != nil {
 		return err
 	}
@@ -407,52 +1623,121 @@ func (cm *ConfigManager) Export(outputPath string) error {
 		return err
 	}
 
-	fmt.Printf("Profiles exported to: %s\n", outputPath)
+	fmt.Fprintf(notices, tr("Profiles exported to: %s\n"), outputPath)
 	return nil
 }
 
-func (cm *ConfigManager) Import(inputPath string) error {
+// Export formats understood by --format
+const (
+	exportFormatJSON      = "json"
+	exportFormatGitconfig = "gitconfig"
+)
+
+// Import strategies understood by --strategy
+const (
+	importMerge   = "merge"
+	importReplace = "replace"
+)
+
+// importOptions holds the flags of the import command
+type importOptions struct {
+	Strategy string
+	Checks   profileChecks
+	DryRun   bool
+	Force    bool
+}
+
+func (cm *ConfigManager) Import(inputPath string, options importOptions) error {
+	strategy, checks := options.Strategy, options.Checks
+
 	// Read the input file
 	data, err := os.ReadFile(inputPath)
 	if err != nil {
 		return err
 	}
 
+	// Reject files that don't match the schema before touching anything
+	problems, err := validateConfigData(data)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s: %v", inputPath, problems[0])
+	}
+
 	// Unmarshal the JSON data
 	var importedProfiles map[string]Profile
 	if err := json.Unmarshal(data, &importedProfiles); err != nil {
 		return err
 	}
 
+	// Catch typos such as malformed emails before they spread
+	var names []string
+	for name := range importedProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := validateProfileName(name); err != nil {
+			return err
+		}
+		if err := checks.check(name, importedProfiles[name]); err != nil {
+			return err
+		}
+	}
+
 	// Prompt for import strategy
-	prompt := promptui.Select{
-		Label: "Import Strategy",
-		Items: []string{
-			"Merge (Add new profiles, keep existing)",
-			"Replace (Overwrite all existing profiles)",
-		},
+	switch strategy {
+	case "":
+		if err := requireTerminal(tr("pass --strategy merge or --strategy replace")); err != nil {
+			return err
+		}
+		prompt := promptui.Select{
+			Label: tr("Import Strategy"),
+			Items: []string{
+				tr("Merge (Add new profiles, keep existing)"),
+				tr("Replace (Overwrite all existing profiles)"),
+			},
+		}
+
+		index, _, err := prompt.Run()
+		if err != nil {
+			return errorf("import cancelled")
+		}
+		strategy = []string{importMerge, importReplace}[index]
+	case importMerge, importReplace:
+	default:
+		return errorf("unknown import strategy '%s' (use merge or replace)", strategy)
 	}
 
-	_, strategy, err := prompt.Run()
-	if err != nil {
-		return fmt.Errorf("import cancelled")
+	// Locked profiles are only overwritten or dropped by a replace under --force
+	plan := cm.planImport(importedProfiles, strategy)
+	if err := cm.checkUnlocked("replace", options.Force, append(plan.Overwritten, plan.Removed...)...); err != nil {
+		return err
+	}
+
+	if options.DryRun {
+		plan.print(stdout)
+		return nil
 	}
 
 	// Apply import strategy
 	switch strategy {
-	case "Merge (Add new profiles, keep existing)":
+	case importMerge:
 		for name, profile := range importedProfiles {
 			if _, exists := cm.Profiles[name]; !exists {
 				cm.Profiles[name] = profile
 			}
 		}
-	case "Replace (Overwrite all existing profiles)":
-		cm.Profiles = importedProfiles
+	case importReplace:
+		cm.backupBefore("import")
+		cm.replaceProfiles(importedProfiles)
 	}
 
 	// Save the updated profiles
 	cm.save()
+	cm.warnDuplicates(names...)
 
-	fmt.Printf("Profiles imported successfully. Total profiles: %d\n", len(cm.Profiles))
+	fmt.Fprintf(notices, tr("Profiles imported successfully. Total profiles: %d\n"), len(cm.Profiles))
 	return nil
 }
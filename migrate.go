@@ -0,0 +1,366 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Tools migrate reads identities from
+const (
+	migrateGitIdentity   = "git-identity"
+	migrateGitUserSwitch = "git-user-switch"
+	migrateIncludes      = "gitconfig-includes"
+)
+
+// migration is what another tool's configuration converts to
+type migration struct {
+	Profiles map[string]Profile
+	Rules    []Rule
+}
+
+// sshIdentityPattern finds the key passed to ssh with -i in a core.sshCommand
+var sshIdentityPattern = regexp.MustCompile(`(?:^|\s)-i\s*("[^"]+"|'[^']+'|\S+)`)
+
+// invalidNameChars matches what profile names can't contain
+var invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// migratedName turns a name found in another tool into a valid profile name that isn't taken yet in taken
+func migratedName(candidate string, taken map[string]Profile) string {
+	name := strings.Trim(invalidNameChars.ReplaceAllString(candidate, "-"), "-._")
+	if len(name) > 60 {
+		name = name[:60]
+	}
+	switch {
+	case name == "":
+		name = "profile"
+	case validateProfileName(name) != nil:
+		name = "profile-" + name
+	}
+
+	unique := name
+	for i := 2; ; i++ {
+		if _, exists := taken[unique]; !exists {
+			return unique
+		}
+		unique = fmt.Sprintf("%s-%d", name, i)
+	}
+}
+
+// nameFromEmail suggests a profile name from the domain of an email, e.g. "company" for john@company.com
+func nameFromEmail(email string) string {
+	_, domain, _ := strings.Cut(email, "@")
+	label, _, _ := strings.Cut(domain, ".")
+	return label
+}
+
+// profileFromConfig reads a profile from gitconfig entries
+func profileFromConfig(entries []configEntry) Profile {
+	var profile Profile
+	for _, entry := range entries {
+		switch strings.ToLower(entry.Key) {
+		case "user.name":
+			profile.Name = entry.Value
+		case "user.email":
+			profile.Email = entry.Value
+		case "user.signingkey":
+			profile.Signing.Key = entry.Value
+		case "gpg.format":
+			profile.Signing.Format = entry.Value
+		case "commit.gpgsign":
+			profile.Signing.Commits = entry.Value == "true"
+		case "tag.gpgsign":
+			profile.Signing.Tags = entry.Value == "true"
+		case "core.sshcommand":
+			if match := sshIdentityPattern.FindStringSubmatch(entry.Value); match != nil {
+				profile.SSH.Key = strings.Trim(match[1], `"'`)
+			}
+		}
+	}
+	return profile
+}
+
+// readGitIdentity converts the identity.<id>.* entries git-identity keeps in the config selected by configArgs
+func readGitIdentity(configArgs []string) (migration, error) {
+	entries, err := gitConfigEntries("", configArgs, `^identity\..+\.`)
+	if err != nil {
+		return migration{}, err
+	}
+
+	var ids []string
+	identities := make(map[string]Profile)
+	for _, entry := range entries {
+		rest := strings.TrimPrefix(entry.Key, "identity.")
+		dot := strings.LastIndex(rest, ".")
+		id, field := rest[:dot], rest[dot+1:]
+		profile, seen := identities[id]
+		if !seen {
+			ids = append(ids, id)
+		}
+		switch field {
+		case "name":
+			profile.Name = entry.Value
+		case "email":
+			profile.Email = entry.Value
+		case "signingkey":
+			profile.Signing.Key = entry.Value
+		case "sshkey":
+			profile.SSH.Key = entry.Value
+		}
+		identities[id] = profile
+	}
+
+	result := migration{Profiles: make(map[string]Profile)}
+	for _, id := range ids {
+		result.Profiles[migratedName(id, result.Profiles)] = identities[id]
+	}
+	return result, nil
+}
+
+// gitUserSwitchPath returns where git-user-switch keeps its users
+func gitUserSwitchPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "configstore", "git-user-switch.json"), nil
+}
+
+// readGitUserSwitch converts the users saved by git-user-switch, naming each profile after its email domain
+func readGitUserSwitch(path string) (migration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return migration{}, err
+	}
+
+	type user struct {
+		Name       string `json:"name"`
+		Email      string `json:"email"`
+		SigningKey string `json:"signingkey"`
+	}
+	var users []user
+	if err := json.Unmarshal(data, &users); err != nil {
+		var store struct {
+			Users []user `json:"users"`
+		}
+		if err := json.Unmarshal(data, &store); err != nil {
+			return migration{}, fmt.Errorf("%s: %w", path, err)
+		}
+		users = store.Users
+	}
+
+	result := migration{Profiles: make(map[string]Profile)}
+	for _, u := range users {
+		profile := Profile{Name: u.Name, Email: u.Email}
+		profile.Signing.Key = u.SigningKey
+		result.Profiles[migratedName(nameFromEmail(u.Email), result.Profiles)] = profile
+	}
+	return result, nil
+}
+
+// includeRule converts an includeIf condition into a rule, if git-profile can express it
+func includeRule(condition string, profile string) (Rule, bool) {
+	for _, prefix := range []string{"gitdir:", "gitdir/i:"} {
+		if dir, found := strings.CutPrefix(condition, prefix); found {
+			return Rule{Dir: dir, Profile: profile}, true
+		}
+	}
+	if url, found := strings.CutPrefix(condition, "hasconfig:remote.*.url:"); found {
+		return Rule{Remote: strings.ReplaceAll(normalizeRemoteURL(url), "**", "*"), Profile: profile}, true
+	}
+	return Rule{}, false
+}
+
+// includeProfileName suggests a profile name from an included file, e.g. "work" for ~/.gitconfig-work
+func includeProfileName(path string) string {
+	name := strings.TrimPrefix(filepath.Base(path), ".")
+	name = strings.TrimPrefix(strings.TrimSuffix(name, ".gitconfig"), "gitconfig")
+	return strings.TrimLeft(name, "-_.")
+}
+
+// readConfigIncludes converts hand-rolled includeIf sections of the config selected by configArgs:
+// each included file holding an identity becomes a profile, and each condition a rule
+func readConfigIncludes(configArgs []string) (migration, error) {
+	entries, err := gitConfigEntries("", configArgs, `^includeif\..+\.path$`)
+	if err != nil {
+		return migration{}, err
+	}
+	ownIncludes, _ := includeDir()
+
+	result := migration{Profiles: make(map[string]Profile)}
+	names := make(map[string]string)
+	for _, entry := range entries {
+		condition := strings.TrimSuffix(strings.TrimPrefix(entry.Key, "includeif."), ".path")
+		path := expandHome(entry.Value)
+		if !filepath.IsAbs(path) {
+			// Relative includes are relative to the including file, here the user's home
+			homeDir, _ := os.UserHomeDir()
+			path = filepath.Join(homeDir, path)
+		}
+		if ownIncludes != "" && strings.HasPrefix(path, ownIncludes+string(filepath.Separator)) {
+			continue
+		}
+
+		name, seen := names[path]
+		if !seen {
+			included, err := gitConfigEntries("", []string{"--file", path}, `^(user\.|gpg\.format$|commit\.gpgsign$|tag\.gpgsign$|core\.sshcommand$)`)
+			if err != nil {
+				warnf("skipping %s: %v", displayPath(path), err)
+				continue
+			}
+			profile := profileFromConfig(included)
+			if profile.Email == "" {
+				infof("skipping %s: it sets no user.email", displayPath(path))
+				continue
+			}
+			candidate := includeProfileName(path)
+			if candidate == "" {
+				candidate = nameFromEmail(profile.Email)
+			}
+			name = migratedName(candidate, result.Profiles)
+			names[path] = name
+			result.Profiles[name] = profile
+		}
+
+		rule, ok := includeRule(condition, name)
+		if !ok {
+			warnf("includeIf \"%s\" has no equivalent rule; add one with 'git profile rules add %s'", condition, name)
+			continue
+		}
+		if !slices.Contains(result.Rules, rule) {
+			result.Rules = append(result.Rules, rule)
+		}
+	}
+	return result, nil
+}
+
+// readMigration reads the configuration of another tool
+func readMigration(from string, file string) (migration, error) {
+	configArgs := []string{"--global"}
+	if file != "" {
+		configArgs = []string{"--file", expandHome(file)}
+	}
+
+	switch from {
+	case migrateGitIdentity:
+		return readGitIdentity(configArgs)
+	case migrateGitUserSwitch:
+		path := expandHome(file)
+		if path == "" {
+			var err error
+			if path, err = gitUserSwitchPath(); err != nil {
+				return migration{}, err
+			}
+		}
+		return readGitUserSwitch(path)
+	case migrateIncludes:
+		return readConfigIncludes(configArgs)
+	}
+	return migration{}, errorf("unknown tool '%s' (use %s, %s or %s)", from, migrateGitIdentity, migrateGitUserSwitch, migrateIncludes)
+}
+
+// newRules returns the rules of a migration that aren't configured yet
+func (cm *ConfigManager) newRules(m migration) []Rule {
+	var rules []Rule
+	for _, rule := range m.Rules {
+		if !slices.Contains(cm.Rules, rule) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// migrate adds the profiles and rules converted from another tool, keeping profiles that already exist
+func (cm *ConfigManager) migrate(m migration, checks profileChecks, dryRun bool) error {
+	var names []string
+	for name := range m.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := checks.check(name, m.Profiles[name]); err != nil {
+			return err
+		}
+	}
+
+	plan := cm.planImport(m.Profiles, importMerge)
+	rules := cm.newRules(m)
+	if dryRun {
+		plan.print(stdout)
+		for _, rule := range rules {
+			fmt.Fprintf(stdout, tr("Would add rule: %s → %s\n"), rule.Target(), rule.Profile)
+		}
+		return nil
+	}
+
+	if len(plan.Added) == 0 && len(rules) == 0 {
+		fmt.Fprintln(notices, tr("Nothing to migrate."))
+		return nil
+	}
+
+	cm.backupBefore("migrate")
+	for _, name := range plan.Added {
+		cm.Profiles[name] = m.Profiles[name]
+	}
+	cm.Rules = append(cm.Rules, rules...)
+	cm.save()
+	cm.warnDuplicates(plan.Added...)
+
+	for _, name := range plan.Added {
+		fmt.Fprintf(notices, tr("Profile '%s' added (%s).\n"), name, m.Profiles[name].Email)
+	}
+	for _, name := range plan.Skipped {
+		fmt.Fprintf(notices, tr("Profile '%s' already exists and was kept.\n"), name)
+	}
+	for _, rule := range rules {
+		fmt.Fprintf(notices, tr("Rule added: %s → %s\n"), rule.Target(), rule.Profile)
+	}
+	return nil
+}
+
+// newMigrateCmd builds the migrate command
+func newMigrateCmd(configManager *ConfigManager) *cobra.Command {
+	var from, file string
+	var dryRun bool
+	var checks profileChecks
+
+	var migrateCmd = &cobra.Command{
+		Use:   "migrate --from <tool>",
+		Short: "Convert identities from git-identity, git-user-switch or includeIf setups into profiles and rules",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			m, err := readMigration(from, file)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Migration failed:"), err)
+				os.Exit(1)
+			}
+			if len(m.Profiles) == 0 {
+				fmt.Fprintf(stdout, tr("No identities found for %s.\n"), from)
+				return
+			}
+
+			if err := configManager.migrate(m, checks, dryRun); err != nil {
+				fmt.Fprintln(stdout, tr("Migration failed:"), err)
+				os.Exit(1)
+			}
+			if from == migrateIncludes && !dryRun && len(m.Rules) > 0 {
+				fmt.Fprintln(notices, tr("Your includeIf sections still apply; once 'git profile rules install' manages them, remove the old ones."))
+			}
+		},
+	}
+
+	migrateCmd.Flags().StringVar(&from, "from", "", fmt.Sprintf("Tool to migrate from: %s, %s or %s", migrateGitIdentity, migrateGitUserSwitch, migrateIncludes))
+	migrateCmd.Flags().StringVar(&file, "file", "", "Read this file instead of the tool's default (the global gitconfig, or git-user-switch's store)")
+	migrateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the profiles and rules that would be added without changing anything")
+	checks.addFlags(migrateCmd)
+	migrateCmd.MarkFlagRequired("from")
+
+	return migrateCmd
+}
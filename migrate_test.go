@@ -0,0 +1,148 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMigratedName tests turning names from other tools into valid, unique profile names
+func TestMigratedName(t *testing.T) {
+	taken := map[string]Profile{"work": {}, "work-2": {}}
+	assert.Equal(t, "oss", migratedName("oss", taken))
+	assert.Equal(t, "work-3", migratedName("work", taken))
+	assert.Equal(t, "my-client", migratedName("my client!", taken))
+	assert.Equal(t, "profile-default", migratedName("default", taken))
+	assert.Equal(t, "profile", migratedName("", taken))
+
+	assert.Equal(t, "company", nameFromEmail("john.doe@company.com"))
+	assert.Equal(t, "work", includeProfileName("/home/john/.gitconfig-work"))
+	assert.Equal(t, "oss", includeProfileName("/home/john/oss.gitconfig"))
+	assert.Equal(t, "acme", includeProfileName("/home/john/.config/git/acme"))
+}
+
+// TestReadGitIdentity tests converting git-identity's identity.<id>.* entries
+func TestReadGitIdentity(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "git-profile-migrate")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	configPath := filepath.Join(tempDir, "gitconfig")
+	config := `[identity]
+	default = work
+[identity "work"]
+	name = John Doe
+	email = john.doe@company.com
+	signingkey = ABCD1234
+	sshkey = ~/.ssh/id_work
+[identity "oss.github"]
+	name = John OSS
+	email = john@oss.dev
+`
+	assert.NoError(t, os.WriteFile(configPath, []byte(config), 0644))
+
+	m, err := readGitIdentity([]string{"--file", configPath})
+	assert.NoError(t, err)
+	assert.Len(t, m.Profiles, 2)
+	assert.Equal(t, "john.doe@company.com", m.Profiles["work"].Email)
+	assert.Equal(t, "ABCD1234", m.Profiles["work"].Signing.Key)
+	assert.Equal(t, "~/.ssh/id_work", m.Profiles["work"].SSH.Key)
+	assert.Equal(t, "John OSS", m.Profiles["oss.github"].Name)
+	assert.Empty(t, m.Rules)
+}
+
+// TestReadGitUserSwitch tests converting git-user-switch's saved users
+func TestReadGitUserSwitch(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "git-profile-migrate")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "git-user-switch.json")
+	store := `{"users": [
+  {"name": "John Doe", "email": "john.doe@company.com"},
+  {"name": "John Personal", "email": "john@gmail.com", "signingkey": "ABCD1234"},
+  {"name": "John Other", "email": "other@company.com"}
+]}`
+	assert.NoError(t, os.WriteFile(path, []byte(store), 0644))
+
+	m, err := readGitUserSwitch(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "John Doe", m.Profiles["company"].Name)
+	assert.Equal(t, "other@company.com", m.Profiles["company-2"].Email)
+	assert.Equal(t, "ABCD1234", m.Profiles["gmail"].Signing.Key)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`[{"name": "John Doe", "email": "john.doe@company.com"}]`), 0644))
+	m, err = readGitUserSwitch(path)
+	assert.NoError(t, err)
+	assert.Len(t, m.Profiles, 1)
+}
+
+// TestReadConfigIncludes tests converting hand-rolled includeIf sections into profiles and rules
+func TestReadConfigIncludes(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "git-profile-home")
+	assert.NoError(t, err)
+	defer os.RemoveAll(homeDir)
+	t.Setenv("HOME", homeDir)
+
+	work := "[user]\n\tname = John Doe\n\temail = john.doe@company.com\n\tsigningkey = ABCD1234\n" +
+		"[commit]\n\tgpgsign = true\n[core]\n\tsshCommand = ssh -i ~/.ssh/id_work -o IdentitiesOnly=yes\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(homeDir, ".gitconfig-work"), []byte(work), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(homeDir, ".gitconfig-aliases"), []byte("[alias]\n\tco = checkout\n"), 0644))
+
+	global := `[includeIf "gitdir:~/work/"]
+	path = ~/.gitconfig-work
+[includeIf "hasconfig:remote.*.url:git@github.com:acme/**"]
+	path = .gitconfig-work
+[includeIf "onbranch:main"]
+	path = ~/.gitconfig-work
+[includeIf "gitdir:~/"]
+	path = ~/.gitconfig-aliases
+`
+	configPath := filepath.Join(homeDir, ".gitconfig")
+	assert.NoError(t, os.WriteFile(configPath, []byte(global), 0644))
+
+	m, err := readConfigIncludes([]string{"--file", configPath})
+	assert.NoError(t, err)
+	if assert.Len(t, m.Profiles, 1) {
+		profile := m.Profiles["work"]
+		assert.Equal(t, "john.doe@company.com", profile.Email)
+		assert.Equal(t, "ABCD1234", profile.Signing.Key)
+		assert.True(t, profile.Signing.Commits)
+		assert.Equal(t, "~/.ssh/id_work", profile.SSH.Key)
+	}
+	assert.Equal(t, []Rule{{Dir: "~/work/", Profile: "work"}, {Remote: "github.com/acme/*", Profile: "work"}}, m.Rules)
+}
+
+// TestMigrate tests adding migrated profiles and rules while keeping existing profiles
+func TestMigrate(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "git-profile-migrate")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	t.Setenv("HOME", tempDir)
+
+	cm := &ConfigManager{
+		ConfigPath: filepath.Join(tempDir, ".git-profiles.json"),
+		Profiles:   map[string]Profile{"work": {Name: "John Doe", Email: "john.doe@company.com"}},
+		Rules:      []Rule{{Dir: "~/work/", Profile: "work"}},
+	}
+	m := migration{
+		Profiles: map[string]Profile{
+			"work": {Name: "Someone Else", Email: "else@company.com"},
+			"oss":  {Name: "John OSS", Email: "john@oss.dev"},
+		},
+		Rules: []Rule{{Dir: "~/work/", Profile: "work"}, {Dir: "~/oss/", Profile: "oss"}},
+	}
+
+	assert.NoError(t, cm.migrate(m, profileChecks{}, true))
+	assert.Len(t, cm.Profiles, 1)
+
+	assert.NoError(t, cm.migrate(m, profileChecks{}, false))
+	assert.Equal(t, "John Doe", cm.Profiles["work"].Name)
+	assert.Equal(t, "john@oss.dev", cm.Profiles["oss"].Email)
+	assert.Equal(t, []Rule{{Dir: "~/work/", Profile: "work"}, {Dir: "~/oss/", Profile: "oss"}}, cm.Rules)
+	assert.FileExists(t, cm.ConfigPath)
+
+	assert.Error(t, cm.migrate(migration{Profiles: map[string]Profile{"bad": {Name: "", Email: "nope"}}}, profileChecks{Strict: true}, false))
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// isFirstRun reports whether git-profile has never been set up: no config file and no profiles from fragments or sources
+func (cm *ConfigManager) isFirstRun() bool {
+	if _, err := os.Stat(cm.ConfigPath); !os.IsNotExist(err) {
+		return false
+	}
+	return len(cm.Profiles) == 0
+}
+
+// promptRequired asks for a value until validate accepts it, offering suggested as the answer to an empty line
+func promptRequired(reader *bufio.Reader, label string, suggested string, validate func(string) error) string {
+	for {
+		if suggested != "" {
+			fmt.Fprintf(stdout, tr("Enter %s [%s]: "), label, suggested)
+		} else {
+			fmt.Fprintf(stdout, tr("Enter %s: "), label)
+		}
+
+		answer, err := reader.ReadString('\n')
+		if answer = strings.TrimSpace(answer); answer == "" {
+			answer = suggested
+		}
+		if answer != "" {
+			validationErr := error(nil)
+			if validate != nil {
+				validationErr = validate(answer)
+			}
+			if validationErr == nil {
+				return answer
+			}
+			fmt.Fprintf(stdout, tr("Invalid value: %v\n"), validationErr)
+		}
+		if err != nil {
+			return suggested
+		}
+	}
+}
+
+// completionFile returns where shell completion for shell is loaded from automatically, and what else is needed
+func completionFile(shell string) (string, string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", err
+	}
+	switch shell {
+	case "bash":
+		dataHome := os.Getenv("XDG_DATA_HOME")
+		if dataHome == "" {
+			dataHome = filepath.Join(homeDir, ".local", "share")
+		}
+		return filepath.Join(dataHome, "bash-completion", "completions", "git-profile"), "", nil
+	case "zsh":
+		return filepath.Join(homeDir, ".zsh", "completions", "_git-profile"), tr("add 'fpath=(~/.zsh/completions $fpath)' before compinit in ~/.zshrc"), nil
+	case "fish":
+		return filepath.Join(homeDir, ".config", "fish", "completions", "git-profile.fish"), "", nil
+	}
+	return "", "", errorf("no completion for shell '%s'", shell)
+}
+
+// installCompletion writes the completion script of root for shell where the shell picks it up
+func installCompletion(root *cobra.Command, shell string) (string, string, error) {
+	path, hint, err := completionFile(shell)
+	if err != nil {
+		return "", "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", "", err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return "", "", err
+	}
+	defer file.Close()
+
+	switch shell {
+	case "bash":
+		err = root.GenBashCompletionV2(file, true)
+	case "zsh":
+		err = root.GenZshCompletion(file)
+	case "fish":
+		err = root.GenFishCompletion(file, true)
+	}
+	return path, hint, err
+}
+
+// onboardingProfile asks for the identity of a new profile, suggesting the global name
+func onboardingProfile(reader *bufio.Reader, globalName string) Profile {
+	return Profile{
+		Name:  promptRequired(reader, tr("name"), globalName, nil),
+		Email: promptRequired(reader, tr("email"), "", validateEmail),
+	}
+}
+
+// runOnboarding walks through the first setup: saving the global identity as a profile, adding work and personal
+// profiles, directory rules for them and shell completion. Answers are read from in.
+func (cm *ConfigManager) runOnboarding(root *cobra.Command, in io.Reader) error {
+	reader := bufio.NewReader(in)
+	fmt.Fprintln(stdout, tr("👋 Welcome to git-profile! Let's set up your profiles (press Enter to accept the [suggestion])."))
+
+	var order []string
+	save := func(name string, profile Profile) {
+		now := time.Now()
+		profile.Created, profile.Updated = &now, &now
+		cm.Profiles[name] = profile
+		order = append(order, name)
+		fmt.Fprintf(notices, tr("✅ Profile '%s' added: %s <%s>\n"), name, profile.Name, profile.Email)
+	}
+	validateNew := func(name string) error {
+		if err := validateProfileName(name); err != nil {
+			return err
+		}
+		if _, exists := cm.Profiles[name]; exists {
+			return errorf("profile '%s' already exists", name)
+		}
+		return nil
+	}
+
+	// The identity already in use is the most likely first profile
+	globalName, _ := runGit("", "config", "--global", "--get", "user.name")
+	globalEmail, _ := runGit("", "config", "--global", "--get", "user.email")
+	if globalEmail != "" {
+		fmt.Fprintf(stdout, tr("\nYour global Git identity is %s <%s>.\n"), globalName, globalEmail)
+		if promptBool(reader, tr("Save it as a profile?"), true) {
+			profile := Profile{Name: globalName, Email: globalEmail}
+			profile.Signing.Key, _ = runGit("", "config", "--global", "--get", "user.signingkey")
+			if format, _ := runGit("", "config", "--global", "--get", "gpg.format"); format != "" {
+				profile.Signing.Format = format
+			}
+			name := promptRequired(reader, tr("profile name"), migratedName(nameFromEmail(globalEmail), cm.Profiles), validateNew)
+			save(name, profile)
+		}
+	}
+
+	for _, name := range []string{"work", "personal"} {
+		if _, exists := cm.Profiles[name]; exists {
+			continue
+		}
+		fmt.Fprintln(stdout)
+		if promptBool(reader, fmt.Sprintf(tr("Create a '%s' profile?"), name), false) {
+			save(name, onboardingProfile(reader, globalName))
+		}
+	}
+
+	// Directory rules let check, hooks and includeIf pick the profile by where a repository lives
+	var rules []Rule
+	if len(order) > 0 {
+		fmt.Fprintln(stdout, tr("\nProfiles can be selected by directory, e.g. every repository under ~/work/."))
+	}
+	for _, name := range order {
+		dir := promptString(reader, fmt.Sprintf(tr("the directory of '%s' repositories"), name), "")
+		if dir != "" {
+			if !strings.HasSuffix(dir, "/") {
+				dir += "/"
+			}
+			rules = append(rules, Rule{Dir: dir, Profile: name})
+		}
+	}
+	cm.Rules = append(cm.Rules, rules...)
+	cm.save()
+
+	for _, rule := range rules {
+		fmt.Fprintf(notices, tr("✅ Rule added: %s → %s\n"), rule.Target(), rule.Profile)
+	}
+	if len(rules) > 0 && promptBool(reader, tr("Write includeIf sections to ~/.gitconfig so Git picks these identities by itself?"), false) {
+		includesPath, err := includeDir()
+		if err == nil {
+			_, err = cm.installIncludes([]string{"--global"}, includesPath)
+		}
+		if err != nil {
+			warnf("installing includeIf sections: %v", err)
+		} else {
+			cm.recordHistory("install-rules", "", "", scopeGlobal)
+			fmt.Fprintln(notices, tr("✅ includeIf sections installed."))
+		}
+	}
+
+	if shell := filepath.Base(os.Getenv("SHELL")); shell == "bash" || shell == "zsh" || shell == "fish" {
+		if promptBool(reader, fmt.Sprintf(tr("\nSet up %s completion?"), shell), false) {
+			path, hint, err := installCompletion(root, shell)
+			switch {
+			case err != nil:
+				warnf("installing %s completion: %v", shell, err)
+			case hint != "":
+				fmt.Fprintf(notices, tr("✅ Completion written to %s; %s.\n"), displayPath(path), hint)
+			default:
+				fmt.Fprintf(notices, tr("✅ Completion written to %s; it loads in new shells.\n"), displayPath(path))
+			}
+		}
+	}
+
+	fmt.Fprintf(notices, tr("\n🎉 All set! Profiles are saved in %s.\n"), displayPath(cm.ConfigPath))
+	fmt.Fprintln(notices, tr("Next: 'git profile apply' in a repository, 'git profile add' for more profiles, 'git profile hooks install' to guard commits."))
+	return nil
+}
+
+// newSetupCmd builds the setup command, which runs the first-run wizard on demand
+func newSetupCmd(configManager *ConfigManager, rootCmd *cobra.Command) *cobra.Command {
+	var setupCmd = &cobra.Command{
+		Use:   "setup",
+		Short: "Guided setup of profiles, directory rules and shell completion",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := requireTerminal(tr("create profiles with 'git profile add <name>'")); err != nil {
+				fmt.Fprintln(stdout, tr("Setup failed:"), err)
+				os.Exit(1)
+			}
+			if err := configManager.runOnboarding(rootCmd, os.Stdin); err != nil {
+				fmt.Fprintln(stdout, tr("Setup failed:"), err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	return setupCmd
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOnboarding tests the first-run wizard: the global identity becomes a profile, a work profile is added with a
+// directory rule, and completion is written for the user's shell
+func TestOnboarding(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "git-profile-onboarding")
+	assert.NoError(t, err)
+	defer os.RemoveAll(homeDir)
+	t.Setenv("HOME", homeDir)
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("SHELL", "/bin/fish")
+	globalConfig := filepath.Join(homeDir, ".gitconfig")
+	t.Setenv("GIT_CONFIG_GLOBAL", globalConfig)
+	_, err = runGit("", "config", "--global", "user.name", "John Doe")
+	assert.NoError(t, err)
+	_, err = runGit("", "config", "--global", "user.email", "john@personal.dev")
+	assert.NoError(t, err)
+
+	cm := &ConfigManager{ConfigPath: filepath.Join(homeDir, ".git-profiles.json"), Profiles: make(map[string]Profile)}
+	assert.True(t, cm.isFirstRun())
+
+	answers := strings.Join([]string{
+		"",                 // save the global identity
+		"",                 // named after its email domain, "personal"
+		"y",                // create a work profile; "personal" is taken, so it isn't offered
+		"",                 // keeping the global name
+		"not-an-email",     // rejected and asked again
+		"john@company.com", // work email
+		"~/personal/",      // directory of personal repositories
+		"~/work",           // directory of work repositories
+		"n",                // no includeIf sections
+		"y",                // fish completion
+	}, "\n") + "\n"
+
+	root := &cobra.Command{Use: "git-profile"}
+	assert.NoError(t, cm.runOnboarding(root, strings.NewReader(answers)))
+
+	assert.Equal(t, Profile{Name: "John Doe", Email: "john@personal.dev"}, withoutTimes(cm.Profiles["personal"]))
+	assert.Equal(t, Profile{Name: "John Doe", Email: "john@company.com"}, withoutTimes(cm.Profiles["work"]))
+	assert.Equal(t, []Rule{{Dir: "~/personal/", Profile: "personal"}, {Dir: "~/work/", Profile: "work"}}, cm.Rules)
+	assert.FileExists(t, cm.ConfigPath)
+	assert.False(t, cm.isFirstRun())
+
+	completion, err := os.ReadFile(filepath.Join(homeDir, ".config", "fish", "completions", "git-profile.fish"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(completion), "git-profile")
+
+	// includeIf sections were declined, so the global config only holds the identity
+	content, err := os.ReadFile(globalConfig)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "includeIf")
+}
+
+// withoutTimes clears the timestamps of a profile so it can be compared
+func withoutTimes(profile Profile) Profile {
+	profile.Created, profile.Updated = nil, nil
+	return profile
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/manifoldco/promptui"
+	"github.com/muesli/termenv"
+)
+
+// outputOptions holds the global flags controlling how output looks
+type outputOptions struct {
+	Quiet bool
+	Plain bool
+}
+
+// outputWriter writes command output, replacing glyphs with ASCII in plain mode and dropping everything when muted
+type outputWriter struct {
+	target io.Writer
+	plain  *bool
+	muted  *bool
+}
+
+// Write implements io.Writer, reporting the input as fully written even when it was rewritten or dropped
+func (w *outputWriter) Write(p []byte) (int, error) {
+	if w.muted != nil && *w.muted {
+		return len(p), nil
+	}
+	if w.plain != nil && *w.plain {
+		if _, err := io.WriteString(w.target, plainText(string(p))); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	return w.target.Write(p)
+}
+
+var (
+	// outputMode holds the global output flags
+	outputMode outputOptions
+
+	// stdout and stderr carry essential output: results, warnings and errors
+	stdout io.Writer = &outputWriter{target: os.Stdout, plain: &outputMode.Plain}
+	stderr io.Writer = &outputWriter{target: os.Stderr, plain: &outputMode.Plain}
+
+	// notices carries confirmations and progress messages, which --quiet drops
+	notices io.Writer = &outputWriter{target: os.Stdout, plain: &outputMode.Plain, muted: &outputMode.Quiet}
+)
+
+// plainReplacements spells out the glyphs that carry meaning
+var plainReplacements = strings.NewReplacer(
+	"⚠️ ", "WARNING:",
+	"⚠️", "WARNING:",
+	"❌", "ERROR:",
+	"✅", "OK:",
+	"→", "->",
+	"•", "*",
+	"›", ">",
+	"●", "*",
+	"⇥", "(tab)",
+	"↑", "up",
+	"↓", "down",
+)
+
+// isDecoration reports whether r is an emoji or a modifier that only decorates text
+func isDecoration(r rune) bool {
+	return r >= 0x1F000 || (r >= 0x2600 && r <= 0x27BF) || r == 0xFE0F || r == 0x200D
+}
+
+// plainText rewrites s to ASCII-friendly text: meaningful glyphs are spelled out, decorative emoji are dropped with their padding
+func plainText(s string) string {
+	s = plainReplacements.Replace(s)
+
+	var sb strings.Builder
+	skipSpace := false
+	for _, r := range s {
+		if isDecoration(r) {
+			skipSpace = true
+			continue
+		}
+		if skipSpace && unicode.IsSpace(r) && r != '\n' {
+			continue
+		}
+		skipSpace = false
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// noColor reports whether colors are disabled, by NO_COLOR (https://no-color.org) or --plain
+func noColor() bool {
+	if _, set := os.LookupEnv("NO_COLOR"); set {
+		return true
+	}
+	return outputMode.Plain
+}
+
+// applyOutputOptions configures the prompt and TUI libraries to honor the output flags
+func applyOutputOptions() {
+	if !noColor() {
+		return
+	}
+
+	lipgloss.SetColorProfile(termenv.Ascii)
+	for name := range promptui.FuncMap {
+		promptui.FuncMap[name] = func(v interface{}) string { return fmt.Sprint(v) }
+	}
+	promptui.IconInitial = "?"
+	promptui.IconGood = "ok"
+	promptui.IconWarn = "!"
+	promptui.IconBad = "x"
+	if outputMode.Plain {
+		promptui.IconSelect = ">"
+	}
+}
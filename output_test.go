@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPlainOutput tests rewriting glyphs for ASCII-only output
+func TestPlainOutput(t *testing.T) {
+	assert.Equal(t, "Profile: work (active)", plainText("💻 Profile: work (active)"))
+	assert.Equal(t, "  Tags: oss", plainText("  🏷️  Tags: oss"))
+	assert.Equal(t, "WARNING: key expired", plainText("⚠️  key expired"))
+	assert.Equal(t, "ERROR: bad email", plainText("❌ bad email"))
+	assert.Equal(t, "OK: No problems found.", plainText("✅ No problems found."))
+	assert.Equal(t, "1. remote github.com/acme-* -> work\n", plainText("1. remote github.com/acme-* → work\n"))
+	assert.Equal(t, "Git Profile CLI", plainText("🦑 Git Profile CLI"))
+}
+
+// TestQuietOutput tests that --quiet drops notices but keeps essential output
+func TestQuietOutput(t *testing.T) {
+	defer func(mode outputOptions) { outputMode = mode }(outputMode)
+
+	var buf bytes.Buffer
+	essential := &outputWriter{target: &buf, plain: &outputMode.Plain}
+	notice := &outputWriter{target: &buf, plain: &outputMode.Plain, muted: &outputMode.Quiet}
+
+	outputMode = outputOptions{Quiet: true}
+	fmt.Fprintln(notice, "Profile 'work' applied successfully!")
+	fmt.Fprintln(essential, "⚠️  signing key expired")
+	assert.Equal(t, "⚠️  signing key expired\n", buf.String())
+
+	buf.Reset()
+	outputMode = outputOptions{Plain: true}
+	fmt.Fprintln(notice, "✅ Repository complies")
+	assert.Equal(t, "OK: Repository complies\n", buf.String())
+}
+
+// TestNoColor tests honoring the NO_COLOR convention
+func TestNoColor(t *testing.T) {
+	defer func(mode outputOptions) { outputMode = mode }(outputMode)
+	outputMode = outputOptions{}
+
+	t.Setenv("NO_COLOR", "")
+	assert.True(t, noColor())
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"unicode"
+
+	"github.com/manifoldco/promptui"
+)
+
+// Pickers understood by --picker
+const (
+	pickerAuto   = "auto"
+	pickerFZF    = "fzf"
+	pickerPrompt = "prompt"
+)
+
+// fuzzyMatch reports whether the characters of input appear in order in item, ignoring case and spaces
+func fuzzyMatch(input string, item string) bool {
+	item = strings.ToLower(item)
+	for _, r := range strings.ToLower(input) {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		index := strings.IndexRune(item, r)
+		if index < 0 {
+			return false
+		}
+		item = item[index+len(string(r)):]
+	}
+	return true
+}
+
+// profileSearchText is what type-to-filter matches a profile against: its name, email and tags
+func (cm *ConfigManager) profileSearchText(name string) string {
+	profile := cm.Profiles[name]
+	return strings.Join(append([]string{name, profile.Email}, profile.Tags...), " ")
+}
+
+// usesFZF reports whether profiles are picked with fzf, failing when fzf was requested but isn't installed
+func (cm *ConfigManager) usesFZF() (bool, error) {
+	_, err := exec.LookPath("fzf")
+	switch cm.Picker {
+	case "", pickerAuto:
+		return err == nil, nil
+	case pickerFZF:
+		if err != nil {
+			return false, errorf("--picker fzf: fzf is not installed")
+		}
+		return true, nil
+	case pickerPrompt:
+		return false, nil
+	}
+	return false, errorf("unknown picker '%s' (use auto, fzf or prompt)", cm.Picker)
+}
+
+// fzfInput lists names one per line, followed by tab-separated fields for the preview pane
+func (cm *ConfigManager) fzfInput(names []string) string {
+	var sb strings.Builder
+	for _, name := range names {
+		profile := cm.Profiles[name]
+		key := profile.Signing.Key
+		if key == "" {
+			key = "none"
+		}
+		fields := []string{
+			name,
+			"name:  " + profile.Name,
+			"email: " + profile.Email,
+			"key:   " + key,
+			"tags:  " + strings.Join(profile.Tags, ", "),
+		}
+		sb.WriteString(strings.Join(fields, "\t") + "\n")
+	}
+	return sb.String()
+}
+
+// runFZF lets the user pick from names with fzf, showing the highlighted profile in a preview pane
+func (cm *ConfigManager) runFZF(label string, names []string, flags ...string) ([]string, error) {
+	args := []string{
+		"--prompt", label + "> ",
+		"--height", "40%",
+		"--reverse",
+		"--delimiter", "\t",
+		"--with-nth", "1",
+		"--preview", "printf '%s\\n' {2..}",
+	}
+	cmd := exec.Command("fzf", append(args, flags...)...)
+	cmd.Stdin = strings.NewReader(cm.fzfInput(names))
+	cmd.Stderr = os.Stderr
+	var selection bytes.Buffer
+	cmd.Stdout = &selection
+
+	if err := cmd.Run(); err != nil {
+		return nil, errorf("fzf: %w", err)
+	}
+	var selected []string
+	for _, line := range strings.Split(strings.TrimSpace(selection.String()), "\n") {
+		if name, _, _ := strings.Cut(line, "\t"); name != "" {
+			selected = append(selected, name)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, errorf("nothing selected")
+	}
+	return selected, nil
+}
+
+// selectWithFZF picks one of names with fzf
+func (cm *ConfigManager) selectWithFZF(label string, names []string, cursor int) (string, error) {
+	// fzf has no initial cursor, so the preselected profile goes first
+	ordered := append([]string{names[cursor]}, names[:cursor]...)
+	ordered = append(ordered, names[cursor+1:]...)
+
+	selected, err := cm.runFZF(label, ordered)
+	if err != nil {
+		return "", err
+	}
+	return selected[0], nil
+}
+
+// selectProfile prompts for one of names, filtering the list as the user types
+func (cm *ConfigManager) selectProfile(label string, names []string, cursor int) (string, error) {
+	useFZF, err := cm.usesFZF()
+	if err != nil {
+		return "", err
+	}
+	if useFZF && len(names) > 0 {
+		return cm.selectWithFZF(label, names, cursor)
+	}
+
+	prompt := promptui.Select{
+		Label:             label,
+		Items:             names,
+		Size:              10,
+		CursorPos:         cursor,
+		StartInSearchMode: true,
+		Searcher: func(input string, index int) bool {
+			return fuzzyMatch(input, cm.profileSearchText(names[index]))
+		},
+	}
+
+	_, selected, err := prompt.Run()
+	return selected, err
+}
+
+// multiSelectDone is the entry that ends a checkbox selection
+const multiSelectDone = "✔ Done"
+
+// checkboxItems renders names with a checkbox each, after the entry ending the selection
+func checkboxItems(names []string, checked map[string]bool) []string {
+	items := []string{multiSelectDone}
+	for _, name := range names {
+		box := "[ ]"
+		if checked[name] {
+			box = "[x]"
+		}
+		items = append(items, box+" "+name)
+	}
+	return items
+}
+
+// selectProfiles prompts for any number of names as a checklist: enter toggles a profile, and Done ends the selection
+func (cm *ConfigManager) selectProfiles(label string, names []string) ([]string, error) {
+	useFZF, err := cm.usesFZF()
+	if err != nil {
+		return nil, err
+	}
+	if useFZF && len(names) > 0 {
+		return cm.runFZF(label+tr(" (tab to mark)"), names, "--multi")
+	}
+
+	checked := make(map[string]bool)
+	cursor := 1
+	for {
+		prompt := promptui.Select{
+			Label:     label + tr(" (enter toggles)"),
+			Items:     checkboxItems(names, checked),
+			Size:      10,
+			CursorPos: cursor,
+			Searcher: func(input string, index int) bool {
+				return index == 0 || fuzzyMatch(input, cm.profileSearchText(names[index-1]))
+			},
+		}
+
+		index, _, err := prompt.Run()
+		if err != nil {
+			return nil, err
+		}
+		if index == 0 {
+			break
+		}
+		checked[names[index-1]] = !checked[names[index-1]]
+		cursor = index
+	}
+
+	var selected []string
+	for _, name := range names {
+		if checked[name] {
+			selected = append(selected, name)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, errorf("nothing selected")
+	}
+	return selected, nil
+}
+
+// profileArgsOrSelect returns the profiles named on the command line, or prompts for several when running in a terminal
+func (cm *ConfigManager) profileArgsOrSelect(args []string, label string, hint string) ([]string, bool) {
+	if len(args) > 0 {
+		for _, name := range args {
+			if _, exists := cm.Profiles[name]; !exists {
+				fmt.Fprintf(stdout, tr("Profile '%s' not found.\n"), name)
+				os.Exit(1)
+			}
+		}
+		return args, true
+	}
+
+	if err := requireTerminal(hint); err != nil {
+		fmt.Fprintln(stdout, err)
+		os.Exit(1)
+	}
+
+	selected, err := cm.selectProfiles(label, cm.visibleProfiles())
+	if err != nil {
+		fmt.Fprintln(notices, tr("Cancelled."))
+		return nil, false
+	}
+	return selected, true
+}
+
+// profileArgOrSelect returns the profile named on the command line, or prompts for one when running in a terminal
+func (cm *ConfigManager) profileArgOrSelect(args []string, label string, hint string) (string, bool) {
+	if len(args) > 0 {
+		if _, exists := cm.Profiles[args[0]]; !exists {
+			fmt.Fprintf(stdout, tr("Profile '%s' not found.\n"), args[0])
+			os.Exit(1)
+		}
+		return args[0], true
+	}
+
+	if err := requireTerminal(hint); err != nil {
+		fmt.Fprintln(stdout, err)
+		os.Exit(1)
+	}
+
+	selected, err := cm.selectProfile(label, cm.visibleProfiles(), 0)
+	if err != nil {
+		fmt.Fprintln(notices, tr("Cancelled."))
+		return "", false
+	}
+	return selected, true
+}
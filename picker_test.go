@@ -0,0 +1,92 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFuzzyMatch tests type-to-filter matching of profiles
+func TestFuzzyMatch(t *testing.T) {
+	assert.True(t, fuzzyMatch("", "work"))
+	assert.True(t, fuzzyMatch("wrk", "work"))
+	assert.True(t, fuzzyMatch("WK", "work"))
+	assert.True(t, fuzzyMatch("acme oss", "acme-oss"))
+	assert.False(t, fuzzyMatch("kw", "work"))
+	assert.False(t, fuzzyMatch("works", "work"))
+
+	cm := &ConfigManager{Profiles: map[string]Profile{
+		"acme": {Name: "John Doe", Email: "john.doe@company.com", Tags: []string{"client"}},
+	}}
+	assert.True(t, fuzzyMatch("company", cm.profileSearchText("acme")))
+	assert.True(t, fuzzyMatch("client", cm.profileSearchText("acme")))
+	assert.False(t, fuzzyMatch("gmail", cm.profileSearchText("acme")))
+}
+
+// TestFZFPicker tests choosing the picker and selecting through fzf
+func TestFZFPicker(t *testing.T) {
+	binDir, err := os.MkdirTemp("", "git-profile-bin")
+	assert.NoError(t, err)
+	defer os.RemoveAll(binDir)
+	path := os.Getenv("PATH")
+	t.Setenv("PATH", binDir)
+
+	cm := &ConfigManager{Profiles: map[string]Profile{
+		"personal": {Name: "John Personal", Email: "john.personal@gmail.com"},
+		"work":     {Name: "John Doe", Email: "john.doe@company.com", Tags: []string{"client"}},
+	}}
+	work := cm.Profiles["work"]
+	work.Signing.Key = "ABCD1234"
+	cm.Profiles["work"] = work
+
+	useFZF, err := cm.usesFZF()
+	assert.NoError(t, err)
+	assert.False(t, useFZF)
+	cm.Picker = pickerFZF
+	_, err = cm.usesFZF()
+	assert.ErrorContains(t, err, "not installed")
+	cm.Picker = "dmenu"
+	_, err = cm.usesFZF()
+	assert.ErrorContains(t, err, "unknown picker")
+
+	assert.Equal(t, "work\tname:  John Doe\temail: john.doe@company.com\tkey:   ABCD1234\ttags:  client\n", cm.fzfInput([]string{"work"}))
+
+	// A stand-in fzf that picks the second line
+	script := "#!/bin/sh\nsed -n 2p\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(binDir, "fzf"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+path)
+	cm.Picker = pickerAuto
+	useFZF, err = cm.usesFZF()
+	assert.NoError(t, err)
+	assert.True(t, useFZF)
+
+	// The preselected profile is listed first
+	selected, err := cm.selectProfile("Select profile", []string{"personal", "work"}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "personal", selected)
+}
+
+// TestMultiSelect tests the checklist items and picking several profiles through fzf
+func TestMultiSelect(t *testing.T) {
+	assert.Equal(t, []string{multiSelectDone, "[ ] personal", "[x] work"},
+		checkboxItems([]string{"personal", "work"}, map[string]bool{"work": true}))
+
+	binDir, err := os.MkdirTemp("", "git-profile-bin")
+	assert.NoError(t, err)
+	defer os.RemoveAll(binDir)
+
+	// A stand-in fzf that requires --multi and marks every line
+	script := "#!/bin/sh\nfor arg; do [ \"$arg\" = --multi ] && exec cat; done\nexit 2\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(binDir, "fzf"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cm := &ConfigManager{Picker: pickerFZF, Profiles: map[string]Profile{
+		"personal": {Name: "John Personal", Email: "john.personal@gmail.com"},
+		"work":     {Name: "John Doe", Email: "john.doe@company.com"},
+	}}
+	selected, err := cm.selectProfiles("Select profiles", []string{"personal", "work"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"personal", "work"}, selected)
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Rule requires repositories whose remote URL matches Remote, or that live under Dir, to use Profile
+type Rule struct {
+	Remote  string `json:"remote,omitempty"`
+	Dir     string `json:"dir,omitempty"`
+	Profile string `json:"profile"`
+}
+
+// Target describes what the rule matches, e.g. "remote github.com/acme-*"
+func (r Rule) Target() string {
+	kind, pattern := r.Pattern()
+	return kind + " " + pattern
+}
+
+// Pattern returns what the rule matches, dir or remote, and the pattern it matches with
+func (r Rule) Pattern() (string, string) {
+	if r.Dir != "" {
+		return "dir", r.Dir
+	}
+	return "remote", r.Remote
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(homeDir, path[1:])
+}
+
+// matchDirPattern reports whether repoPath lies under a gitdir-style pattern such as "~/work/"
+func matchDirPattern(pattern string, repoPath string) bool {
+	dirPattern := filepath.ToSlash(expandHome(pattern))
+	if !strings.HasSuffix(dirPattern, "/") {
+		dirPattern += "/"
+	}
+	return matchGlob(dirPattern+"*", filepath.ToSlash(repoPath)+"/")
+}
+
+// repoTopLevel returns the root of the working tree containing dir
+func repoTopLevel(dir string) string {
+	topLevel, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return ""
+	}
+	return topLevel
+}
+
+// repoRemoteURLs returns the URLs of every remote configured in the repository at dir
+func repoRemoteURLs(dir string) []string {
+	entries, err := gitConfigEntries(dir, nil, `^remote\..*\.url$`)
+	if err != nil {
+		return nil
+	}
+
+	var urls []string
+	for _, entry := range entries {
+		urls = append(urls, entry.Value)
+	}
+	sort.Strings(urls)
+	return urls
+}
+
+// matches reports whether the rule applies to a repository at repoPath with the given remote URLs
+func (r Rule) matches(repoPath string, urls []string) bool {
+	if r.Dir != "" {
+		return repoPath != "" && matchDirPattern(r.Dir, repoPath)
+	}
+
+	for _, url := range urls {
+		if matchRemotePattern(r.Remote, url) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchRule returns the first rule applying to the repository at dir
+func (cm *ConfigManager) matchRule(dir string) (Rule, bool) {
+	repoPath, urls := repoTopLevel(dir), repoRemoteURLs(dir)
+	for _, rule := range cm.Rules {
+		if rule.matches(repoPath, urls) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// expectedProfile returns the profile commits in dir must use: the one required by policy, else the assigned one
+func (cm *ConfigManager) expectedProfile(dir string) (string, error) {
+	if rule, found := cm.matchRule(dir); found {
+		if _, exists := cm.Profiles[rule.Profile]; !exists {
+			return "", errorf("policy for %s requires profile '%s', which doesn't exist", rule.Target(), rule.Profile)
+		}
+		return rule.Profile, nil
+	}
+
+	return gitConfigGet(dir, assignedProfileKey)
+}
+
+// checkPolicy verifies that the identity configured in dir satisfies the matching rule
+func (cm *ConfigManager) checkPolicy(dir string) error {
+	rule, found := cm.matchRule(dir)
+	if !found {
+		return nil
+	}
+
+	saved, exists := cm.Profiles[rule.Profile]
+	if !exists {
+		return errorf("policy for %s requires profile '%s', which doesn't exist", rule.Target(), rule.Profile)
+	}
+	profile, err := resolveProfile(saved)
+	if err != nil {
+		return errorf("profile '%s': %w", rule.Profile, err)
+	}
+
+	email, err := gitConfigGet(dir, "user.email")
+	if err != nil {
+		return err
+	}
+	if !profile.OwnsEmail(email) {
+		return errorf("policy for %s requires profile '%s' <%s>, but user.email is <%s>", rule.Target(), rule.Profile, profile.Email, email)
+	}
+	return nil
+}
+
+// checkPolicyFinding reports policy violations as doctor findings
+func checkPolicyFinding(cm *ConfigManager, dir string) []doctorFinding {
+	if err := cm.checkPolicy(dir); err != nil {
+		return []doctorFinding{{severityError, err.Error()}}
+	}
+	return nil
+}
+
+// newRulesCmd builds the rules command group
+func newRulesCmd(configManager *ConfigManager) *cobra.Command {
+	var rulesCmd = &cobra.Command{
+		Use:   "rules",
+		Short: "Manage policy rules mapping remotes to required profiles",
+	}
+
+	var listRulesCmd = &cobra.Command{
+		Use:   "ls",
+		Short: "List policy rules in evaluation order",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(configManager.Rules) == 0 {
+				fmt.Fprintln(stdout, tr("No rules found. Use 'git profile rules add' to create a rule."))
+				return
+			}
+
+			for i, rule := range configManager.Rules {
+				fmt.Fprintf(stdout, "%d. %s → %s\n", i+1, rule.Target(), rule.Profile)
+			}
+		},
+	}
+
+	var remote, dir string
+	var addRuleCmd = &cobra.Command{
+		Use:   "add <profile> (--remote <pattern> | --dir <path>)",
+		Short: "Require a profile for matching remotes or directories",
+		Example: "  git profile rules add work --remote 'github.com/acme-*'\n" +
+			"  git profile rules add oss --dir ~/src/oss/",
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if _, exists := configManager.Profiles[args[0]]; !exists {
+				fmt.Fprintf(stdout, tr("Profile '%s' not found.\n"), args[0])
+				os.Exit(1)
+			}
+			if (remote == "") == (dir == "") {
+				fmt.Fprintln(stdout, tr("Specify exactly one of --remote or --dir."))
+				os.Exit(1)
+			}
+
+			rule := Rule{Remote: remote, Dir: dir, Profile: args[0]}
+			configManager.Rules = append(configManager.Rules, rule)
+			configManager.save()
+
+			fmt.Fprintf(notices, tr("Rule added: %s → %s\n"), rule.Target(), rule.Profile)
+		},
+	}
+	addRuleCmd.Flags().StringVar(&remote, "remote", "", "Remote URL pattern, e.g. github.com/acme-*")
+	addRuleCmd.Flags().StringVar(&dir, "dir", "", "Directory whose repositories use the profile, e.g. ~/work/")
+
+	var removeRuleCmd = &cobra.Command{
+		Use:   "rm <number>",
+		Short: "Remove a policy rule by its number in 'rules ls'",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			index, err := strconv.Atoi(args[0])
+			if err != nil || index < 1 || index > len(configManager.Rules) {
+				fmt.Fprintf(stdout, tr("Rule '%s' not found.\n"), args[0])
+				os.Exit(1)
+			}
+
+			rule := configManager.Rules[index-1]
+			configManager.Rules = append(configManager.Rules[:index-1], configManager.Rules[index:]...)
+			configManager.save()
+
+			fmt.Fprintf(notices, tr("Rule removed: %s → %s\n"), rule.Target(), rule.Profile)
+		},
+	}
+
+	var installRulesCmd = &cobra.Command{
+		Use:   "install",
+		Short: "Write includeIf sections so Git selects profiles by rule automatically",
+		Run: func(cmd *cobra.Command, args []string) {
+			includesPath, err := includeDir()
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Install failed:"), err)
+				os.Exit(1)
+			}
+
+			count, err := configManager.installIncludes([]string{"--global"}, includesPath)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Install failed:"), err)
+				os.Exit(1)
+			}
+
+			configManager.recordHistory("install-rules", "", "", scopeGlobal)
+			fmt.Fprintf(notices, tr("Installed %d includeIf section(s) into the global Git config.\n"), count)
+		},
+	}
+
+	var uninstallRulesCmd = &cobra.Command{
+		Use:   "uninstall",
+		Short: "Remove includeIf sections written by 'rules install'",
+		Run: func(cmd *cobra.Command, args []string) {
+			includesPath, err := includeDir()
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Uninstall failed:"), err)
+				os.Exit(1)
+			}
+
+			count, err := uninstallIncludes([]string{"--global"}, includesPath)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Uninstall failed:"), err)
+				os.Exit(1)
+			}
+
+			configManager.recordHistory("uninstall-rules", "", "", scopeGlobal)
+			fmt.Fprintf(notices, tr("Removed %d includeIf section(s) from the global Git config.\n"), count)
+		},
+	}
+
+	rulesCmd.AddCommand(listRulesCmd, addRuleCmd, removeRuleCmd, installRulesCmd, uninstallRulesCmd)
+	return rulesCmd
+}
+
+// newCheckCmd builds the check command
+func newCheckCmd(configManager *ConfigManager) *cobra.Command {
+	var checkCmd = &cobra.Command{
+		Use:   "check",
+		Short: "Check the current repository against policy rules",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := configManager.checkPolicy("."); err != nil {
+				fmt.Fprintf(stdout, "❌ %v\n", err)
+				os.Exit(1)
+			}
+
+			if rule, found := configManager.matchRule("."); found {
+				fmt.Fprintf(notices, tr("✅ Repository complies with policy %s → %s\n"), rule.Target(), rule.Profile)
+			} else {
+				fmt.Fprintln(notices, tr("✅ No policy rule applies to this repository."))
+			}
+		},
+	}
+
+	return checkCmd
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPolicy tests matching rules and enforcing the required profile
+func TestPolicy(t *testing.T) {
+	repoDir := initTestRepo(t)
+	cm := &ConfigManager{
+		Profiles: map[string]Profile{
+			"work":     {Name: "John Doe", Email: "john.doe@company.com"},
+			"personal": {Name: "John Personal", Email: "john.personal@gmail.com"},
+		},
+		Rules: []Rule{
+			{Remote: "github.com/acme-*/*", Profile: "work"},
+			{Remote: "github.com/*", Profile: "personal"},
+		},
+	}
+
+	_, err := runGit(repoDir, "remote", "add", "origin", "git@github.com:acme-platform/api.git")
+	assert.NoError(t, err)
+
+	// First matching rule wins
+	rule, found := cm.matchRule(repoDir)
+	assert.True(t, found)
+	assert.Equal(t, "work", rule.Profile)
+
+	expected, err := cm.expectedProfile(repoDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "work", expected)
+
+	assert.NoError(t, applyProfile(repoDir, "personal", cm.Profiles["personal"]))
+	assert.Error(t, cm.checkPolicy(repoDir))
+
+	assert.NoError(t, applyProfile(repoDir, "work", cm.Profiles["work"]))
+	assert.NoError(t, cm.checkPolicy(repoDir))
+
+	// An alias of the required profile complies too
+	work := cm.Profiles["work"]
+	work.EmailAliases = []string{"john.doe@oldcorp.com"}
+	cm.Profiles["work"] = work
+	_, err = runGit(repoDir, "config", "user.email", "john.doe@oldcorp.com")
+	assert.NoError(t, err)
+	assert.NoError(t, cm.checkPolicy(repoDir))
+
+	// Rules pointing at missing profiles are reported
+	cm.Rules = []Rule{{Remote: "github.com/*", Profile: "deleted"}}
+	assert.Error(t, cm.checkPolicy(repoDir))
+	_, err = cm.expectedProfile(repoDir)
+	assert.Error(t, err)
+}
+
+// TestDirRule tests rules matching repositories by directory
+func TestDirRule(t *testing.T) {
+	repoDir := initTestRepo(t)
+	topLevel := repoTopLevel(repoDir)
+	cm := &ConfigManager{
+		Profiles: map[string]Profile{
+			"oss": {Name: "John Doe", Email: "john@oss.dev"},
+		},
+		Rules: []Rule{{Dir: filepath.Dir(topLevel), Profile: "oss"}},
+	}
+
+	rule, found := cm.matchRule(repoDir)
+	assert.True(t, found)
+	assert.Equal(t, "dir "+filepath.Dir(topLevel), rule.Target())
+
+	assert.True(t, matchDirPattern("/home/me/work/", "/home/me/work/api"))
+	assert.True(t, matchDirPattern("/home/me/*/", "/home/me/work/api"))
+	assert.False(t, matchDirPattern("/home/me/work", "/home/me/workshop"))
+}
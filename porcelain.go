@@ -0,0 +1,118 @@
+package main
+
+import (
+	"io"
+	"slices"
+	"strings"
+)
+
+// porcelainEmpty stands in for an empty porcelain field, so consecutive tabs never collapse when the shell splits a line
+const porcelainEmpty = "-"
+
+// porcelainField makes a value safe for a porcelain field: tabs and newlines become spaces and an empty value becomes -
+func porcelainField(value string) string {
+	value = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(value)
+	if value == "" {
+		return porcelainEmpty
+	}
+	return value
+}
+
+// writePorcelain writes one line of tab-separated porcelain fields; the output of --porcelain is untranslated, free
+// of emoji and color, and only ever extended by fields appended at the end of a line or by new record types
+func writePorcelain(w io.Writer, fields ...string) {
+	for i, field := range fields {
+		fields[i] = porcelainField(field)
+	}
+	io.WriteString(w, strings.Join(fields, "\t")+"\n")
+}
+
+// writeProfilePorcelain prints ls --porcelain: per profile a state (* active, ? possibly active, ! active but violating
+// a policy, - otherwise), the name, user.name, email, comma-separated tags and flags (shared, fragment, locked, archived)
+func (cm *ConfigManager) writeProfilePorcelain(w io.Writer, names []string, active []string, ambiguous bool, violation error) {
+	for _, name := range names {
+		profile := cm.Profiles[name]
+		state := porcelainEmpty
+		if slices.Contains(active, name) {
+			switch {
+			case violation != nil:
+				state = "!"
+			case ambiguous:
+				state = "?"
+			default:
+				state = "*"
+			}
+		}
+
+		var flags []string
+		if _, shared := cm.sourceOf(name); shared {
+			flags = append(flags, "shared")
+		}
+		if _, owned := cm.fragmentOf(name); owned {
+			flags = append(flags, "fragment")
+		}
+		if profile.Locked {
+			flags = append(flags, "locked")
+		}
+		if profile.Archived {
+			flags = append(flags, "archived")
+		}
+		writePorcelain(w, state, name, profile.Name, profile.Email, strings.Join(profile.Tags, ","), strings.Join(flags, ","))
+	}
+}
+
+// writeSelectionPorcelain prints which --porcelain: a repository line, a remote line per remote URL, a rule line per
+// rule with its verdict (selected, missing, shadowed or no-match), kind, pattern and profile, and last a profile line
+// with the selected profile (- for none), what selected it (rule or pattern) and the rule pattern or remote pattern
+func writeSelectionPorcelain(w io.Writer, selection profileSelection) {
+	writePorcelain(w, "repository", selection.Repo)
+	for _, url := range selection.Remotes {
+		writePorcelain(w, "remote", url)
+	}
+	for _, verdict := range selection.Rules {
+		outcome := "no-match"
+		switch {
+		case verdict.Selected:
+			outcome = "selected"
+		case verdict.Missing:
+			outcome = "missing"
+		case verdict.Matches:
+			outcome = "shadowed"
+		}
+		kind, pattern := verdict.Rule.Pattern()
+		writePorcelain(w, "rule", outcome, kind, pattern, verdict.Rule.Profile)
+	}
+	writePorcelain(w, "profile", selection.Profile, selection.Via, selection.Match)
+}
+
+// writeStatusPorcelain prints status --porcelain for the repository at dir: a repository line with its path and the
+// profile apply recorded there, per identity key a source line per value in the order Git reads them (so the last one
+// wins) with the scope, origin file and value, an override line per environment variable taking precedence when
+// committing with its value, and last a profile line with the profile in use
+func (cm *ConfigManager) writeStatusPorcelain(w io.Writer, dir string) error {
+	assigned := ""
+	repo := repoTopLevel(dir)
+	if repo != "" {
+		assigned, _ = gitConfigGet(dir, assignedProfileKey)
+	}
+	writePorcelain(w, "repository", repo, assigned)
+
+	mainFiles := mainConfigFiles(dir)
+	for _, key := range append(statusKeys, roleStatusKeys...) {
+		resolution, err := resolveConfigKey(dir, key, mainFiles)
+		if err != nil {
+			return err
+		}
+		for _, source := range resolution.Sources {
+			writePorcelain(w, "source", key, source.Scope, source.Origin, source.Value)
+		}
+		for _, override := range resolution.Overrides {
+			variable, value, _ := strings.Cut(override, "=")
+			writePorcelain(w, "override", key, variable, value)
+		}
+	}
+
+	name, _ := cm.appliedProfile(dir)
+	writePorcelain(w, "profile", name)
+	return nil
+}
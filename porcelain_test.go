@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPorcelain tests the stable line-oriented output of ls, which and status
+func TestPorcelain(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(tmpDir, ".gitconfig"))
+
+	// Fields never contain tabs or newlines, and empty ones are spelled out so the shell can split lines
+	var out bytes.Buffer
+	writePorcelain(&out, "a\tb", "", "c\nd")
+	assert.Equal(t, "a b\t-\tc d\n", out.String())
+
+	cm := &ConfigManager{
+		Profiles: map[string]Profile{
+			"work": {Name: "John Doe", Email: "john.doe@company.com", Tags: []string{"client", "work"}, Locked: true},
+			"oss":  {Name: "John Doe", Email: "john@oss.dev"},
+		},
+	}
+	out.Reset()
+	cm.writeProfilePorcelain(&out, []string{"oss", "work"}, []string{"work"}, false, nil)
+	assert.Equal(t, "-\toss\tJohn Doe\tjohn@oss.dev\t-\t-\n*\twork\tJohn Doe\tjohn.doe@company.com\tclient,work\tlocked\n", out.String())
+	out.Reset()
+	cm.writeProfilePorcelain(&out, []string{"work"}, []string{"work"}, false, errors.New("policy"))
+	assert.Equal(t, "!\twork\tJohn Doe\tjohn.doe@company.com\tclient,work\tlocked\n", out.String())
+
+	repoDir := initTestRepo(t)
+	repoDir, _ = filepath.EvalSymlinks(repoDir)
+	_, err = runGit(repoDir, "remote", "add", "origin", "git@github.com:acme/api.git")
+	assert.NoError(t, err)
+	cm.Rules = []Rule{{Dir: "~/elsewhere/", Profile: "oss"}, {Remote: "github.com/acme/*", Profile: "work"}}
+
+	out.Reset()
+	writeSelectionPorcelain(&out, cm.explainProfile(repoDir))
+	assert.Equal(t, "repository\t"+repoDir+"\n"+
+		"remote\tgit@github.com:acme/api.git\n"+
+		"rule\tno-match\tdir\t~/elsewhere/\toss\n"+
+		"rule\tselected\tremote\tgithub.com/acme/*\twork\n"+
+		"profile\twork\trule\tgithub.com/acme/*\n", out.String())
+
+	assert.NoError(t, applyProfile(repoDir, "work", cm.Profiles["work"]))
+	out.Reset()
+	assert.NoError(t, cm.writeStatusPorcelain(&out, repoDir))
+	localConfig := filepath.Join(repoDir, ".git", "config")
+	assert.Contains(t, out.String(), "repository\t"+repoDir+"\twork\n")
+	assert.Contains(t, out.String(), "source\tuser.email\tlocal\t"+localConfig+"\tjohn.doe@company.com\n")
+	assert.Contains(t, out.String(), "\nprofile\twork\n")
+
+	t.Setenv("GIT_AUTHOR_EMAIL", "jdoe@example.com")
+	out.Reset()
+	assert.NoError(t, cm.writeStatusPorcelain(&out, repoDir))
+	assert.Contains(t, out.String(), "override\tuser.email\tGIT_AUTHOR_EMAIL\tjdoe@example.com\n")
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// profileChecks holds the flags controlling profile validation in add, edit and import
+type profileChecks struct {
+	Strict bool
+	MX     bool
+}
+
+// addFlags registers the validation flags on cmd
+func (c *profileChecks) addFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&c.Strict, "strict", false, "Fail instead of warning when a profile looks invalid")
+	cmd.Flags().BoolVar(&c.MX, "check-mx", false, "Also check that the email domain accepts mail (needs network)")
+}
+
+// openPGPKeyPattern matches OpenPGP key IDs and fingerprints, optionally 0x-prefixed or forced with '!'
+var openPGPKeyPattern = regexp.MustCompile(`^(0[xX])?([0-9a-fA-F]{8}|[0-9a-fA-F]{16}|[0-9a-fA-F]{40}|[0-9a-fA-F]{64})!?$`)
+
+// profileNamePattern is the character set allowed in profile names, safe in file names and gitconfig section names
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// profileKeywords are words that stand in for a profile, or for none, where commands take one
+var profileKeywords = []string{"all", "default", "new", "none"}
+
+// reservedProfileNames look like commands or keywords, so a profile can't shadow them as a positional argument; the
+// command names are added by reserveCommandNames once the commands are registered
+var reservedProfileNames = profileKeywords
+
+// reserveCommandNames reserves the names and aliases of every command of root, including the help and completion
+// commands cobra only adds when it executes
+func reserveCommandNames(root *cobra.Command) {
+	root.InitDefaultHelpCmd()
+	root.InitDefaultCompletionCmd()
+
+	names := slices.Clone(profileKeywords)
+	for _, cmd := range root.Commands() {
+		names = append(names, cmd.Name())
+		names = append(names, cmd.Aliases...)
+	}
+	reservedProfileNames = names
+}
+
+// validateProfileName checks that name is usable as a profile name
+func validateProfileName(name string) error {
+	if name == "" {
+		return errorf("profile name cannot be empty")
+	}
+	if !profileNamePattern.MatchString(name) {
+		return errorf("profile name '%s' must start with a letter or digit and only contain letters, digits, '.', '_' and '-' (at most 64 characters)", name)
+	}
+	if slices.Contains(reservedProfileNames, strings.ToLower(name)) {
+		return errorf("profile name '%s' is reserved", name)
+	}
+	return nil
+}
+
+// lookupMX resolves the mail servers of a domain
+var lookupMX = net.LookupMX
+
+// validateEmail checks that email is a bare address with a plausible domain
+func validateEmail(email string) error {
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email || address.Name != "" {
+		return errorf("'%s' is not a valid email address", email)
+	}
+	if _, domain, _ := strings.Cut(email, "@"); !strings.Contains(strings.Trim(domain, "."), ".") {
+		return errorf("email domain of '%s' has no top-level domain", email)
+	}
+	return nil
+}
+
+// validateSigningKey checks that a signing key looks like its format expects
+func validateSigningKey(profile Profile) error {
+	key := profile.Signing.Key
+	if key == "" || profile.SigningFormat() != signingFormatOpenPGP {
+		return nil
+	}
+	// gpg also accepts user IDs, which carry an email address
+	if openPGPKeyPattern.MatchString(strings.ReplaceAll(key, " ", "")) || strings.Contains(key, "@") {
+		return nil
+	}
+	return errorf("signing key '%s' doesn't look like an OpenPGP key ID, fingerprint or email", key)
+}
+
+// validateProfile returns everything that looks wrong with a profile
+func validateProfile(profile Profile, checkMX bool) []error {
+	var problems []error
+	if strings.TrimSpace(profile.Name) == "" {
+		problems = append(problems, errors.New(tr("name is empty")))
+	}
+
+	if err := validateEmail(profile.Email); err != nil {
+		problems = append(problems, err)
+	} else if checkMX {
+		_, domain, _ := strings.Cut(profile.Email, "@")
+		if records, err := lookupMX(domain); err != nil || len(records) == 0 {
+			problems = append(problems, errorf("email domain '%s' has no mail servers", domain))
+		}
+	}
+
+	if profile.Author.Email != "" {
+		if err := validateEmail(profile.Author.Email); err != nil {
+			problems = append(problems, errorf("author email: %w", err))
+		}
+	}
+	if profile.Committer.Email != "" {
+		if err := validateEmail(profile.Committer.Email); err != nil {
+			problems = append(problems, errorf("committer email: %w", err))
+		}
+	}
+
+	for _, alias := range profile.EmailAliases {
+		if err := validateEmail(alias); err != nil {
+			problems = append(problems, errorf("email alias: %w", err))
+		}
+	}
+
+	if err := validateSigningKey(profile); err != nil {
+		problems = append(problems, err)
+	}
+
+	// Proxies may take credentials from {{env "..."}} placeholders, which only resolve when applied
+	for _, proxy := range []string{profile.Network.HTTPProxy, profile.Network.HTTPSProxy} {
+		if proxy != "" && !strings.Contains(proxy, "{{") {
+			if err := validateProxyURL(proxy); err != nil {
+				problems = append(problems, err)
+			}
+		}
+	}
+	return problems
+}
+
+// validateProxies checks that a profile sets at most one of the HTTP and HTTPS proxies, since Git sends both kinds of
+// traffic through the one http.proxy and the other would be silently dropped
+func validateProxies(profile Profile) error {
+	if profile.Network.HTTPProxy != "" && profile.Network.HTTPSProxy != "" {
+		return errorf("set either an HTTP or an HTTPS proxy, not both: Git uses a single http.proxy for every remote")
+	}
+	return nil
+}
+
+// check validates a profile, warning about problems or, under --strict, returning them as an error; profiles that
+// can't be applied as saved are always refused
+func (c profileChecks) check(name string, profile Profile) error {
+	if err := validateProxies(profile); err != nil {
+		return errorf("profile '%s': %w", name, err)
+	}
+
+	problems := validateProfile(profile, c.MX)
+	if len(problems) == 0 {
+		return nil
+	}
+
+	if c.Strict {
+		messages := make([]string, len(problems))
+		for i, problem := range problems {
+			messages[i] = problem.Error()
+		}
+		return errorf("profile '%s': %s", name, strings.Join(messages, "; "))
+	}
+	for _, problem := range problems {
+		warnf("profile '%s': %v", name, problem)
+	}
+	return nil
+}
+
+// checkProfileNames reports saved profiles whose names predate the naming rules
+func checkProfileNames(cm *ConfigManager, dir string) []doctorFinding {
+	var names []string
+	for name := range cm.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var findings []doctorFinding
+	for _, name := range names {
+		if err := validateProfileName(name); err != nil {
+			findings = append(findings, doctorFinding{severityWarning, fmt.Sprintf(tr("%v; recreate the profile under another name"), err)})
+		}
+	}
+	return findings
+}
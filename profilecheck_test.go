@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProfileChecks tests validating emails, names and signing keys
+func TestProfileChecks(t *testing.T) {
+	assert.NoError(t, validateEmail("john.doe@company.com"))
+	assert.Error(t, validateEmail("john.doe@company"))
+	assert.Error(t, validateEmail("john.doe.company.com"))
+	assert.Error(t, validateEmail("John Doe <john.doe@company.com>"))
+
+	profile := Profile{Name: "John Doe", Email: "john.doe@company.com"}
+	for _, key := range []string{"ABCD1234", "0x1234567890ABCDEF", "1234 5678 9ABC DEF0 1234 5678 9ABC DEF0 1234 5678", "john.doe@company.com", "~/.ssh/id_work.pub"} {
+		profile.Signing.Key = key
+		assert.Empty(t, validateProfile(profile, false), key)
+	}
+	profile.Signing.Key = "my-key"
+	assert.Len(t, validateProfile(profile, false), 1)
+
+	assert.Len(t, validateProfile(Profile{Email: "john.doe@"}, false), 2)
+
+	defer func(original func(string) ([]*net.MX, error)) { lookupMX = original }(lookupMX)
+	lookupMX = func(domain string) ([]*net.MX, error) { return nil, errors.New("no such host") }
+	assert.Len(t, validateProfile(Profile{Name: "John Doe", Email: "john.doe@company.com"}, true), 1)
+}
+
+// TestStrictImport tests that --strict refuses invalid profiles while the default only warns
+func TestStrictImport(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	importPath := filepath.Join(tmpDir, "import.json")
+	assert.NoError(t, os.WriteFile(importPath, []byte(`{"work": {"name": "John Doe", "email": "john.doe@company"}}`), 0644))
+
+	cm := &ConfigManager{ConfigPath: filepath.Join(tmpDir, ".git-profiles-test.json"), Profiles: map[string]Profile{}}
+	assert.ErrorContains(t, cm.Import(importPath, importOptions{Strategy: importMerge, Checks: profileChecks{Strict: true}}), "profile 'work'")
+	assert.Empty(t, cm.Profiles)
+
+	assert.NoError(t, cm.Import(importPath, importOptions{Strategy: importMerge}))
+	assert.Contains(t, cm.Profiles, "work")
+}
+
+// TestProfileNames tests the allowed characters and reserved profile names
+func TestProfileNames(t *testing.T) {
+	for _, name := range []string{"work", "client-a", "oss_2", "corp.eu"} {
+		assert.NoError(t, validateProfileName(name), name)
+	}
+	// Every command name and alias is reserved, along with the keywords
+	defer func(original []string) { reservedProfileNames = original }(reservedProfileNames)
+	root := &cobra.Command{Use: "git-profile"}
+	root.AddCommand(&cobra.Command{Use: "ls", Aliases: []string{"list"}}, &cobra.Command{Use: "rm [profile...]"})
+	reserveCommandNames(root)
+	assert.NoError(t, validateProfileName("remove"))
+
+	for _, name := range []string{"", "-work", "my work", "work/eu", "wörk", "rm", "LS", "list", "help", "completion", "none", strings.Repeat("a", 65)} {
+		assert.Error(t, validateProfileName(name), name)
+	}
+
+	cm := &ConfigManager{Profiles: map[string]Profile{"work": {}, "my work": {}}}
+	findings := checkProfileNames(cm, "")
+	assert.Len(t, findings, 1)
+	assert.Contains(t, findings[0].Message, "'my work'")
+}
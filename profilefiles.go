@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+)
+
+// ProfileFile is a file a profile points Git at, either by path or embedded in the profile
+type ProfileFile struct {
+	Path    string `json:"path,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+// IsSet reports whether the profile file references or embeds anything
+func (f ProfileFile) IsSet() bool {
+	return f.Path != "" || f.Content != ""
+}
+
+// profileFilesDir returns the directory embedded profile files are written out to
+func profileFilesDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "git-profile", "files"), nil
+}
+
+// location returns the file Git should read: the referenced path, or where the embedded content is written
+func (f ProfileFile) location(name string, kind string) string {
+	if f.Content == "" {
+		return expandHome(f.Path)
+	}
+
+	dir, err := profileFilesDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, name, kind)
+}
+
+// materialize writes embedded content to its location so Git can read it
+func (f ProfileFile) materialize(name string, kind string) error {
+	if f.Content == "" {
+		return nil
+	}
+
+	path := f.location(name, kind)
+	if path == "" {
+		return errorf("can't resolve the location of the %s file", kind)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(f.Content), 0644)
+}
+
+// Kinds of files a profile can carry, named as materialized under profileFilesDir
+const (
+	commitTemplateFile = "commit-template"
+	excludesFile       = "excludes"
+)
+
+// profileFiles lists a profile's files by kind
+func profileFiles(profile Profile) map[string]ProfileFile {
+	return map[string]ProfileFile{
+		commitTemplateFile: profile.CommitTemplate,
+		excludesFile:       profile.Excludes,
+	}
+}
+
+// materializeProfileFiles writes out every embedded file of a profile before its config is applied
+func materializeProfileFiles(name string, profile Profile) error {
+	for kind, file := range profileFiles(profile) {
+		if err := file.materialize(name, kind); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// promptProfileFile asks for a file path and whether to embed its contents in the profile
+func promptProfileFile(reader *bufio.Reader, label string, current ProfileFile) ProfileFile {
+	currentPath := current.Path
+	if current.Content != "" {
+		currentPath = "(embedded)"
+	}
+
+	path := promptString(reader, label, currentPath)
+	switch path {
+	case "":
+		return ProfileFile{}
+	case currentPath:
+		return current
+	}
+
+	if !promptBool(reader, tr("Embed the file contents in the profile?"), false) {
+		return ProfileFile{Path: path}
+	}
+	data, err := os.ReadFile(expandHome(path))
+	if err != nil {
+		warnf("%v, referencing the path instead", err)
+		return ProfileFile{Path: path}
+	}
+	return ProfileFile{Content: string(data)}
+}
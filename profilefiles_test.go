@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCommitTemplate tests writing out embedded templates and referencing template paths on apply
+func TestCommitTemplate(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(homeDir)
+	t.Setenv("HOME", homeDir)
+
+	repoDir := initTestRepo(t)
+
+	work := Profile{Name: "John Doe", Email: "john.doe@company.com"}
+	work.CommitTemplate = ProfileFile{Content: "[TICKET-] \n\nWhy:\n"}
+	assert.NoError(t, applyProfile(repoDir, "work", work))
+
+	path, err := gitConfigGet(repoDir, "commit.template")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(homeDir, ".config", "git-profile", "files", "work", commitTemplateFile), path)
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "[TICKET-] \n\nWhy:\n", string(data))
+
+	// Referenced templates are used in place, and profiles without one drop the setting
+	personal := Profile{Name: "John Personal", Email: "john.personal@gmail.com"}
+	personal.CommitTemplate = ProfileFile{Path: "~/.gitmessage"}
+	assert.NoError(t, applyProfile(repoDir, "personal", personal))
+	path, err = gitConfigGet(repoDir, "commit.template")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(homeDir, ".gitmessage"), path)
+
+	assert.NoError(t, applyProfile(repoDir, "plain", Profile{Name: "John Doe", Email: "john@oss.dev"}))
+	path, err = gitConfigGet(repoDir, "commit.template")
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+}
+
+// TestExcludesFile tests that a profile's embedded gitignore fragment hides files on apply
+func TestExcludesFile(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(homeDir)
+	t.Setenv("HOME", homeDir)
+
+	repoDir := initTestRepo(t)
+	assert.NoError(t, os.MkdirAll(filepath.Join(repoDir, ".idea"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(repoDir, ".idea", "workspace.xml"), []byte("<xml/>"), 0644))
+
+	work := Profile{Name: "John Doe", Email: "john.doe@company.com"}
+	work.Excludes = ProfileFile{Content: ".idea/\n"}
+	assert.NoError(t, applyProfile(repoDir, "work", work))
+
+	status, err := runGit(repoDir, "status", "--porcelain")
+	assert.NoError(t, err)
+	assert.NotContains(t, status, ".idea")
+
+	assert.NoError(t, unapplyProfile(repoDir))
+	status, err = runGit(repoDir, "status", "--porcelain")
+	assert.NoError(t, err)
+	assert.Contains(t, status, ".idea")
+}
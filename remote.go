@@ -0,0 +1,87 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// normalizeRemoteURL reduces SSH, SCP-style and HTTPS remote URLs to "host/path" without a .git suffix, lowercasing the host
+func normalizeRemoteURL(url string) string {
+	normalized := strings.TrimSpace(url)
+
+	if i := strings.Index(normalized, "://"); i >= 0 {
+		// scheme://[user@]host[:port]/path
+		normalized = normalized[i+3:]
+		if at := strings.Index(normalized, "@"); at >= 0 && at < strings.Index(normalized+"/", "/") {
+			normalized = normalized[at+1:]
+		}
+		if slash := strings.Index(normalized, "/"); slash >= 0 {
+			host := normalized[:slash]
+			if colon := strings.Index(host, ":"); colon >= 0 {
+				host = host[:colon]
+			}
+			normalized = host + normalized[slash:]
+		}
+	} else if colon := strings.Index(normalized, ":"); colon >= 0 {
+		// [user@]host:path
+		host := normalized[:colon]
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+		normalized = host + "/" + strings.TrimPrefix(normalized[colon+1:], "/")
+	}
+
+	normalized = strings.TrimSuffix(strings.TrimSuffix(normalized, "/"), ".git")
+	if host, path, found := strings.Cut(normalized, "/"); found {
+		return strings.ToLower(host) + "/" + path
+	}
+	return strings.ToLower(normalized)
+}
+
+// matchGlob reports whether s matches pattern, where * matches any sequence (including /) and ? any character
+func matchGlob(pattern string, s string) bool {
+	quoted := regexp.QuoteMeta(pattern)
+	quoted = strings.ReplaceAll(quoted, `\*`, ".*")
+	quoted = strings.ReplaceAll(quoted, `\?`, ".")
+
+	matched, err := regexp.MatchString("^"+quoted+"$", s)
+	return err == nil && matched
+}
+
+// matchRemotePattern reports whether a remote URL matches a glob pattern such as "github.com/mycorp/*"
+func matchRemotePattern(pattern string, url string) bool {
+	return matchGlob(strings.ToLower(normalizeRemoteURL(pattern)), strings.ToLower(normalizeRemoteURL(url)))
+}
+
+// suggestProfile returns the profile whose remote pattern (or host) most specifically matches url
+func (cm *ConfigManager) suggestProfile(url string) (string, bool) {
+	name, _, found := cm.matchProfilePattern(url)
+	return name, found
+}
+
+// matchProfilePattern returns the profile suggested for url along with the remote pattern that won
+func (cm *ConfigManager) matchProfilePattern(url string) (string, string, bool) {
+	if url == "" {
+		return "", "", false
+	}
+
+	suggested, winner := "", ""
+	for _, name := range cm.visibleProfiles() {
+		for _, pattern := range cm.Profiles[name].remotePatterns() {
+			if matchRemotePattern(pattern, url) && (suggested == "" || len(pattern) > len(winner)) {
+				suggested, winner = name, pattern
+			}
+		}
+	}
+
+	return suggested, winner, suggested != ""
+}
+
+// suggestProfileForRepo suggests a profile based on the origin remote of the repository at dir
+func (cm *ConfigManager) suggestProfileForRepo(dir string) (string, bool) {
+	url, err := gitConfigGet(dir, "remote.origin.url")
+	if err != nil {
+		return "", false
+	}
+	return cm.suggestProfile(url)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/manifoldco/promptui"
+)
+
+// profileReferences is what still points at profiles about to be removed
+type profileReferences struct {
+	Repos []string
+	Rules []Rule
+}
+
+// IsEmpty reports whether nothing refers to the profiles
+func (r profileReferences) IsEmpty() bool {
+	return len(r.Repos) == 0 && len(r.Rules) == 0
+}
+
+// print lists the references
+func (r profileReferences) print(w io.Writer) {
+	for _, path := range r.Repos {
+		fmt.Fprintf(w, "  📂 %s\n", displayPath(path))
+	}
+	for _, rule := range r.Rules {
+		fmt.Fprintf(w, tr("  📏 rule %s → %s\n"), rule.Target(), rule.Profile)
+	}
+}
+
+// referencesTo lists the repositories registered with any of names and the rules requiring them
+func (cm *ConfigManager) referencesTo(names []string) profileReferences {
+	var refs profileReferences
+	for _, path := range cm.registeredPaths("") {
+		if slices.Contains(names, cm.Repos[path].Profile) {
+			refs.Repos = append(refs.Repos, path)
+		}
+	}
+	for _, rule := range cm.Rules {
+		if slices.Contains(names, rule.Profile) {
+			refs.Rules = append(refs.Rules, rule)
+		}
+	}
+	return refs
+}
+
+// reassignReferences points the repositories and rules using any of names at target instead, reapplying target
+// where one of names is still applied; it returns the repositories that failed
+func (cm *ConfigManager) reassignReferences(names []string, target string) []error {
+	if err := cm.checkNotArchived(target); err != nil {
+		return []error{err}
+	}
+	profile, err := resolveProfile(cm.Profiles[target])
+	if err != nil {
+		return []error{err}
+	}
+
+	for i, rule := range cm.Rules {
+		if slices.Contains(names, rule.Profile) {
+			cm.Rules[i].Profile = target
+		}
+	}
+
+	var failures []error
+	for _, path := range cm.referencesTo(names).Repos {
+		assigned, _ := gitConfigGet(path, assignedProfileKey)
+		if !slices.Contains(names, assigned) {
+			// Applied with another profile since, or gone: the registration is all that's stale
+			delete(cm.Repos, path)
+			continue
+		}
+		if err := applyProfile(path, target, profile); err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", displayPath(path), err))
+			continue
+		}
+		cm.registerRepo(path, target)
+		cm.recordHistory("apply", target, path, scopeLocal)
+	}
+	cm.save()
+	return failures
+}
+
+// clearReferences deletes the rules requiring any of names and unregisters their repositories, removing the settings
+// of the profile from those where it is still applied; it returns the repositories that failed
+func (cm *ConfigManager) clearReferences(names []string) []error {
+	cm.Rules = slices.DeleteFunc(cm.Rules, func(rule Rule) bool {
+		return slices.Contains(names, rule.Profile)
+	})
+
+	var failures []error
+	for _, path := range cm.referencesTo(names).Repos {
+		if assigned, _ := gitConfigGet(path, assignedProfileKey); slices.Contains(names, assigned) {
+			if err := unapplyProfile(path); err != nil {
+				failures = append(failures, fmt.Errorf("%s: %w", displayPath(path), err))
+				continue
+			}
+			cm.recordHistory("unapply", assigned, path, scopeLocal)
+		}
+		delete(cm.Repos, path)
+	}
+	cm.save()
+	return failures
+}
+
+// Ways rm handles the repositories and rules of the removed profiles
+const (
+	removeReassign = "reassign"
+	removeClear    = "clear"
+)
+
+// chooseReferenceAction asks what to do with the references of the profiles being removed, returning the action and,
+// for a reassignment, the profile taking over
+func (cm *ConfigManager) chooseReferenceAction(names []string) (string, string, error) {
+	var targets []string
+	for _, name := range cm.visibleProfiles() {
+		if !slices.Contains(names, name) {
+			targets = append(targets, name)
+		}
+	}
+
+	actions, labels := []string{removeClear, ""}, []string{tr("Clear them: unapply the repositories and delete the rules"), tr("Cancel")}
+	if len(targets) > 0 {
+		actions, labels = append([]string{removeReassign}, actions...), append([]string{tr("Reassign them to another profile")}, labels...)
+	}
+	prompt := promptui.Select{Label: tr("What should happen to them"), Items: labels}
+	index, _, err := prompt.Run()
+	switch {
+	case err != nil || actions[index] == "":
+		return "", "", errorf("removal cancelled")
+	case actions[index] == removeClear:
+		return removeClear, "", nil
+	}
+
+	target, err := cm.selectProfile(tr("Reassign to"), targets, 0)
+	if err != nil {
+		return "", "", errorf("removal cancelled")
+	}
+	return removeReassign, target, nil
+}
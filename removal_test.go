@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRemovalReferences tests finding, reassigning and clearing what still uses profiles being removed
+func TestRemovalReferences(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	t.Setenv("HOME", tmpDir)
+
+	newManager := func() (*ConfigManager, string) {
+		cm := &ConfigManager{ConfigPath: filepath.Join(tmpDir, ".git-profiles-test.json"), Profiles: map[string]Profile{
+			"corp":     {Name: "John Doe", Email: "john.doe@company.com"},
+			"personal": {Name: "John Doe", Email: "john@personal.dev"},
+		}, Rules: []Rule{
+			{Remote: "github.com/acme-*", Profile: "corp"},
+			{Dir: "~/oss", Profile: "personal"},
+		}}
+		repoDir := initTestRepo(t)
+		assert.NoError(t, applyProfile(repoDir, "corp", cm.Profiles["corp"]))
+		cm.registerRepo(repoDir, "corp")
+		return cm, repoTopLevel(repoDir)
+	}
+
+	cm, repoPath := newManager()
+	refs := cm.referencesTo([]string{"corp"})
+	assert.Equal(t, []string{repoPath}, refs.Repos)
+	assert.Equal(t, []Rule{{Remote: "github.com/acme-*", Profile: "corp"}}, refs.Rules)
+	assert.True(t, cm.referencesTo([]string{"missing"}).IsEmpty())
+
+	// Archived profiles can't take over repositories
+	personal := cm.Profiles["personal"]
+	personal.Archived = true
+	cm.Profiles["personal"] = personal
+	failures := cm.reassignReferences([]string{"corp"}, "personal")
+	assert.Len(t, failures, 1)
+	assert.ErrorContains(t, failures[0], "archived")
+	assert.Equal(t, "corp", cm.Repos[repoPath].Profile)
+	personal.Archived = false
+	cm.Profiles["personal"] = personal
+
+	// Reassigning reapplies the repository and repoints the rule
+	assert.Empty(t, cm.reassignReferences([]string{"corp"}, "personal"))
+	email, _ := gitConfigGet(repoPath, "user.email")
+	assert.Equal(t, "john@personal.dev", email)
+	assert.Equal(t, "personal", cm.Repos[repoPath].Profile)
+	assert.Equal(t, "personal", cm.Rules[0].Profile)
+	assert.True(t, cm.referencesTo([]string{"corp"}).IsEmpty())
+
+	// Clearing unapplies and unregisters the repository and drops the rule
+	cm, repoPath = newManager()
+	assert.Empty(t, cm.clearReferences([]string{"corp"}))
+	entries, err := gitConfigEntries(repoPath, []string{"--local"}, `^user\.`)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.NotContains(t, cm.Repos, repoPath)
+	assert.Equal(t, []Rule{{Dir: "~/oss", Profile: "personal"}}, cm.Rules)
+}
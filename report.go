@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// repoStatus describes the identity configured in a repository
+type repoStatus struct {
+	Path    string
+	Name    string
+	Email   string
+	Profile string
+}
+
+// findRepos returns every Git repository under root, without descending into repositories it finds
+func findRepos(root string) ([]string, error) {
+	var repos []string
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return fs.SkipDir
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+
+		if _, err := os.Stat(filepath.Join(path, ".git")); err == nil {
+			repos = append(repos, path)
+			return fs.SkipDir
+		}
+		return nil
+	})
+
+	sort.Strings(repos)
+	return repos, err
+}
+
+// inspectRepo reads the effective identity of the repository at path and the profile it corresponds to
+func (cm *ConfigManager) inspectRepo(path string) repoStatus {
+	status := repoStatus{Path: path}
+	status.Name, _ = gitConfigGet(path, "user.name")
+	status.Email, _ = gitConfigGet(path, "user.email")
+	status.Profile, _ = cm.appliedProfile(path)
+	return status
+}
+
+// displayPath shortens paths under the home directory to ~/...
+func displayPath(path string) string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	if rel, err := filepath.Rel(homeDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.Join("~", rel)
+	}
+	return path
+}
+
+// identityTotal sums an identity's authored commits across repositories
+type identityTotal struct {
+	Email    string
+	Authored int
+	Repos    int
+}
+
+// totalCommits aggregates authored commits per email across the given repositories, most active first
+func totalCommits(repos []string) []identityTotal {
+	totals := make(map[string]*identityTotal)
+	for _, repo := range repos {
+		commits, err := listCommits(repo, "--all")
+		if err != nil {
+			continue
+		}
+
+		counted := make(map[string]bool)
+		for _, identity := range auditCommits(commits) {
+			if identity.Authored == 0 {
+				continue
+			}
+
+			key := strings.ToLower(identity.Email)
+			if _, exists := totals[key]; !exists {
+				totals[key] = &identityTotal{Email: identity.Email}
+			}
+			totals[key].Authored += identity.Authored
+			if !counted[key] {
+				counted[key] = true
+				totals[key].Repos++
+			}
+		}
+	}
+
+	var result []identityTotal
+	for _, total := range totals {
+		result = append(result, *total)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Authored != result[j].Authored {
+			return result[i].Authored > result[j].Authored
+		}
+		return result[i].Email < result[j].Email
+	})
+	return result
+}
+
+// newReportCmd builds the report command
+func newReportCmd(configManager *ConfigManager) *cobra.Command {
+	var reportCmd = &cobra.Command{
+		Use:   "report [scan-root]",
+		Short: "Summarize profile usage across registered repositories or those under a directory",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			repos := configManager.registeredPaths("")
+			if len(args) > 0 {
+				var err error
+				if repos, err = findRepos(args[0]); err != nil {
+					fmt.Fprintln(stdout, tr("Report failed:"), err)
+					os.Exit(1)
+				}
+			}
+
+			if len(repos) == 0 {
+				fmt.Fprintln(stdout, tr("No repositories found."))
+				return
+			}
+
+			fmt.Fprintf(stdout, tr("📊 Repositories (%d)\n"), len(repos))
+			writer := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+			for _, repo := range repos {
+				status := configManager.inspectRepo(repo)
+				profile, email := status.Profile, status.Email
+				if profile == "" {
+					profile = tr("UNKNOWN")
+				}
+				if email == "" {
+					email = "-"
+				}
+				changed := "-"
+				if last, found := configManager.lastIdentityChange(status.Path); found {
+					changed = fmt.Sprintf(tr("changed %s"), last.Time.Local().Format("2006-01-02"))
+				}
+				fmt.Fprintf(writer, "  %s\t%s\t%s\t%s\n", displayPath(status.Path), profile, email, changed)
+			}
+			writer.Flush()
+
+			fmt.Fprintln(stdout, tr("\n📊 Commits by identity"))
+			writer = tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+			for _, total := range totalCommits(repos) {
+				profile, found := configManager.findProfileByEmail(total.Email)
+				if !found {
+					profile = tr("UNKNOWN")
+				}
+				fmt.Fprintf(writer, tr("  %s\t%s\t%d commits in %d repos\n"), total.Email, profile, total.Authored, total.Repos)
+			}
+			writer.Flush()
+		},
+	}
+
+	return reportCmd
+}
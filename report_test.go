@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFindRepos tests discovering repositories under a directory
+func TestFindRepos(t *testing.T) {
+	root, err := os.MkdirTemp("", "git-profile-scan")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	for _, repo := range []string{"work/api", "work/web", "oss/lib"} {
+		repoDir := filepath.Join(root, repo)
+		assert.NoError(t, os.MkdirAll(repoDir, 0755))
+		_, err := runGit(repoDir, "init", "--quiet")
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "notes"), 0755))
+
+	repos, err := findRepos(root)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(root, "oss/lib"),
+		filepath.Join(root, "work/api"),
+		filepath.Join(root, "work/web"),
+	}, repos)
+}
+
+// TestTotalCommits tests aggregating commits per identity across repositories
+func TestTotalCommits(t *testing.T) {
+	first := initTestRepo(t)
+	second := initTestRepo(t)
+	commitAs(t, first, "John Doe", "john.doe@company.com")
+	commitAs(t, first, "John Doe", "john.doe@company.com")
+	commitAs(t, second, "John Doe", "John.Doe@company.com")
+	commitAs(t, second, "John Personal", "john.personal@gmail.com")
+
+	totals := totalCommits([]string{first, second})
+	assert.Len(t, totals, 2)
+	assert.Equal(t, 3, totals[0].Authored)
+	assert.Equal(t, 2, totals[0].Repos)
+	assert.Equal(t, "john.personal@gmail.com", totals[1].Email)
+
+	cm := &ConfigManager{
+		Profiles: map[string]Profile{"work": {Name: "John Doe", Email: "john.doe@company.com"}},
+	}
+	assert.NoError(t, applyProfile(first, "work", cm.Profiles["work"]))
+	status := cm.inspectRepo(first)
+	assert.Equal(t, "work", status.Profile)
+	assert.Equal(t, "john.doe@company.com", status.Email)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// RegisteredRepo records the profile applied to a repository
+type RegisteredRepo struct {
+	Profile string     `json:"profile"`
+	Applied *time.Time `json:"applied,omitempty"`
+}
+
+// registerRepo records that profile was applied to the repository containing dir
+func (cm *ConfigManager) registerRepo(dir string, profile string) {
+	repoPath := repoTopLevel(dir)
+	if repoPath == "" {
+		return
+	}
+
+	if cm.Repos == nil {
+		cm.Repos = make(map[string]RegisteredRepo)
+	}
+
+	now := time.Now()
+	cm.Repos[filepath.Clean(repoPath)] = RegisteredRepo{Profile: profile, Applied: &now}
+	cm.save()
+}
+
+// registeredPaths returns the registered repository paths, optionally only those using profile
+func (cm *ConfigManager) registeredPaths(profile string) []string {
+	var paths []string
+	for path, repo := range cm.Repos {
+		if profile == "" || repo.Profile == profile {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// applyRegistered reapplies a profile to every repository registered with it, or only prints the commands on a dry run
+func (cm *ConfigManager) applyRegistered(name string, dryRun bool) error {
+	profile, exists := cm.Profiles[name]
+	if !exists {
+		return errorf("profile '%s' not found", name)
+	}
+
+	paths := cm.registeredPaths(name)
+	if len(paths) == 0 {
+		return errorf("no repositories are registered with profile '%s'", name)
+	}
+
+	if dryRun {
+		for i, path := range paths {
+			if i > 0 {
+				fmt.Fprintln(stdout)
+			}
+			fmt.Fprintf(stdout, "# %s\n", displayPath(path))
+			cm.dryRunApplyHooks(stdout, hookPreApply, name)
+			if err := dryRunApply(stdout, path, name, profile); err != nil {
+				return err
+			}
+			cm.dryRunApplyHooks(stdout, hookPostApply, name)
+		}
+		return nil
+	}
+
+	failed := 0
+	for _, path := range paths {
+		if err := cm.applyWithHooks(path, name, profile); err != nil {
+			failed++
+			fmt.Fprintf(stdout, "  ❌ %s: %v\n", displayPath(path), err)
+			continue
+		}
+		fmt.Fprintf(notices, "  ✅ %s\n", displayPath(path))
+		cm.registerRepo(path, name)
+		cm.recordHistory("apply", name, path, scopeLocal)
+	}
+	cm.markUsed(name)
+
+	fmt.Fprintf(notices, tr("Profile '%s' applied to %d of %d registered repositories.\n"), name, len(paths)-failed, len(paths))
+	if failed > 0 {
+		return errorf("%d repositories failed", failed)
+	}
+	return nil
+}
+
+// newReposCmd builds the repos command group
+func newReposCmd(configManager *ConfigManager) *cobra.Command {
+	var reposCmd = &cobra.Command{
+		Use:   "repos",
+		Short: "Manage the registry of repositories profiles are applied to",
+	}
+
+	var listReposCmd = &cobra.Command{
+		Use:   "ls",
+		Short: "List registered repositories",
+		Run: func(cmd *cobra.Command, args []string) {
+			paths := configManager.registeredPaths("")
+			if len(paths) == 0 {
+				fmt.Fprintln(stdout, tr("No repositories registered. Apply a profile or use 'git profile repos add'."))
+				return
+			}
+
+			for _, path := range paths {
+				repo := configManager.Repos[path]
+				missing := ""
+				if _, err := os.Stat(path); err != nil {
+					missing = tr(" (missing)")
+				}
+				fmt.Fprintf(stdout, "📁 %s%s\n", displayPath(path), missing)
+				fmt.Fprintf(stdout, tr("  💻 Profile: %s, applied %s\n"), repo.Profile, formatTime(repo.Applied))
+			}
+		},
+	}
+
+	var addProfile string
+	var addRepoCmd = &cobra.Command{
+		Use:   "add [path]",
+		Short: "Register a repository with the profile it uses",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			path := "."
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			if repoTopLevel(path) == "" {
+				fmt.Fprintf(stdout, tr("'%s' is not a Git repository.\n"), path)
+				os.Exit(1)
+			}
+
+			profile := addProfile
+			if profile == "" {
+				applied, found := configManager.appliedProfile(path)
+				if !found {
+					fmt.Fprintln(stdout, tr("No profile is applied to this repository; pass --profile."))
+					os.Exit(1)
+				}
+				profile = applied
+			} else if _, exists := configManager.Profiles[profile]; !exists {
+				fmt.Fprintf(stdout, tr("Profile '%s' not found.\n"), profile)
+				os.Exit(1)
+			}
+
+			configManager.registerRepo(path, profile)
+			fmt.Fprintf(notices, tr("Repository %s registered with profile '%s'.\n"), displayPath(repoTopLevel(path)), profile)
+		},
+	}
+	addRepoCmd.Flags().StringVar(&addProfile, "profile", "", "Profile to register the repository with (default: the applied profile)")
+
+	var removeRepoCmd = &cobra.Command{
+		Use:   "rm [path]",
+		Short: "Unregister a repository",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			path := "."
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			// Missing repositories can still be removed by their registered path
+			key := repoTopLevel(path)
+			if key == "" {
+				key, _ = filepath.Abs(path)
+			}
+			key = filepath.Clean(key)
+
+			if _, exists := configManager.Repos[key]; !exists {
+				fmt.Fprintf(stdout, tr("Repository %s is not registered.\n"), displayPath(key))
+				os.Exit(1)
+			}
+
+			delete(configManager.Repos, key)
+			configManager.save()
+			fmt.Fprintf(notices, tr("Repository %s unregistered.\n"), displayPath(key))
+		},
+	}
+
+	reposCmd.AddCommand(listReposCmd, addRepoCmd, removeRepoCmd)
+	return reposCmd
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRepoRegistry tests registering repositories and reapplying profiles to them
+func TestRepoRegistry(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	first := initTestRepo(t)
+	second := initTestRepo(t)
+	cm := &ConfigManager{
+		ConfigPath: filepath.Join(tmpDir, ".git-profiles-test.json"),
+		Profiles: map[string]Profile{
+			"work":     {Name: "John Doe", Email: "john.doe@company.com"},
+			"personal": {Name: "John Personal", Email: "john.personal@gmail.com"},
+		},
+	}
+
+	cm.registerRepo(first, "work")
+	cm.registerRepo(second, "personal")
+	assert.Equal(t, []string{repoTopLevel(first)}, cm.registeredPaths("work"))
+	assert.Len(t, cm.registeredPaths(""), 2)
+
+	// Changed profile values are pushed to every registered repository
+	cm.Profiles["work"] = Profile{Name: "John Doe", Email: "john.doe@newcorp.com"}
+	assert.NoError(t, cm.applyRegistered("work", false))
+
+	email, err := gitConfigGet(first, "user.email")
+	assert.NoError(t, err)
+	assert.Equal(t, "john.doe@newcorp.com", email)
+
+	email, err = gitConfigGet(second, "user.email")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "john.doe@newcorp.com", email)
+
+	assert.Error(t, cm.applyRegistered("missing", false))
+
+	// The registry survives a save/load round trip
+	loaded := &ConfigManager{ConfigPath: cm.ConfigPath}
+	loaded.load()
+	assert.Equal(t, "personal", loaded.Repos[repoTopLevel(second)].Profile)
+}
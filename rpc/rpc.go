@@ -0,0 +1,114 @@
+// Package rpc defines the messages of git-profile's editor protocol: newline-delimited JSON-RPC 2.0 over the stdio
+// of `git-profile --rpc`. Each request is one line on stdin and each response one line on stdout.
+//
+// These types are the wire format. Fields are only ever added, never renamed or removed, within a ProtocolVersion.
+package rpc
+
+import "encoding/json"
+
+// ProtocolVersion is bumped on any incompatible change to the messages
+const ProtocolVersion = 1
+
+// Methods understood by the server
+const (
+	MethodVersion    = "version"
+	MethodList       = "list"
+	MethodCurrent    = "current"
+	MethodApply      = "apply"
+	MethodRulesMatch = "rules.match"
+)
+
+// Error codes: the JSON-RPC 2.0 ones, then git-profile's own
+const (
+	CodeParseError      = -32700
+	CodeInvalidRequest  = -32600
+	CodeMethodNotFound  = -32601
+	CodeInvalidParams   = -32602
+	CodeInternalError   = -32603
+	CodeProfileNotFound = -32001
+	CodeApplyFailed     = -32002
+	CodeConfigInvalid   = -32003
+)
+
+// Request is a JSON-RPC request; without an ID it is a notification and gets no response
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response carries either a Result or an Error
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// VersionResult answers MethodVersion
+type VersionResult struct {
+	Protocol int    `json:"protocol"`
+	Version  string `json:"version"`
+}
+
+// Profile is a profile as shown to editors
+type Profile struct {
+	Name          string   `json:"name"`
+	UserName      string   `json:"userName"`
+	Email         string   `json:"email"`
+	SigningKey    string   `json:"signingKey,omitempty"`
+	SigningFormat string   `json:"signingFormat,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+	Source        string   `json:"source,omitempty"`
+}
+
+// ListResult answers MethodList with the profiles that aren't archived
+type ListResult struct {
+	Profiles []Profile `json:"profiles"`
+}
+
+// DirParams selects the repository MethodCurrent and MethodRulesMatch look at
+type DirParams struct {
+	Dir string `json:"dir"`
+}
+
+// ApplyParams asks MethodApply to apply Profile to the repository at Dir
+type ApplyParams struct {
+	Dir     string `json:"dir"`
+	Profile string `json:"profile"`
+}
+
+// CurrentResult answers MethodCurrent and MethodApply with the identity of a repository
+type CurrentResult struct {
+	Dir      string `json:"dir"`
+	Repo     string `json:"repo,omitempty"`
+	UserName string `json:"userName,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Profile  string `json:"profile,omitempty"`
+	Expected string `json:"expected,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// RuleMatch is how one rule fared against a repository
+type RuleMatch struct {
+	Target   string `json:"target"`
+	Profile  string `json:"profile"`
+	Matches  bool   `json:"matches"`
+	Selected bool   `json:"selected"`
+	Missing  bool   `json:"missing"`
+}
+
+// RulesMatchResult answers MethodRulesMatch: every rule in order, and the profile selected by them or the remote patterns
+type RulesMatchResult struct {
+	Repo    string      `json:"repo,omitempty"`
+	Rules   []RuleMatch `json:"rules"`
+	Profile string      `json:"profile,omitempty"`
+	Reason  string      `json:"reason,omitempty"`
+}
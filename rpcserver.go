@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/lvluu/git-profile/rpc"
+)
+
+// rpcError is a failed call, answered with its JSON-RPC code
+type rpcError struct {
+	code    int
+	message string
+}
+
+// Error returns the message of the failed call
+func (e *rpcError) Error() string {
+	return e.message
+}
+
+// newRPCError builds a failed call
+func newRPCError(code int, format string, args ...any) *rpcError {
+	return &rpcError{code: code, message: fmt.Sprintf(format, args...)}
+}
+
+// decodeParams reads the params of a call into their wire type
+func decodeParams[T any](raw json.RawMessage) (T, *rpcError) {
+	var params T
+	if len(raw) == 0 {
+		return params, newRPCError(rpc.CodeInvalidParams, "params are required")
+	}
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return params, newRPCError(rpc.CodeInvalidParams, "invalid params: %v", err)
+	}
+	return params, nil
+}
+
+// currentResult converts a repository identity to its wire format
+func currentResult(identity repoIdentity) rpc.CurrentResult {
+	return rpc.CurrentResult{
+		Dir:      identity.Dir,
+		Repo:     identity.Repo,
+		UserName: identity.Name,
+		Email:    identity.Email,
+		Profile:  identity.Profile,
+		Expected: identity.Expected,
+		Reason:   identity.Reason,
+	}
+}
+
+// call runs one method against the loaded profiles
+func (s *profileServer) call(method string, raw json.RawMessage) (any, *rpcError) {
+	switch method {
+	case rpc.MethodVersion:
+		return rpc.VersionResult{Protocol: rpc.ProtocolVersion, Version: version}, nil
+
+	case rpc.MethodList:
+		result := rpc.ListResult{Profiles: []rpc.Profile{}}
+		for _, name := range s.cm.visibleProfiles() {
+			profile := s.cm.Profiles[name]
+			info := rpc.Profile{Name: name, UserName: profile.Name, Email: profile.Email, SigningKey: profile.Signing.Key, Tags: profile.Tags}
+			if profile.Signing.Key != "" {
+				info.SigningFormat = profile.SigningFormat()
+			}
+			info.Source, _ = s.cm.sourceOf(name)
+			result.Profiles = append(result.Profiles, info)
+		}
+		return result, nil
+
+	case rpc.MethodCurrent:
+		params, err := decodeParams[rpc.DirParams](raw)
+		if err != nil {
+			return nil, err
+		}
+		if params.Dir == "" {
+			return nil, newRPCError(rpc.CodeInvalidParams, "dir is required")
+		}
+		return currentResult(s.identity(expandHome(params.Dir))), nil
+
+	case rpc.MethodApply:
+		params, err := decodeParams[rpc.ApplyParams](raw)
+		if err != nil {
+			return nil, err
+		}
+		if params.Dir == "" || params.Profile == "" {
+			return nil, newRPCError(rpc.CodeInvalidParams, "dir and profile are required")
+		}
+		if _, exists := s.cm.Profiles[params.Profile]; !exists {
+			return nil, newRPCError(rpc.CodeProfileNotFound, "profile '%s' not found", params.Profile)
+		}
+		dir := expandHome(params.Dir)
+		if err := s.apply(dir, params.Profile); err != nil {
+			return nil, newRPCError(rpc.CodeApplyFailed, "%v", err)
+		}
+		return currentResult(s.identity(dir)), nil
+
+	case rpc.MethodRulesMatch:
+		params, err := decodeParams[rpc.DirParams](raw)
+		if err != nil {
+			return nil, err
+		}
+		if params.Dir == "" {
+			return nil, newRPCError(rpc.CodeInvalidParams, "dir is required")
+		}
+		selection := s.cm.explainProfile(expandHome(params.Dir))
+		result := rpc.RulesMatchResult{Repo: selection.Repo, Rules: []rpc.RuleMatch{}, Profile: selection.Profile, Reason: selection.Reason}
+		for _, verdict := range selection.Rules {
+			result.Rules = append(result.Rules, rpc.RuleMatch{
+				Target:   verdict.Rule.Target(),
+				Profile:  verdict.Rule.Profile,
+				Matches:  verdict.Matches,
+				Selected: verdict.Selected,
+				Missing:  verdict.Missing,
+			})
+		}
+		return result, nil
+	}
+	return nil, newRPCError(rpc.CodeMethodNotFound, "unknown method '%s'", method)
+}
+
+// handleRPC answers one request line, returning nil for notifications
+func (s *profileServer) handleRPC(line []byte) *rpc.Response {
+	var request rpc.Request
+	if err := json.Unmarshal(line, &request); err != nil {
+		return &rpc.Response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpc.Error{Code: rpc.CodeParseError, Message: err.Error()}}
+	}
+	id := request.ID
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	if request.JSONRPC != "2.0" || request.Method == "" {
+		return &rpc.Response{JSONRPC: "2.0", ID: id, Error: &rpc.Error{Code: rpc.CodeInvalidRequest, Message: "expected a JSON-RPC 2.0 request with a method"}}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	debugf("rpc: %s %s", request.Method, request.Params)
+
+	var result any
+	var callErr *rpcError
+	if err := s.reload(); err != nil {
+		callErr = newRPCError(rpc.CodeConfigInvalid, "%v", err)
+	} else {
+		result, callErr = s.call(request.Method, request.Params)
+	}
+
+	if len(request.ID) == 0 {
+		return nil
+	}
+	if callErr != nil {
+		return &rpc.Response{JSONRPC: "2.0", ID: id, Error: &rpc.Error{Code: callErr.code, Message: callErr.message}}
+	}
+	return &rpc.Response{JSONRPC: "2.0", ID: id, Result: result}
+}
+
+// serveRPC answers newline-delimited JSON-RPC requests from r on w, one at a time, until r is closed
+func (s *profileServer) serveRPC(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	encoder := json.NewEncoder(w)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		if response := s.handleRPC(scanner.Bytes()); response != nil {
+			if err := encoder.Encode(response); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
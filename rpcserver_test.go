@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lvluu/git-profile/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestServeRPC tests answering newline-delimited JSON-RPC requests
+func TestServeRPC(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "git-profile-home")
+	assert.NoError(t, err)
+	defer os.RemoveAll(homeDir)
+	t.Setenv("HOME", homeDir)
+	repoDir := initTestRepo(t)
+
+	cm := &ConfigManager{
+		ConfigPath: filepath.Join(homeDir, ".git-profiles.json"),
+		Profiles: map[string]Profile{
+			"work":     {Name: "John Doe", Email: "john.doe@company.com", Tags: []string{"client"}},
+			"personal": {Name: "John Personal", Email: "john.personal@gmail.com"},
+		},
+		Rules: []Rule{{Remote: "github.com/acme-*", Profile: "work"}, {Dir: repoDir, Profile: "personal"}},
+	}
+	cm.save()
+
+	dir, _ := json.Marshal(repoDir)
+	requests := strings.Join([]string{
+		`{"jsonrpc": "2.0", "id": 1, "method": "version"}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "list"}`,
+		`{"jsonrpc": "2.0", "id": "rules", "method": "rules.match", "params": {"dir": ` + string(dir) + `}}`,
+		`{"jsonrpc": "2.0", "method": "apply", "params": {"dir": ` + string(dir) + `, "profile": "work"}}`,
+		`{"jsonrpc": "2.0", "id": 3, "method": "current", "params": {"dir": ` + string(dir) + `}}`,
+		`{"jsonrpc": "2.0", "id": 4, "method": "apply", "params": {"dir": ` + string(dir) + `, "profile": "missing"}}`,
+		`{"jsonrpc": "2.0", "id": 5, "method": "current"}`,
+		`{"jsonrpc": "2.0", "id": 6, "method": "delete"}`,
+		``,
+		`not json`,
+	}, "\n") + "\n"
+
+	var out bytes.Buffer
+	assert.NoError(t, newProfileServer(cm).serveRPC(strings.NewReader(requests), &out))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	// The apply notification gets no response
+	if !assert.Len(t, lines, 8) {
+		return
+	}
+	type response struct {
+		ID     json.RawMessage `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  *rpc.Error      `json:"error"`
+	}
+	responses := make([]response, len(lines))
+	for i, line := range lines {
+		assert.NoError(t, json.Unmarshal([]byte(line), &responses[i]))
+	}
+
+	var versionResult rpc.VersionResult
+	assert.NoError(t, json.Unmarshal(responses[0].Result, &versionResult))
+	assert.Equal(t, rpc.ProtocolVersion, versionResult.Protocol)
+
+	var list rpc.ListResult
+	assert.NoError(t, json.Unmarshal(responses[1].Result, &list))
+	assert.Equal(t, []rpc.Profile{
+		{Name: "personal", UserName: "John Personal", Email: "john.personal@gmail.com"},
+		{Name: "work", UserName: "John Doe", Email: "john.doe@company.com", Tags: []string{"client"}},
+	}, list.Profiles)
+
+	var match rpc.RulesMatchResult
+	assert.Equal(t, `"rules"`, string(responses[2].ID))
+	assert.NoError(t, json.Unmarshal(responses[2].Result, &match))
+	assert.Equal(t, "personal", match.Profile)
+	if assert.Len(t, match.Rules, 2) {
+		assert.False(t, match.Rules[0].Matches)
+		assert.True(t, match.Rules[1].Selected)
+	}
+
+	var current rpc.CurrentResult
+	assert.NoError(t, json.Unmarshal(responses[3].Result, &current))
+	assert.Equal(t, "work", current.Profile)
+	assert.Equal(t, "john.doe@company.com", current.Email)
+	assert.Equal(t, "personal", current.Expected)
+
+	assert.Equal(t, rpc.CodeProfileNotFound, responses[4].Error.Code)
+	assert.Equal(t, rpc.CodeInvalidParams, responses[5].Error.Code)
+	assert.Equal(t, rpc.CodeMethodNotFound, responses[6].Error.Code)
+	assert.Equal(t, rpc.CodeParseError, responses[7].Error.Code)
+	assert.Equal(t, "null", string(responses[7].ID))
+}
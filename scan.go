@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+)
+
+// recommendedProfile returns the profile a repository should use according to policy rules, then remote patterns
+func (cm *ConfigManager) recommendedProfile(dir string) (string, bool) {
+	if rule, found := cm.matchRule(dir); found {
+		if _, exists := cm.Profiles[rule.Profile]; exists {
+			return rule.Profile, true
+		}
+	}
+	return cm.suggestProfileForRepo(dir)
+}
+
+// isMismatched reports whether a repository's identity is unknown or differs from its recommended profile
+func (cm *ConfigManager) isMismatched(status repoStatus) bool {
+	if status.Profile == "" {
+		return true
+	}
+	recommended, found := cm.recommendedProfile(status.Path)
+	return found && recommended != status.Profile
+}
+
+// fixRepo prompts for the profile to apply to a mismatched repository
+func (cm *ConfigManager) fixRepo(status repoStatus) error {
+	profileNames := cm.visibleProfiles()
+
+	const skip = "(skip)"
+	prompt := promptui.Select{
+		Label: fmt.Sprintf(tr("Profile for %s"), displayPath(status.Path)),
+		Items: append(profileNames, skip),
+	}
+	if recommended, found := cm.recommendedProfile(status.Path); found {
+		for i, name := range profileNames {
+			if name == recommended {
+				prompt.CursorPos = i
+			}
+		}
+	}
+
+	_, selected, err := prompt.Run()
+	if err != nil || selected == skip {
+		return nil
+	}
+
+	if err := applyProfile(status.Path, selected, cm.Profiles[selected]); err != nil {
+		return err
+	}
+	cm.registerRepo(status.Path, selected)
+	cm.recordHistory("apply", selected, status.Path, scopeLocal)
+	fmt.Fprintf(notices, tr("Profile '%s' applied to %s\n"), selected, displayPath(status.Path))
+	return nil
+}
+
+// newScanCmd builds the scan command
+func newScanCmd(configManager *ConfigManager) *cobra.Command {
+	var fix bool
+	var scanCmd = &cobra.Command{
+		Use:   "scan <dir>",
+		Short: "Find repositories under a directory and flag identity mismatches",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if fix {
+				if err := requireTerminal(tr("run without --fix to only report mismatches")); err != nil {
+					fmt.Fprintln(stdout, tr("Scan failed:"), err)
+					os.Exit(1)
+				}
+			}
+
+			repos, err := findRepos(args[0])
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Scan failed:"), err)
+				os.Exit(1)
+			}
+
+			if len(repos) == 0 {
+				fmt.Fprintln(stdout, tr("No repositories found."))
+				return
+			}
+
+			var mismatched []repoStatus
+			writer := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(writer, tr("REPOSITORY\tIDENTITY\tPROFILE\t"))
+			for _, repo := range repos {
+				status := configManager.inspectRepo(repo)
+
+				identity := "-"
+				if status.Email != "" {
+					identity = fmt.Sprintf("%s <%s>", status.Name, status.Email)
+				}
+				profile := status.Profile
+				if profile == "" {
+					profile = tr("UNKNOWN")
+				}
+
+				flag := ""
+				if configManager.isMismatched(status) {
+					mismatched = append(mismatched, status)
+					flag = "⚠️"
+					if recommended, found := configManager.recommendedProfile(repo); found {
+						flag += fmt.Sprintf(tr(" expected %s"), recommended)
+					}
+				}
+				fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", displayPath(repo), identity, profile, flag)
+			}
+			writer.Flush()
+
+			fmt.Fprintf(stdout, tr("\n%d repositories scanned, %d mismatched.\n"), len(repos), len(mismatched))
+
+			if fix {
+				for _, status := range mismatched {
+					if err := configManager.fixRepo(status); err != nil {
+						fmt.Fprintf(stdout, tr("Error applying profile to %s: %v\n"), displayPath(status.Path), err)
+					}
+				}
+			}
+		},
+	}
+	scanCmd.Flags().BoolVar(&fix, "fix", false, "Interactively apply the right profile to mismatched repositories")
+
+	return scanCmd
+}
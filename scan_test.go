@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIsMismatched tests flagging repositories with unknown or unexpected identities
+func TestIsMismatched(t *testing.T) {
+	repoDir := initTestRepo(t)
+	cm := &ConfigManager{
+		Profiles: map[string]Profile{
+			"work":     {Name: "John Doe", Email: "john.doe@company.com", Remotes: []string{"github.com/mycorp/*"}},
+			"personal": {Name: "John Personal", Email: "john.personal@gmail.com"},
+		},
+	}
+
+	_, err := runGit(repoDir, "config", "user.email", "stranger@example.com")
+	assert.NoError(t, err)
+	assert.True(t, cm.isMismatched(cm.inspectRepo(repoDir)))
+
+	assert.NoError(t, applyProfile(repoDir, "personal", cm.Profiles["personal"]))
+	assert.False(t, cm.isMismatched(cm.inspectRepo(repoDir)))
+
+	_, err = runGit(repoDir, "remote", "add", "origin", "git@github.com:mycorp/api.git")
+	assert.NoError(t, err)
+	assert.True(t, cm.isMismatched(cm.inspectRepo(repoDir)))
+
+	recommended, found := cm.recommendedProfile(repoDir)
+	assert.True(t, found)
+	assert.Equal(t, "work", recommended)
+}
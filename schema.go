@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"github.com/spf13/cobra"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// configSchema is the JSON Schema of the config file, published as schema.json
+//
+//go:embed schema.json
+var configSchema []byte
+
+// schemaURL identifies the embedded schema
+const schemaURL = "https://github.com/lvluu/git-profile/schema.json"
+
+// schemaError is one schema violation, located in the validated file
+type schemaError struct {
+	Pointer string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e schemaError) Error() string {
+	location := e.Pointer
+	if location == "" {
+		location = "/"
+	}
+	return fmt.Sprintf(tr("line %d, column %d (%s): %s"), e.Line, e.Column, location, e.Message)
+}
+
+// compileSchema compiles the embedded schema, or one of its definitions when ref is a fragment like "#/$defs/profiles"
+func compileSchema(ref string) (*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(configSchema))
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat()
+	if err := compiler.AddResource(schemaURL, doc); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(schemaURL + ref)
+}
+
+// validateConfigData checks a config file, or an export file of bare profiles, against the schema
+func validateConfigData(data []byte) ([]schemaError, error) {
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return []schemaError{syntaxError(data, err)}, nil
+	}
+
+	// Export files and legacy configs are a bare map of profiles
+	ref := ""
+	var raw map[string]json.RawMessage
+	if json.Unmarshal(data, &raw) == nil {
+		if version, err := detectConfigVersion(data, raw); err == nil && version == 0 {
+			ref = "#/$defs/profiles"
+		}
+	}
+
+	schema, err := compileSchema(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	validationErr, ok := schema.Validate(instance).(*jsonschema.ValidationError)
+	if !ok {
+		return nil, nil
+	}
+
+	offsets := jsonValueOffsets(data)
+	printer := message.NewPrinter(language.English)
+	var problems []schemaError
+	for _, leaf := range schemaLeaves(validationErr) {
+		pointer := jsonPointer(leaf.InstanceLocation)
+		// Point unknown fields at the field itself rather than the object holding it
+		if additional, ok := leaf.ErrorKind.(*kind.AdditionalProperties); ok && len(additional.Properties) == 1 {
+			if _, found := offsets[pointer+"/"+escapePointer(additional.Properties[0])]; found {
+				pointer += "/" + escapePointer(additional.Properties[0])
+			}
+		}
+
+		line, column := lineColumn(data, offsets[pointer])
+		problems = append(problems, schemaError{
+			Pointer: pointer,
+			Line:    line,
+			Column:  column,
+			Message: leaf.ErrorKind.LocalizedString(printer),
+		})
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Column < problems[j].Column
+	})
+	return problems, nil
+}
+
+// syntaxError locates a JSON syntax error
+func syntaxError(data []byte, err error) schemaError {
+	var offset int64
+	if syntax, ok := err.(*json.SyntaxError); ok {
+		offset = syntax.Offset
+	}
+	line, column := lineColumn(data, int(offset))
+	return schemaError{Line: line, Column: column, Message: err.Error()}
+}
+
+// schemaLeaves flattens a validation error into the violations that caused it
+func schemaLeaves(err *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(err.Causes) == 0 {
+		return []*jsonschema.ValidationError{err}
+	}
+
+	var leaves []*jsonschema.ValidationError
+	for _, cause := range err.Causes {
+		leaves = append(leaves, schemaLeaves(cause)...)
+	}
+	return leaves
+}
+
+// escapePointer escapes a JSON Pointer reference token
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// jsonPointer joins reference tokens into a JSON Pointer
+func jsonPointer(tokens []string) string {
+	var sb strings.Builder
+	for _, token := range tokens {
+		sb.WriteString("/" + escapePointer(token))
+	}
+	return sb.String()
+}
+
+// jsonValueOffsets maps the JSON Pointer of every value in data to the byte offset where it starts
+func jsonValueOffsets(data []byte) map[string]int {
+	offsets := make(map[string]int)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	walkJSON(decoder, data, "", offsets)
+	return offsets
+}
+
+// walkJSON records the offset of the next value and of everything nested in it
+func walkJSON(decoder *json.Decoder, data []byte, pointer string, offsets map[string]int) error {
+	start := int(decoder.InputOffset())
+	for start < len(data) && strings.ContainsRune(" \t\r\n,:", rune(data[start])) {
+		start++
+	}
+	offsets[pointer] = start
+
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			if err := walkJSON(decoder, data, pointer+"/"+escapePointer(key.(string)), offsets); err != nil {
+				return err
+			}
+		}
+		_, err = decoder.Token()
+	case json.Delim('['):
+		for i := 0; decoder.More(); i++ {
+			if err := walkJSON(decoder, data, pointer+"/"+strconv.Itoa(i), offsets); err != nil {
+				return err
+			}
+		}
+		_, err = decoder.Token()
+	}
+	return err
+}
+
+// lineColumn converts a byte offset into a 1-based line and column
+func lineColumn(data []byte, offset int) (int, int) {
+	if offset > len(data) {
+		offset = len(data)
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := offset - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
+
+// newValidateCmd builds the validate command
+func newValidateCmd(configManager *ConfigManager) *cobra.Command {
+	var printSchema bool
+
+	var validateCmd = &cobra.Command{
+		Use:   "validate [file]",
+		Short: "Check a config or import file against the profiles schema",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if printSchema {
+				os.Stdout.Write(configSchema)
+				return
+			}
+
+			path := configManager.ConfigPath
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Validate failed:"), err)
+				os.Exit(1)
+			}
+
+			problems, err := validateConfigData(data)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Validate failed:"), err)
+				os.Exit(1)
+			}
+			if len(problems) > 0 {
+				for _, problem := range problems {
+					fmt.Fprintf(stdout, "❌ %s: %v\n", path, problem)
+				}
+				os.Exit(1)
+			}
+			fmt.Fprintf(notices, tr("✅ %s is valid.\n"), path)
+		},
+	}
+	validateCmd.Flags().BoolVar(&printSchema, "schema", false, "Print the JSON Schema instead of validating")
+
+	return validateCmd
+}
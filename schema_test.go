@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidateConfig tests checking config and export files against the schema
+func TestValidateConfig(t *testing.T) {
+	cm := &ConfigManager{
+		Profiles: map[string]Profile{
+			"work": {Name: "John Doe", Email: "john.doe@company.com", Tags: []string{"work"}},
+		},
+		Rules: []Rule{{Remote: "github.com/acme-*", Profile: "work"}},
+	}
+	config, err := json.MarshalIndent(configFile{Version: currentConfigVersion, Profiles: cm.Profiles, Rules: cm.Rules}, "", "  ")
+	assert.NoError(t, err)
+	problems, err := validateConfigData(config)
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+
+	// Export files are a bare map of profiles
+	export, err := json.MarshalIndent(cm.Profiles, "", "  ")
+	assert.NoError(t, err)
+	problems, err = validateConfigData(export)
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+
+	edited := []byte(`{
+  "version": 2,
+  "profiles": {
+    "work": {
+      "name": "John Doe",
+      "email": 42,
+      "signing": {"format": "pgp"},
+      "colour": "blue"
+    }
+  }
+}`)
+	problems, err = validateConfigData(edited)
+	assert.NoError(t, err)
+	if assert.Len(t, problems, 3) {
+		assert.Equal(t, schemaError{Pointer: "/profiles/work/email", Line: 6, Column: 16, Message: problems[0].Message}, problems[0])
+		assert.Contains(t, problems[0].Message, "want string")
+		assert.Equal(t, "/profiles/work/signing/format", problems[1].Pointer)
+		assert.Equal(t, 7, problems[1].Line)
+		assert.Equal(t, "/profiles/work/colour", problems[2].Pointer)
+		assert.Equal(t, 8, problems[2].Line)
+		assert.Equal(t, 17, problems[2].Column)
+	}
+
+	problems, err = validateConfigData([]byte("{\n  \"profiles\": {,}\n}"))
+	assert.NoError(t, err)
+	if assert.Len(t, problems, 1) {
+		assert.Equal(t, 2, problems[0].Line)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// applyTarget is a config file apply writes a profile to
+type applyTarget struct {
+	Scope      string
+	ConfigArgs []string
+}
+
+// applyTargets returns the config files the scope flags of apply select, global first; the local config when none is set
+func applyTargets(options applyOptions) []applyTarget {
+	var targets []applyTarget
+	if options.Global || options.AllScopes {
+		targets = append(targets, applyTarget{Scope: scopeGlobal, ConfigArgs: []string{"--global"}})
+	}
+	if options.Local || options.AllScopes || (!options.Global && !options.Worktree) {
+		targets = append(targets, applyTarget{Scope: scopeLocal})
+	}
+	if options.Worktree {
+		targets = append(targets, applyTarget{Scope: scopeWorktree, ConfigArgs: worktreeConfigArgs})
+	}
+	return targets
+}
+
+// applyToTargets applies a profile to each target in turn, registering the repository for the repository-level ones
+// when the profile is saved; with several targets every outcome is reported, and it returns those that failed
+func (cm *ConfigManager) applyToTargets(dir string, name string, profile Profile, targets []applyTarget) []error {
+	var failures []error
+	for _, target := range targets {
+		if err := applyProfile(dir, name, profile, target.ConfigArgs...); err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", target.Scope, err))
+			if len(targets) > 1 {
+				fmt.Fprintf(stdout, "❌ %s: %v\n", target.Scope, err)
+			}
+			continue
+		}
+
+		historyDir := dir
+		if target.Scope == scopeGlobal {
+			historyDir = ""
+		} else if _, saved := cm.Profiles[name]; saved {
+			cm.registerRepo(dir, name)
+		}
+		cm.recordHistory("apply", name, historyDir, target.Scope)
+		if len(targets) > 1 {
+			fmt.Fprintf(notices, tr("✅ %s: applied\n"), target.Scope)
+		}
+	}
+	return failures
+}
+
+// dryRunTargets prints the commands applying a profile to each target would run, headed by the scope when there are several
+func dryRunTargets(w io.Writer, dir string, name string, profile Profile, targets []applyTarget) error {
+	for _, target := range targets {
+		if len(targets) > 1 {
+			fmt.Fprintf(w, "# %s\n", target.Scope)
+		}
+		if err := dryRunApply(w, dir, name, profile, target.ConfigArgs...); err != nil {
+			return fmt.Errorf("%s: %w", target.Scope, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestApplyToScopes tests writing a profile to the global and local config in one go
+func TestApplyToScopes(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(tmpDir, ".gitconfig"))
+
+	assert.Equal(t, []applyTarget{{Scope: scopeLocal}}, applyTargets(applyOptions{}))
+	assert.Equal(t, []applyTarget{{Scope: scopeGlobal, ConfigArgs: []string{"--global"}}}, applyTargets(applyOptions{Global: true}))
+	assert.Equal(t, applyTargets(applyOptions{Global: true, Local: true}), applyTargets(applyOptions{AllScopes: true}))
+
+	cm := &ConfigManager{ConfigPath: filepath.Join(tmpDir, ".git-profiles-test.json"), Profiles: map[string]Profile{
+		"corp": {Name: "John Doe", Email: "john.doe@company.com"},
+	}}
+	targets := applyTargets(applyOptions{AllScopes: true})
+	repoDir := initTestRepo(t)
+	assert.Empty(t, cm.applyToTargets(repoDir, "corp", cm.Profiles["corp"], targets))
+	for _, scope := range []string{"--global", "--local"} {
+		email, err := runGit(repoDir, "config", scope, "--get", "user.email")
+		assert.NoError(t, err)
+		assert.Equal(t, "john.doe@company.com", email)
+	}
+	assert.Equal(t, "corp", cm.Repos[repoTopLevel(repoDir)].Profile)
+
+	// Outside a repository only the global scope succeeds
+	failures := cm.applyToTargets(tmpDir, "corp", cm.Profiles["corp"], targets)
+	if assert.Len(t, failures, 1) {
+		assert.ErrorContains(t, failures[0], "local:")
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"github.com/zalando/go-keyring"
+)
+
+// keyringService is the service name profile secrets are stored under in the OS keystore
+const keyringService = "git-profile"
+
+// keyringHandlePrefix marks config values that reference a secret in the OS keystore
+const keyringHandlePrefix = "keyring:"
+
+// secretAccount returns the keystore account holding a profile secret
+func secretAccount(profile string, name string) string {
+	return profile + "/" + name
+}
+
+// setSecret stores value in the OS keystore and records its handle on the profile
+func (cm *ConfigManager) setSecret(profileName string, name string, value string) error {
+	profile, exists := cm.Profiles[profileName]
+	if !exists {
+		return errorf("profile '%s' not found", profileName)
+	}
+
+	account := secretAccount(profileName, name)
+	if err := keyring.Set(keyringService, account, value); err != nil {
+		return errorf("storing secret in the OS keystore: %w", err)
+	}
+
+	if profile.Secrets == nil {
+		profile.Secrets = make(map[string]string)
+	}
+	profile.Secrets[name] = keyringHandlePrefix + account
+	cm.Profiles[profileName] = profile
+	cm.save()
+	return nil
+}
+
+// profileSecret reads a profile secret back from the OS keystore
+func (cm *ConfigManager) profileSecret(profileName string, name string) (string, error) {
+	profile, exists := cm.Profiles[profileName]
+	if !exists {
+		return "", errorf("profile '%s' not found", profileName)
+	}
+
+	handle, exists := profile.Secrets[name]
+	if !exists {
+		return "", errorf("profile '%s' has no secret '%s'", profileName, name)
+	}
+
+	account, ok := strings.CutPrefix(handle, keyringHandlePrefix)
+	if !ok {
+		return "", errorf("secret '%s' has an unsupported handle '%s'", name, handle)
+	}
+
+	value, err := keyring.Get(keyringService, account)
+	if err != nil {
+		return "", errorf("reading secret '%s' from the OS keystore: %w", name, err)
+	}
+	return value, nil
+}
+
+// removeSecret deletes a profile secret from the OS keystore and drops its handle
+func (cm *ConfigManager) removeSecret(profileName string, name string) error {
+	profile, exists := cm.Profiles[profileName]
+	if !exists {
+		return errorf("profile '%s' not found", profileName)
+	}
+
+	handle, exists := profile.Secrets[name]
+	if !exists {
+		return errorf("profile '%s' has no secret '%s'", profileName, name)
+	}
+
+	if account, ok := strings.CutPrefix(handle, keyringHandlePrefix); ok {
+		if err := keyring.Delete(keyringService, account); err != nil && err != keyring.ErrNotFound {
+			return errorf("removing secret from the OS keystore: %w", err)
+		}
+	}
+
+	delete(profile.Secrets, name)
+	cm.Profiles[profileName] = profile
+	cm.save()
+	return nil
+}
+
+// removeProfileSecrets deletes every keystore entry of a profile that is about to be removed
+func (cm *ConfigManager) removeProfileSecrets(profileName string) {
+	for name := range cm.Profiles[profileName].Secrets {
+		if err := cm.removeSecret(profileName, name); err != nil {
+			warnf("Secret '%s' could not be removed: %v", name, err)
+		}
+	}
+}
+
+// readSecretValue reads a secret from stdin or, on a terminal, from a masked prompt
+func readSecretValue(fromStdin bool, name string) (string, error) {
+	if fromStdin {
+		value, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if value = strings.TrimRight(value, "\r\n"); value != "" {
+			return value, nil
+		}
+		return "", errorf("no secret on stdin: %v", err)
+	}
+
+	if err := requireTerminal(tr("pass the value on stdin with --stdin")); err != nil {
+		return "", err
+	}
+	prompt := promptui.Prompt{
+		Label: fmt.Sprintf(tr("Enter value for '%s'"), name),
+		Mask:  '*',
+	}
+	return prompt.Run()
+}
+
+// newSecretCmd builds the secret command group
+func newSecretCmd(configManager *ConfigManager) *cobra.Command {
+	var secretCmd = &cobra.Command{
+		Use:   "secret",
+		Short: "Store tokens and passphrases for a profile in the OS keystore",
+	}
+
+	var fromStdin bool
+	var setCmd = &cobra.Command{
+		Use:   "set <profile> <name>",
+		Short: "Store a secret for a profile",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			value, err := readSecretValue(fromStdin, args[1])
+			if err != nil {
+				fmt.Fprintln(notices, tr("Cancelled."))
+				return
+			}
+
+			if err := configManager.setSecret(args[0], args[1], value); err != nil {
+				fmt.Fprintln(stdout, tr("Set failed:"), err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(notices, tr("Secret '%s' stored for profile '%s'.\n"), args[1], args[0])
+		},
+	}
+	setCmd.Flags().BoolVar(&fromStdin, "stdin", false, "Read the secret from stdin instead of prompting")
+
+	var getCmd = &cobra.Command{
+		Use:   "get <profile> <name>",
+		Short: "Print a secret of a profile",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			value, err := configManager.profileSecret(args[0], args[1])
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Get failed:"), err)
+				os.Exit(1)
+			}
+			fmt.Fprintln(stdout, value)
+		},
+	}
+
+	var listCmd = &cobra.Command{
+		Use:   "ls <profile>",
+		Short: "List the secrets stored for a profile",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			profile, exists := configManager.Profiles[args[0]]
+			if !exists {
+				fmt.Fprintf(stdout, tr("Profile '%s' not found.\n"), args[0])
+				os.Exit(1)
+			}
+			if len(profile.Secrets) == 0 {
+				fmt.Fprintln(stdout, tr("No secrets stored."))
+				return
+			}
+
+			var names []string
+			for name := range profile.Secrets {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Fprintf(stdout, "🔐 %s (%s)\n", name, profile.Secrets[name])
+			}
+		},
+	}
+
+	var removeCmd = &cobra.Command{
+		Use:   "rm <profile> <name>",
+		Short: "Remove a secret from a profile and the OS keystore",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := configManager.removeSecret(args[0], args[1]); err != nil {
+				fmt.Fprintln(stdout, tr("Remove failed:"), err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(notices, tr("Secret '%s' removed from profile '%s'.\n"), args[1], args[0])
+		},
+	}
+
+	secretCmd.AddCommand(setCmd, getCmd, listCmd, removeCmd)
+	return secretCmd
+}
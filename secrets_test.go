@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando/go-keyring"
+)
+
+// TestProfileSecrets tests storing secrets in the keystore and referencing them by handle
+func TestProfileSecrets(t *testing.T) {
+	keyring.MockInit()
+
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	cm := &ConfigManager{
+		ConfigPath: filepath.Join(tmpDir, ".git-profiles-test.json"),
+		Profiles: map[string]Profile{
+			"work": {Name: "John Doe", Email: "john.doe@company.com"},
+		},
+	}
+
+	assert.NoError(t, cm.setSecret("work", "github-token", "ghp_secret"))
+	assert.Error(t, cm.setSecret("missing", "github-token", "ghp_secret"))
+
+	// Only the handle ends up in the config file
+	data, err := os.ReadFile(cm.ConfigPath)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "ghp_secret")
+	assert.Contains(t, string(data), "keyring:work/github-token")
+
+	loaded := &ConfigManager{ConfigPath: cm.ConfigPath}
+	loaded.load()
+	value, err := loaded.profileSecret("work", "github-token")
+	assert.NoError(t, err)
+	assert.Equal(t, "ghp_secret", value)
+
+	assert.NoError(t, loaded.removeSecret("work", "github-token"))
+	_, err = loaded.profileSecret("work", "github-token")
+	assert.Error(t, err)
+	_, err = keyring.Get(keyringService, secretAccount("work", "github-token"))
+	assert.ErrorIs(t, err, keyring.ErrNotFound)
+}
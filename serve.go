@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// repoIdentity is the identity of a repository as served by the API
+type repoIdentity struct {
+	Dir      string `json:"dir"`
+	Repo     string `json:"repo,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Profile  string `json:"profile,omitempty"`
+	Expected string `json:"expected,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// applyRequest is the body of POST /v1/apply
+type applyRequest struct {
+	Dir     string `json:"dir"`
+	Profile string `json:"profile"`
+}
+
+// profileServer answers API requests from the profiles on disk, reloading them when the config file changes
+type profileServer struct {
+	mu       sync.Mutex
+	cm       *ConfigManager
+	modified time.Time
+}
+
+// newProfileServer serves the profiles of cm
+func newProfileServer(cm *ConfigManager) *profileServer {
+	s := &profileServer{cm: cm}
+	if info, err := os.Stat(cm.ConfigPath); err == nil {
+		s.modified = info.ModTime()
+	}
+	return s
+}
+
+// reload picks up changes made to the config file since the last request, keeping the loaded profiles while the file is invalid
+func (s *profileServer) reload() error {
+	info, err := os.Stat(s.cm.ConfigPath)
+	if err != nil || info.ModTime().Equal(s.modified) {
+		return nil
+	}
+
+	// load exits on a broken file, which a long-running server must survive
+	data, err := os.ReadFile(s.cm.ConfigPath)
+	if err != nil {
+		return err
+	}
+	if len(data) > 0 {
+		if _, err := parseConfig(data); err != nil {
+			return fmt.Errorf("%s: %w", s.cm.ConfigPath, err)
+		}
+	}
+
+	reloaded := &ConfigManager{ConfigPath: s.cm.ConfigPath, FragmentsDir: s.cm.FragmentsDir, Picker: s.cm.Picker, Profiles: make(map[string]Profile)}
+	reloaded.load()
+	s.cm = reloaded
+	s.modified = info.ModTime()
+	debugf("serve: reloaded %d profiles from %s", len(reloaded.Profiles), reloaded.ConfigPath)
+	return nil
+}
+
+// writeJSON sends value as the JSON response
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// writeError sends an error as {"error": "..."}
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// identity describes the repository at dir
+func (s *profileServer) identity(dir string) repoIdentity {
+	identity := repoIdentity{Dir: dir, Repo: repoTopLevel(dir)}
+	if identity.Repo == "" {
+		return identity
+	}
+	identity.Name, _ = gitConfigGet(dir, "user.name")
+	identity.Email, _ = gitConfigGet(dir, "user.email")
+	identity.Profile, _ = s.cm.appliedProfile(dir)
+	selection := s.cm.explainProfile(dir)
+	identity.Expected, identity.Reason = selection.Profile, selection.Reason
+	return identity
+}
+
+// apply applies a profile to the repository at dir as the apply command does, hooks included
+func (s *profileServer) apply(dir string, name string) error {
+	if repoTopLevel(dir) == "" {
+		return errorf("%s is not inside a Git repository", dir)
+	}
+	if err := s.cm.checkNotArchived(name); err != nil {
+		return err
+	}
+	profile, err := resolveProfile(s.cm.Profiles[name])
+	if err != nil {
+		return err
+	}
+
+	if err := s.cm.runApplyHooks(hookPreApply, dir, name, profile); err != nil {
+		return err
+	}
+	if err := applyProfile(dir, name, profile); err != nil {
+		return err
+	}
+	s.cm.registerRepo(dir, name)
+	s.cm.recordHistory("apply", name, dir, scopeLocal)
+	s.cm.markUsed(name)
+	if err := s.cm.runApplyHooks(hookPostApply, dir, name, profile); err != nil {
+		warnf("%v", err)
+	}
+	return nil
+}
+
+// handler routes the API:
+// GET /v1/profiles, GET /v1/profiles/{name}, GET /v1/status?dir=PATH and POST /v1/apply {"dir", "profile"}
+func (s *profileServer) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /v1/profiles", func(w http.ResponseWriter, r *http.Request) {
+		var names []string
+		for name := range s.cm.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		profiles := make([]profileDetails, 0, len(names))
+		for _, name := range names {
+			profiles = append(profiles, s.cm.profileDetails(name))
+		}
+		writeJSON(w, http.StatusOK, profiles)
+	})
+
+	mux.HandleFunc("GET /v1/profiles/{name}", func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		if _, exists := s.cm.Profiles[name]; !exists {
+			writeError(w, http.StatusNotFound, errorf("profile '%s' not found", name))
+			return
+		}
+		writeJSON(w, http.StatusOK, s.cm.profileDetails(name))
+	})
+
+	mux.HandleFunc("GET /v1/status", func(w http.ResponseWriter, r *http.Request) {
+		dir := r.URL.Query().Get("dir")
+		if dir == "" {
+			writeError(w, http.StatusBadRequest, errors.New(tr("the dir query parameter is required")))
+			return
+		}
+		writeJSON(w, http.StatusOK, s.identity(expandHome(dir)))
+	})
+
+	mux.HandleFunc("POST /v1/apply", func(w http.ResponseWriter, r *http.Request) {
+		var request applyRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, errorf("invalid request body: %w", err))
+			return
+		}
+		if request.Dir == "" || request.Profile == "" {
+			writeError(w, http.StatusBadRequest, errors.New(tr("dir and profile are required")))
+			return
+		}
+		if _, exists := s.cm.Profiles[request.Profile]; !exists {
+			writeError(w, http.StatusNotFound, errorf("profile '%s' not found", request.Profile))
+			return
+		}
+
+		dir := expandHome(request.Dir)
+		if err := s.apply(dir, request.Profile); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, s.identity(dir))
+	})
+
+	// Requests are served one at a time, so an apply never races a reload or another apply
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		infof("serve: %s %s", r.Method, r.URL)
+		if err := s.reload(); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// listenSocket listens on the Unix socket at path, replacing a stale socket left behind by a server that died
+func listenSocket(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, errorf("another server is already listening on %s", path)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	// Anyone who can connect can switch identities, so only the owner may
+	listener, err := listenUnix(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// newServeCmd builds the serve command
+func newServeCmd(configManager *ConfigManager) *cobra.Command {
+	var socket string
+
+	var serveCmd = &cobra.Command{
+		Use:   "serve",
+		Short: "Serve profiles and apply over a local HTTP/JSON API on a Unix socket",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			path := expandHome(socket)
+			listener, err := listenSocket(path)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Serve failed:"), err)
+				os.Exit(1)
+			}
+
+			server := &http.Server{Handler: newProfileServer(configManager).handler()}
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			go func() {
+				<-ctx.Done()
+				shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				server.Shutdown(shutdown)
+			}()
+
+			fmt.Fprintf(notices, tr("🦑 Listening on %s (Ctrl+C to stop)\n"), displayPath(path))
+			if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				fmt.Fprintln(stdout, tr("Serve failed:"), err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	serveCmd.Flags().StringVar(&socket, "socket", "~/.cache/git-profile.sock", "Unix socket to listen on")
+	return serveCmd
+}
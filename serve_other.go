@@ -0,0 +1,10 @@
+//go:build !unix
+
+package main
+
+import "net"
+
+// listenUnix creates the socket at path; there's no umask to narrow its permissions here, so listenSocket's chmod does
+func listenUnix(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProfileServer tests reading profiles and repository identities and applying profiles over the API
+func TestProfileServer(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "git-profile-home")
+	assert.NoError(t, err)
+	defer os.RemoveAll(homeDir)
+	t.Setenv("HOME", homeDir)
+	repoDir := initTestRepo(t)
+
+	cm := &ConfigManager{
+		ConfigPath: filepath.Join(homeDir, ".git-profiles.json"),
+		Profiles: map[string]Profile{
+			"work":     {Name: "John Doe", Email: "john.doe@company.com"},
+			"personal": {Name: "John Personal", Email: "john.personal@gmail.com"},
+		},
+		Rules: []Rule{{Dir: repoDir, Profile: "work"}},
+	}
+	cm.save()
+	handler := newProfileServer(cm).handler()
+
+	request := func(method string, target string, body string) (int, map[string]any) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(method, target, strings.NewReader(body)))
+		var decoded map[string]any
+		json.Unmarshal(recorder.Body.Bytes(), &decoded)
+		return recorder.Code, decoded
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/profiles", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var profiles []profileDetails
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &profiles))
+	if assert.Len(t, profiles, 2) {
+		assert.Equal(t, "personal", profiles[0].Name)
+	}
+
+	code, body := request(http.MethodGet, "/v1/profiles/work", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "work", body["name"])
+	code, body = request(http.MethodGet, "/v1/profiles/missing", "")
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, "profile 'missing' not found", body["error"])
+
+	statusURL := "/v1/status?dir=" + url.QueryEscape(repoDir)
+	code, body = request(http.MethodGet, statusURL, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "work", body["expected"])
+	assert.Nil(t, body["profile"])
+	code, _ = request(http.MethodGet, "/v1/status", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	applyBody, _ := json.Marshal(applyRequest{Dir: repoDir, Profile: "personal"})
+	code, body = request(http.MethodPost, "/v1/apply", string(applyBody))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "personal", body["profile"])
+	assert.Equal(t, "john.personal@gmail.com", body["email"])
+	code, _ = request(http.MethodPost, "/v1/apply", `{"dir": "`+repoDir+`"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// Profiles added to the config file after the server started are picked up
+	cm.Profiles["oss"] = Profile{Name: "John OSS", Email: "john@oss.dev"}
+	cm.save()
+	future := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(cm.ConfigPath, future, future))
+	code, _ = request(http.MethodGet, "/v1/profiles/oss", "")
+	assert.Equal(t, http.StatusOK, code)
+
+	// A broken config file is reported instead of bringing the server down
+	assert.NoError(t, os.WriteFile(cm.ConfigPath, []byte("{"), 0644))
+	assert.NoError(t, os.Chtimes(cm.ConfigPath, future.Add(time.Minute), future.Add(time.Minute)))
+	code, _ = request(http.MethodGet, "/v1/profiles", "")
+	assert.Equal(t, http.StatusInternalServerError, code)
+}
+
+// TestListenSocket tests replacing stale sockets and refusing to take over a live one
+func TestListenSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "gp-sock")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cache", "git-profile.sock")
+
+	listener, err := listenSocket(path)
+	assert.NoError(t, err)
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	_, err = listenSocket(path)
+	assert.ErrorContains(t, err, "already listening")
+
+	// A socket file nobody listens on is stale
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	listener.Close()
+	listener, err = listenSocket(path)
+	assert.NoError(t, err)
+	listener.Close()
+}
@@ -0,0 +1,31 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestListenUnix tests that the socket is never created accessible to others and the umask is restored
+func TestListenUnix(t *testing.T) {
+	dir, err := os.MkdirTemp("", "gp-sock")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "git-profile.sock")
+
+	umask := syscall.Umask(0022)
+	defer syscall.Umask(umask)
+
+	listener, err := listenUnix(path)
+	assert.NoError(t, err)
+	defer listener.Close()
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	assert.Equal(t, 0022, syscall.Umask(0022))
+}
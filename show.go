@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// profileDetails is everything known about a profile, as printed by show
+type profileDetails struct {
+	Name    string   `json:"name"`
+	Profile Profile  `json:"profile"`
+	Source  string   `json:"source,omitempty"`
+	File    string   `json:"file,omitempty"`
+	Rules   []Rule   `json:"rules,omitempty"`
+	Repos   []string `json:"repos,omitempty"`
+}
+
+// profileDetails gathers a profile with where it comes from, the rules requiring it and the repositories it's applied to
+func (cm *ConfigManager) profileDetails(name string) profileDetails {
+	details := profileDetails{Name: name, Profile: cm.Profiles[name], Repos: cm.registeredPaths(name)}
+	if source, shared := cm.sourceOf(name); shared {
+		details.Source = source
+	}
+	if path, owned := cm.fragmentOf(name); owned {
+		details.File = path
+	}
+	for _, rule := range cm.Rules {
+		if rule.Profile == name {
+			details.Rules = append(details.Rules, rule)
+		}
+	}
+	return details
+}
+
+// newShowCmd builds the show command
+func newShowCmd(configManager *ConfigManager) *cobra.Command {
+	var asJSON bool
+	var color string
+
+	var showCmd = &cobra.Command{
+		Use:   "show <profile>",
+		Short: "Show everything about a profile",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := args[0]
+			if _, exists := configManager.Profiles[name]; !exists {
+				fmt.Fprintf(stdout, tr("Profile '%s' not found.\n"), name)
+				os.Exit(1)
+			}
+			details := configManager.profileDetails(name)
+
+			if asJSON {
+				data, err := json.MarshalIndent(details, "", "  ")
+				if err != nil {
+					fmt.Fprintln(stdout, tr("Show failed:"), err)
+					os.Exit(1)
+				}
+				fmt.Fprintln(stdout, string(data))
+				return
+			}
+
+			enabled, err := useColor(color)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Show failed:"), err)
+				os.Exit(1)
+			}
+
+			fmt.Fprintf(stdout, tr("💻 Profile: %s\n"), name)
+			configManager.writeProfileFields(stdout, name, enabled)
+			if details.Source != "" {
+				fmt.Fprintf(stdout, tr("  📡 Shared: %s\n"), details.Source)
+			}
+			if details.File != "" {
+				fmt.Fprintf(stdout, tr("  📄 File: %s\n"), filepath.Base(details.File))
+			}
+			if len(details.Rules) > 0 {
+				fmt.Fprintln(stdout, tr("  📏 Required by:"))
+				for _, rule := range details.Rules {
+					fmt.Fprintf(stdout, "    • %s\n", rule.Target())
+				}
+			}
+			if len(details.Repos) > 0 {
+				fmt.Fprintln(stdout, tr("  📂 Applied to:"))
+				for _, path := range details.Repos {
+					fmt.Fprintf(stdout, "    • %s\n", path)
+				}
+			}
+		},
+	}
+
+	showCmd.Flags().BoolVar(&asJSON, "json", false, "Print the profile as JSON")
+	showCmd.Flags().StringVar(&color, "color", colorAuto, "Highlight missing key files: auto (when writing to a terminal), always or never")
+
+	return showCmd
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProfileDetails tests gathering the rules and repositories of a profile
+func TestProfileDetails(t *testing.T) {
+	cm := &ConfigManager{
+		Profiles: map[string]Profile{
+			"work":     {Name: "John Doe", Email: "john.doe@company.com"},
+			"personal": {Name: "John Personal", Email: "john.personal@gmail.com"},
+		},
+		Rules: []Rule{
+			{Remote: "github.com/acme-*", Profile: "work"},
+			{Dir: "~/oss/*", Profile: "personal"},
+			{Dir: "~/work/*", Profile: "work"},
+		},
+		Repos: map[string]RegisteredRepo{
+			"/src/api":  {Profile: "work"},
+			"/src/blog": {Profile: "personal"},
+			"/src/app":  {Profile: "work"},
+		},
+	}
+
+	details := cm.profileDetails("work")
+	assert.Equal(t, []Rule{{Remote: "github.com/acme-*", Profile: "work"}, {Dir: "~/work/*", Profile: "work"}}, details.Rules)
+	assert.Equal(t, []string{"/src/api", "/src/app"}, details.Repos)
+	assert.Empty(t, details.Source)
+
+	data, err := json.Marshal(cm.profileDetails("personal"))
+	assert.NoError(t, err)
+	var decoded map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.JSONEq(t, `[{"dir": "~/oss/*", "profile": "personal"}]`, string(decoded["rules"]))
+	assert.JSONEq(t, `["/src/blog"]`, string(decoded["repos"]))
+	assert.Contains(t, string(decoded["profile"]), `"email":"john.personal@gmail.com"`)
+	assert.NotContains(t, decoded, "source")
+}
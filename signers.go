@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Markers delimiting the section of a file maintained by git-profile
+const (
+	managedBlockStart = "# BEGIN git-profile (managed, do not edit)"
+	managedBlockEnd   = "# END git-profile"
+)
+
+// replaceManagedBlock swaps the git-profile managed section of content for block, appending it if absent
+func replaceManagedBlock(content string, block string) string {
+	var kept []string
+	inside := false
+	for _, line := range strings.Split(content, "\n") {
+		switch {
+		case line == managedBlockStart:
+			inside = true
+		case line == managedBlockEnd:
+			inside = false
+		case !inside:
+			kept = append(kept, line)
+		}
+	}
+
+	result := strings.TrimRight(strings.Join(kept, "\n"), "\n")
+	if block == "" {
+		if result == "" {
+			return ""
+		}
+		return result + "\n"
+	}
+
+	if result != "" {
+		result += "\n\n"
+	}
+	return result + managedBlockStart + "\n" + strings.TrimRight(block, "\n") + "\n" + managedBlockEnd + "\n"
+}
+
+// allowedSignersPath returns the allowed signers file git-profile maintains
+func allowedSignersPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "git", "allowed_signers"), nil
+}
+
+// sshPublicKey resolves a signing key setting (literal key, key:: prefix, or key file path) to "type base64"
+func sshPublicKey(key string) (string, error) {
+	literal := strings.TrimPrefix(key, "key::")
+	if !isSSHKeyLiteral(literal) {
+		path := expandHome(key)
+		if !strings.HasSuffix(path, ".pub") {
+			if _, err := os.Stat(path + ".pub"); err == nil {
+				path += ".pub"
+			}
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		literal = string(data)
+	}
+
+	fields := strings.Fields(literal)
+	if len(fields) < 2 {
+		return "", errorf("'%s' is not an SSH public key", key)
+	}
+	return fields[0] + " " + fields[1], nil
+}
+
+// isSSHKeyLiteral reports whether s starts with an SSH public key type
+func isSSHKeyLiteral(s string) bool {
+	return strings.HasPrefix(s, "ssh-") || strings.HasPrefix(s, "ecdsa-") || strings.HasPrefix(s, "sk-")
+}
+
+// allowedSigners renders the allowed signers entries for every profile signing with an SSH key
+func (cm *ConfigManager) allowedSigners() (string, []error) {
+	var names []string
+	for name, profile := range cm.Profiles {
+		if profile.Signing.Key != "" && profile.SigningFormat() == signingFormatSSH {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var lines []string
+	var errs []error
+	for _, name := range names {
+		profile, err := resolveProfile(cm.Profiles[name])
+		if err != nil {
+			errs = append(errs, errorf("profile '%s': %w", name, err))
+			continue
+		}
+		publicKey, err := sshPublicKey(profile.Signing.Key)
+		if err != nil {
+			errs = append(errs, errorf("profile '%s': %w", name, err))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s namespaces=\"git\" %s %s", profile.Email, publicKey, name))
+	}
+	return strings.Join(lines, "\n"), errs
+}
+
+// syncAllowedSigners rewrites the managed section of the allowed signers file at path
+func (cm *ConfigManager) syncAllowedSigners(path string) []error {
+	block, errs := cm.allowedSigners()
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return append(errs, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return append(errs, err)
+	}
+	if err := os.WriteFile(path, []byte(replaceManagedBlock(string(existing), block)), 0644); err != nil {
+		return append(errs, err)
+	}
+	return errs
+}
+
+// signatureStatus describes a %G? signature code
+func signatureStatus(code string) (string, bool) {
+	switch code {
+	case "G":
+		return tr("good signature"), true
+	case "U":
+		return tr("good signature, unknown validity"), true
+	case "B":
+		return tr("bad signature"), false
+	case "X":
+		return tr("good signature, expired"), false
+	case "Y":
+		return tr("good signature, expired key"), false
+	case "R":
+		return tr("good signature, revoked key"), false
+	case "E":
+		return tr("signature can't be checked"), false
+	default:
+		return tr("not signed"), false
+	}
+}
+
+// newSignersCmd builds the signers command group
+func newSignersCmd(configManager *ConfigManager) *cobra.Command {
+	var signersCmd = &cobra.Command{
+		Use:   "signers",
+		Short: "Manage the allowed signers file used to verify SSH signatures",
+	}
+
+	var syncCmd = &cobra.Command{
+		Use:   "sync",
+		Short: "Write allowed signers entries for every profile with an SSH signing key",
+		Run: func(cmd *cobra.Command, args []string) {
+			path, err := allowedSignersPath()
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Sync failed:"), err)
+				os.Exit(1)
+			}
+
+			for _, err := range configManager.syncAllowedSigners(path) {
+				warnf("%v", err)
+			}
+			fmt.Fprintf(notices, tr("Allowed signers written to: %s\n"), path)
+		},
+	}
+
+	var count int
+	var verifyCmd = &cobra.Command{
+		Use:   "verify",
+		Short: "Check the signatures of recent commits against the allowed signers file",
+		Run: func(cmd *cobra.Command, args []string) {
+			path, err := allowedSignersPath()
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Verify failed:"), err)
+				os.Exit(1)
+			}
+
+			output, err := runGit(".", "-c", "gpg.ssh.allowedSignersFile="+path,
+				"log", "-n", strconv.Itoa(count), "--format=%h%x1f%G?%x1f%ae%x1f%s")
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Verify failed:"), err)
+				os.Exit(1)
+			}
+
+			failed := 0
+			for _, line := range strings.Split(output, "\n") {
+				fields := strings.SplitN(line, "\x1f", 4)
+				if len(fields) != 4 {
+					continue
+				}
+
+				status, ok := signatureStatus(fields[1])
+				marker := "✅"
+				if !ok {
+					marker = "❌"
+					failed++
+				}
+				fmt.Fprintf(stdout, "%s %s <%s> %s (%s)\n", marker, fields[0], fields[2], fields[3], status)
+			}
+
+			if failed > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+	verifyCmd.Flags().IntVarP(&count, "count", "n", 10, "Number of recent commits to check")
+
+	signersCmd.AddCommand(syncCmd, verifyCmd)
+	return signersCmd
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReplaceManagedBlock tests replacing only the git-profile section of a file
+func TestReplaceManagedBlock(t *testing.T) {
+	content := "manual entry\n"
+	updated := replaceManagedBlock(content, "generated one")
+	assert.Equal(t, "manual entry\n\n"+managedBlockStart+"\ngenerated one\n"+managedBlockEnd+"\n", updated)
+
+	updated = replaceManagedBlock(updated, "generated two")
+	assert.Contains(t, updated, "manual entry")
+	assert.Contains(t, updated, "generated two")
+	assert.NotContains(t, updated, "generated one")
+
+	assert.Equal(t, "manual entry\n", replaceManagedBlock(updated, ""))
+}
+
+// TestSyncAllowedSigners tests deriving allowed signers entries from profiles
+func TestSyncAllowedSigners(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	keyPath := filepath.Join(tmpDir, "id_work.pub")
+	assert.NoError(t, os.WriteFile(keyPath, []byte("ssh-ed25519 AAAAC3NzaWork john@laptop\n"), 0644))
+
+	work := Profile{Name: "John Doe", Email: "john.doe@company.com"}
+	work.Signing.Key = filepath.Join(tmpDir, "id_work")
+	oss := Profile{Name: "John Doe", Email: "john@oss.dev"}
+	oss.Signing.Key = "key::ssh-ed25519 AAAAC3NzaOss"
+	gpgProfile := Profile{Name: "John Doe", Email: "john@gpg.dev"}
+	gpgProfile.Signing.Key = "ABCDEF0123456789"
+
+	cm := &ConfigManager{
+		Profiles: map[string]Profile{"work": work, "oss": oss, "gpg": gpgProfile},
+	}
+
+	signersPath := filepath.Join(tmpDir, "git", "allowed_signers")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(signersPath), 0755))
+	assert.NoError(t, os.WriteFile(signersPath, []byte("friend@example.com ssh-ed25519 AAAAFriend\n"), 0644))
+
+	assert.Empty(t, cm.syncAllowedSigners(signersPath))
+
+	data, err := os.ReadFile(signersPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "friend@example.com ssh-ed25519 AAAAFriend")
+	assert.Contains(t, string(data), `john.doe@company.com namespaces="git" ssh-ed25519 AAAAC3NzaWork work`)
+	assert.Contains(t, string(data), `john@oss.dev namespaces="git" ssh-ed25519 AAAAC3NzaOss oss`)
+	assert.NotContains(t, string(data), "john@gpg.dev")
+
+	// Missing key files are reported without aborting the sync
+	work.Signing.Key = filepath.Join(tmpDir, "missing")
+	cm.Profiles["work"] = work
+	assert.Len(t, cm.syncAllowedSigners(signersPath), 1)
+}
+
+// TestProfileConfigSSHSigning tests the config written for SSH signing profiles
+func TestProfileConfigSSHSigning(t *testing.T) {
+	profile := Profile{Name: "John Doe", Email: "john@oss.dev"}
+	profile.Signing.Key = "ssh-ed25519 AAAAC3NzaOss"
+
+	entries := profileConfig("oss", profile)
+	assert.Contains(t, entries, configEntry{"gpg.format", "ssh"})
+	assert.Contains(t, entries, configEntry{"user.signingkey", "ssh-ed25519 AAAAC3NzaOss"})
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// sourcedProfile is a profile merged in from a shared source, as it was read from there
+type sourcedProfile struct {
+	Source  string
+	Profile Profile
+}
+
+// isRemoteSource reports whether a source is fetched over HTTP rather than read from disk
+func isRemoteSource(source string) bool {
+	return strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://")
+}
+
+// sourceClient fetches remote sources
+var sourceClient = &http.Client{Timeout: 5 * time.Second}
+
+// sourceCachePath returns where the last successful fetch of a remote source is kept
+func sourceCachePath(source string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(source))
+	return filepath.Join(homeDir, ".config", "git-profile", "sources", hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// sourceRefreshInterval is how long the cached copy of a remote source is used before it is fetched again
+const sourceRefreshInterval = time.Hour
+
+// fetchSource downloads a remote source unless its cached copy is recent enough or refresh is off, falling back to
+// the cached copy when offline
+func fetchSource(source string, refresh bool) ([]byte, error) {
+	cachePath, cacheErr := sourceCachePath(source)
+
+	// Every config load reads the sources, hooks and the prompt included, so they mustn't wait on the network
+	if !refresh && cacheErr == nil {
+		if info, err := os.Stat(cachePath); err == nil && time.Since(info.ModTime()) < sourceRefreshInterval {
+			if cached, err := os.ReadFile(cachePath); err == nil {
+				return cached, nil
+			}
+		}
+	}
+
+	resp, err := sourceClient.Get(source)
+	if err == nil {
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			err = errorf("%s returned %s", source, resp.Status)
+		}
+	}
+
+	var data []byte
+	if err == nil {
+		data, err = io.ReadAll(resp.Body)
+	}
+	if err == nil {
+		// The cache is as trusted as the source, so only the owner may change it
+		if cacheErr == nil && os.MkdirAll(filepath.Dir(cachePath), 0700) == nil && os.Chmod(filepath.Dir(cachePath), 0700) == nil {
+			if os.WriteFile(cachePath, data, 0600) == nil {
+				os.Chmod(cachePath, 0600)
+			}
+		}
+		return data, nil
+	}
+
+	if cacheErr == nil && !refresh {
+		if cached, readErr := os.ReadFile(cachePath); readErr == nil {
+			// Offline, the cached copy is used until the next interval instead of retrying on every load
+			now := time.Now()
+			os.Chtimes(cachePath, now, now)
+			return cached, nil
+		}
+	}
+	return nil, err
+}
+
+// readSource reads the profiles published by a shared source file or URL; remote sources are fetched when refresh is
+// set or their cached copy is older than sourceRefreshInterval
+func readSource(source string, refresh bool) (map[string]Profile, error) {
+	var data []byte
+	var err error
+	switch {
+	case strings.HasPrefix(source, "http://"):
+		// Shared profiles set config such as credential.helper and gpg.program that Git runs, so they can't come over
+		// a connection anyone on the way could rewrite
+		return nil, errorf("%s isn't fetched over plain HTTP, since shared profiles can make Git run commands; use https://", source)
+	case isRemoteSource(source):
+		data, err = fetchSource(source, refresh)
+	default:
+		data, err = os.ReadFile(expandHome(source))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := parseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	return config.Profiles, nil
+}
+
+// mergeSources adds the profiles of every shared source that aren't defined locally, earlier sources winning
+func (cm *ConfigManager) mergeSources() {
+	cm.sourced = make(map[string]sourcedProfile)
+	for _, source := range cm.Sources {
+		profiles, err := readSource(source, false)
+		if err != nil {
+			warnf("Profile source %s skipped: %v", source, err)
+			continue
+		}
+
+		for name, profile := range profiles {
+			if _, exists := cm.Profiles[name]; exists {
+				continue
+			}
+			cm.Profiles[name] = profile
+			cm.sourced[name] = sourcedProfile{Source: source, Profile: profile}
+		}
+	}
+}
+
+// sourceOf returns the shared source a profile comes from, if it isn't a local profile
+func (cm *ConfigManager) sourceOf(name string) (string, bool) {
+	sourced, exists := cm.sourced[name]
+	return sourced.Source, exists
+}
+
+// localProfiles returns the profiles to persist in the config file: local ones, plus shared ones that were edited into local overrides
+func (cm *ConfigManager) localProfiles() map[string]Profile {
+	if len(cm.sourced) == 0 && len(cm.fragmentOwner) == 0 {
+		return cm.Profiles
+	}
+
+	profiles := make(map[string]Profile)
+	for name, profile := range cm.Profiles {
+		if _, owned := cm.fragmentOwner[name]; owned {
+			continue
+		}
+		if sourced, exists := cm.sourced[name]; exists {
+			// Usage bookkeeping alone doesn't turn a shared profile into a local copy
+			unchanged := profile
+			unchanged.LastUsed = sourced.Profile.LastUsed
+			if reflect.DeepEqual(unchanged, sourced.Profile) {
+				continue
+			}
+		}
+		profiles[name] = profile
+	}
+	return profiles
+}
+
+// newSourceCmd builds the source command group
+func newSourceCmd(configManager *ConfigManager) *cobra.Command {
+	var sourceCmd = &cobra.Command{
+		Use:   "source",
+		Short: "Manage shared, read-only profile sources",
+	}
+
+	var listCmd = &cobra.Command{
+		Use:   "ls",
+		Short: "List profile sources",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(configManager.Sources) == 0 {
+				fmt.Fprintln(stdout, tr("No profile sources configured."))
+				return
+			}
+
+			for i, source := range configManager.Sources {
+				count := 0
+				for _, sourced := range configManager.sourced {
+					if sourced.Source == source {
+						count++
+					}
+				}
+				fmt.Fprintf(stdout, tr("%d. %s (%d profiles)\n"), i+1, source, count)
+			}
+		},
+	}
+
+	var addCmd = &cobra.Command{
+		Use:   "add <file|url>",
+		Short: "Add a JSON file or HTTPS URL whose profiles are merged read-only",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			profiles, err := readSource(args[0], true)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Add failed:"), err)
+				os.Exit(1)
+			}
+
+			configManager.Sources = append(configManager.Sources, args[0])
+			configManager.save()
+			fmt.Fprintf(notices, tr("Source added with %d profiles.\n"), len(profiles))
+		},
+	}
+
+	var removeCmd = &cobra.Command{
+		Use:   "rm <n>",
+		Short: "Remove the source with the given number (see 'source ls')",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			index, err := strconv.Atoi(args[0])
+			if err != nil || index < 1 || index > len(configManager.Sources) {
+				fmt.Fprintf(stdout, tr("No source number %s.\n"), args[0])
+				os.Exit(1)
+			}
+
+			source := configManager.Sources[index-1]
+			configManager.Sources = append(configManager.Sources[:index-1], configManager.Sources[index:]...)
+			configManager.save()
+			fmt.Fprintf(notices, tr("Source %s removed.\n"), source)
+		},
+	}
+
+	var refreshCmd = &cobra.Command{
+		Use:   "refresh",
+		Short: "Fetch every remote source now instead of waiting for its cached copy to expire",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			failed := 0
+			for _, source := range configManager.Sources {
+				if !isRemoteSource(source) {
+					continue
+				}
+				profiles, err := readSource(source, true)
+				if err != nil {
+					failed++
+					fmt.Fprintf(stdout, "  ❌ %s: %v\n", source, err)
+					continue
+				}
+				fmt.Fprintf(notices, tr("  ✅ %s (%d profiles)\n"), source, len(profiles))
+			}
+			if failed > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+
+	sourceCmd.AddCommand(listCmd, addCmd, removeCmd, refreshCmd)
+	return sourceCmd
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProfileSources tests merging shared profiles read-only beneath local ones
+func TestProfileSources(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	sourcePath := filepath.Join(tmpDir, "team.json")
+	team, err := json.Marshal(configFile{Profiles: map[string]Profile{
+		"corp":     {Name: "Team Default", Email: "dev@company.com"},
+		"personal": {Name: "Not Me", Email: "someone@company.com"},
+	}})
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(sourcePath, team, 0644))
+
+	cm := &ConfigManager{
+		ConfigPath: filepath.Join(tmpDir, ".git-profiles-test.json"),
+		Profiles: map[string]Profile{
+			"personal": {Name: "John Personal", Email: "john.personal@gmail.com"},
+		},
+		Sources: []string{sourcePath},
+	}
+	cm.save()
+
+	loaded := &ConfigManager{ConfigPath: cm.ConfigPath}
+	loaded.load()
+	assert.Equal(t, "dev@company.com", loaded.Profiles["corp"].Email)
+	assert.Equal(t, "john.personal@gmail.com", loaded.Profiles["personal"].Email)
+	source, shared := loaded.sourceOf("corp")
+	assert.True(t, shared)
+	assert.Equal(t, sourcePath, source)
+	_, shared = loaded.sourceOf("personal")
+	assert.False(t, shared)
+
+	// Shared profiles aren't copied into the local store, even after being applied
+	loaded.markUsed("corp")
+	var saved configFile
+	data, err := os.ReadFile(loaded.ConfigPath)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(data, &saved))
+	assert.NotContains(t, saved.Profiles, "corp")
+
+	// Editing a shared profile turns it into a local override
+	corp := loaded.Profiles["corp"]
+	corp.Name = "John Doe"
+	now := time.Now()
+	corp.Updated = &now
+	loaded.Profiles["corp"] = corp
+	loaded.save()
+
+	reloaded := &ConfigManager{ConfigPath: cm.ConfigPath}
+	reloaded.load()
+	assert.Equal(t, "John Doe", reloaded.Profiles["corp"].Name)
+	_, shared = reloaded.sourceOf("corp")
+	assert.False(t, shared)
+}
+
+// TestRemoteProfileSource tests fetching a source over HTTP and falling back to the cached copy
+func TestRemoteProfileSource(t *testing.T) {
+	homeDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(homeDir)
+	t.Setenv("HOME", homeDir)
+
+	email := "dev@company.com"
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"profiles": {"corp": {"name": "Team Default", "email": "` + email + `"}}}`))
+	}))
+	defer func(client *http.Client) { sourceClient = client }(sourceClient)
+	sourceClient = server.Client()
+
+	source := server.URL + "/profiles.json"
+	profiles, err := readSource(source, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "dev@company.com", profiles["corp"].Email)
+
+	// Only the owner can read or change the cached copy
+	cachePath, err := sourceCachePath(source)
+	assert.NoError(t, err)
+	info, err := os.Stat(cachePath)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	info, err = os.Stat(filepath.Dir(cachePath))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+
+	// Plain HTTP would let anyone on the way hand out profiles that run commands
+	_, err = readSource("http"+strings.TrimPrefix(source, "https"), true)
+	assert.ErrorContains(t, err, "plain HTTP")
+
+	// The cached copy is used until it expires or a refresh is asked for
+	email = "team@company.com"
+	profiles, err = readSource(source, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "dev@company.com", profiles["corp"].Email)
+	profiles, err = readSource(source, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "team@company.com", profiles["corp"].Email)
+
+	email = "platform@company.com"
+	expired := time.Now().Add(-sourceRefreshInterval)
+	assert.NoError(t, os.Chtimes(cachePath, expired, expired))
+	profiles, err = readSource(source, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "platform@company.com", profiles["corp"].Email)
+
+	// Offline, an expired copy is still used rather than failing
+	server.Close()
+	assert.NoError(t, os.Chtimes(cachePath, expired, expired))
+	profiles, err = readSource(source, false)
+	assert.NoError(t, err)
+	assert.Contains(t, profiles, "corp")
+	_, err = readSource(source, true)
+	assert.Error(t, err)
+
+	_, err = readSource(server.URL+"/never-fetched.json", false)
+	assert.Error(t, err)
+}
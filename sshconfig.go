@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// sshConfigPath returns the OpenSSH client config git-profile writes host aliases into
+func sshConfigPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".ssh", "config"), nil
+}
+
+// sshHost returns the forge host a profile's SSH alias points at
+func (p Profile) sshHost() string {
+	if p.Host != "" {
+		return p.Host
+	}
+	return "github.com"
+}
+
+// sshAlias returns the SSH host alias of a profile, e.g. github.com-work
+func (p Profile) sshAlias(name string) string {
+	return p.sshHost() + "-" + name
+}
+
+// sshConfigBlock renders a Host block for every profile with an SSH key
+func (cm *ConfigManager) sshConfigBlock() string {
+	var names []string
+	for name, profile := range cm.Profiles {
+		if profile.SSH.Key != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var blocks []string
+	for _, name := range names {
+		profile, err := resolveProfile(cm.Profiles[name])
+		if err != nil {
+			warnf("SSH config entry of profile '%s' skipped: %v", name, err)
+			continue
+		}
+		blocks = append(blocks, fmt.Sprintf("Host %s\n    HostName %s\n    User git\n    IdentityFile %s\n    IdentitiesOnly yes",
+			profile.sshAlias(name), profile.sshHost(), profile.SSH.Key))
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// syncSSHConfig rewrites the managed section of the SSH config at path
+func (cm *ConfigManager) syncSSHConfig(path string) error {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(replaceManagedBlock(string(existing), cm.sshConfigBlock())), 0600)
+}
+
+// rewriteRemoteURL turns a remote URL on the profile's host into an SSH URL using its host alias
+func rewriteRemoteURL(url string, name string, profile Profile) (string, error) {
+	host, path, found := strings.Cut(normalizeRemoteURL(url), "/")
+	if !found {
+		return "", errorf("can't parse remote URL '%s'", url)
+	}
+
+	alias := profile.sshAlias(name)
+	if !strings.EqualFold(host, profile.sshHost()) && !strings.EqualFold(host, alias) {
+		return "", errorf("remote '%s' is not on %s", url, profile.sshHost())
+	}
+	return "git@" + alias + ":" + path + ".git", nil
+}
+
+// rewriteOrigin points the origin remote of the repository at dir at the profile's SSH host alias
+func rewriteOrigin(dir string, name string, profile Profile) (string, error) {
+	if profile.SSH.Key == "" {
+		return "", errorf("profile '%s' has no SSH key, so it has no host alias", name)
+	}
+
+	url, err := gitConfigGet(dir, "remote.origin.url")
+	if err != nil {
+		return "", err
+	}
+	if url == "" {
+		return "", errorf("the repository has no origin remote")
+	}
+
+	rewritten, err := rewriteRemoteURL(url, name, profile)
+	if err != nil {
+		return "", err
+	}
+	if _, err := runGit(dir, "remote", "set-url", "origin", rewritten); err != nil {
+		return "", err
+	}
+	return rewritten, nil
+}
+
+// newSSHCmd builds the ssh command group
+func newSSHCmd(configManager *ConfigManager) *cobra.Command {
+	var sshCmd = &cobra.Command{
+		Use:   "ssh",
+		Short: "Manage per-profile SSH keys and host aliases",
+	}
+
+	var syncCmd = &cobra.Command{
+		Use:   "sync",
+		Short: "Write a Host alias to ~/.ssh/config for every profile with an SSH key",
+		Run: func(cmd *cobra.Command, args []string) {
+			path, err := sshConfigPath()
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Sync failed:"), err)
+				os.Exit(1)
+			}
+
+			if err := configManager.syncSSHConfig(path); err != nil {
+				fmt.Fprintln(stdout, tr("Sync failed:"), err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(notices, tr("SSH host aliases written to: %s\n"), path)
+		},
+	}
+
+	sshCmd.AddCommand(syncCmd, newSSHGenerateCmd(configManager))
+	return sshCmd
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSyncSSHConfig tests writing managed host aliases while keeping the user's own entries
+func TestSyncSSHConfig(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	work := Profile{Name: "John Doe", Email: "john.doe@company.com"}
+	work.SSH.Key = "~/.ssh/id_ed25519_work"
+	gitlab := Profile{Name: "John Doe", Email: "john@gitlab.dev", Host: "gitlab.com"}
+	gitlab.SSH.Key = "~/.ssh/id_ed25519_gitlab"
+	cm := &ConfigManager{
+		Profiles: map[string]Profile{
+			"work":     work,
+			"gitlab":   gitlab,
+			"personal": {Name: "John Personal", Email: "john.personal@gmail.com"},
+		},
+	}
+
+	path := filepath.Join(tmpDir, "config")
+	assert.NoError(t, os.WriteFile(path, []byte("Host myserver\n    HostName 10.0.0.1\n"), 0600))
+	assert.NoError(t, cm.syncSSHConfig(path))
+	assert.NoError(t, cm.syncSSHConfig(path))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "Host myserver\n    HostName 10.0.0.1\n\n"+managedBlockStart+"\n"+
+		"Host gitlab.com-gitlab\n    HostName gitlab.com\n    User git\n    IdentityFile ~/.ssh/id_ed25519_gitlab\n    IdentitiesOnly yes\n\n"+
+		"Host github.com-work\n    HostName github.com\n    User git\n    IdentityFile ~/.ssh/id_ed25519_work\n    IdentitiesOnly yes\n"+
+		managedBlockEnd+"\n", string(data))
+}
+
+// TestRewriteOrigin tests pointing origin at a profile's SSH host alias
+func TestRewriteOrigin(t *testing.T) {
+	work := Profile{Name: "John Doe", Email: "john.doe@company.com"}
+	work.SSH.Key = "~/.ssh/id_ed25519_work"
+
+	for _, url := range []string{"https://github.com/acme/api.git", "git@github.com:acme/api.git", "ssh://git@github.com/acme/api", "git@github.com-work:acme/api.git"} {
+		rewritten, err := rewriteRemoteURL(url, "work", work)
+		assert.NoError(t, err)
+		assert.Equal(t, "git@github.com-work:acme/api.git", rewritten)
+	}
+	_, err := rewriteRemoteURL("git@gitlab.com:acme/api.git", "work", work)
+	assert.Error(t, err)
+
+	repoDir := initTestRepo(t)
+	_, err = runGit(repoDir, "remote", "add", "origin", "https://github.com/acme/api.git")
+	assert.NoError(t, err)
+	_, err = rewriteOrigin(repoDir, "work", work)
+	assert.NoError(t, err)
+	url, err := gitConfigGet(repoDir, "remote.origin.url")
+	assert.NoError(t, err)
+	assert.Equal(t, "git@github.com-work:acme/api.git", url)
+
+	_, err = rewriteOrigin(repoDir, "personal", Profile{Name: "John Personal", Email: "john.personal@gmail.com"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultSSHKeyPath returns where 'ssh generate' puts a profile's key, e.g. ~/.ssh/id_ed25519_work
+func defaultSSHKeyPath(name string) (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".ssh", "id_ed25519_"+name), nil
+}
+
+// generateSSHKey creates an ed25519 keypair at path, letting ssh-keygen prompt for a passphrase unless noPassphrase is set
+func generateSSHKey(path string, comment string, noPassphrase bool) error {
+	if _, err := os.Stat(path); err == nil {
+		return errorf("%s already exists", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	args := []string{"-q", "-t", "ed25519", "-C", comment, "-f", path}
+	if noPassphrase {
+		args = append(args, "-N", "")
+	}
+
+	cmd := exec.Command("ssh-keygen", args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return errorf("ssh-keygen: %w", err)
+	}
+	return nil
+}
+
+// errKeyNotInAgent reports a profile SSH key missing from the ssh-agent
+var errKeyNotInAgent = errors.New(tr("SSH key is not loaded in the ssh-agent"))
+
+// sshAddArgs returns the ssh-add arguments loading path, storing the passphrase in the macOS keychain
+func sshAddArgs(goos string, path string) []string {
+	if goos == "darwin" {
+		return []string{"--apple-use-keychain", path}
+	}
+	return []string{path}
+}
+
+// addToAgent loads the private key at path into the running ssh-agent
+func addToAgent(path string) error {
+	cmd := exec.Command("ssh-add", sshAddArgs(runtime.GOOS, path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return errorf("ssh-add: %w", err)
+	}
+	return nil
+}
+
+// sshFingerprint returns the SHA256 fingerprint from a line of ssh-keygen -l or ssh-add -l output
+func sshFingerprint(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return ""
+	}
+	return fields[1]
+}
+
+// agentFingerprints lists the fingerprints of the ssh-agent's keys in the order they are offered
+func agentFingerprints() ([]string, error) {
+	output, err := exec.Command("ssh-add", "-l").Output()
+	if err != nil {
+		// ssh-add exits 1 when the agent holds no keys and 2 when it can't be reached
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, errorf("no ssh-agent is running")
+	}
+
+	var fingerprints []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if fingerprint := sshFingerprint(line); fingerprint != "" {
+			fingerprints = append(fingerprints, fingerprint)
+		}
+	}
+	return fingerprints, nil
+}
+
+// checkAgentKey reports whether the fingerprint is loaded in the agent and offered before any other key
+func checkAgentKey(fingerprints []string, fingerprint string) error {
+	for i, loaded := range fingerprints {
+		if loaded != fingerprint {
+			continue
+		}
+		if i > 0 {
+			return errorf("SSH key is loaded but %d other key(s) are offered first; hosts that limit auth attempts may pick the wrong account", i)
+		}
+		return nil
+	}
+	return errKeyNotInAgent
+}
+
+// checkSSHAgent verifies that a profile's SSH key is the first key the ssh-agent offers
+func checkSSHAgent(profile Profile) error {
+	if profile.SSH.Key == "" {
+		return nil
+	}
+
+	path := expandHome(profile.SSH.Key)
+	output, err := exec.Command("ssh-keygen", "-lf", path).Output()
+	if err != nil {
+		return errorf("can't read SSH key %s", profile.SSH.Key)
+	}
+
+	fingerprints, err := agentFingerprints()
+	if err != nil {
+		return err
+	}
+	return checkAgentKey(fingerprints, sshFingerprint(string(output)))
+}
+
+// checkSSHAgentFinding reports problems with the applied profile's SSH key in the ssh-agent; unresolvable placeholders
+// are reported by checkSigningKeyFinding
+func checkSSHAgentFinding(cm *ConfigManager, dir string) []doctorFinding {
+	name, found := cm.appliedProfile(dir)
+	if !found {
+		return nil
+	}
+	profile, err := resolveProfile(cm.Profiles[name])
+	if err != nil {
+		return nil
+	}
+
+	if err := checkSSHAgent(profile); err != nil {
+		return []doctorFinding{{severityWarning, fmt.Sprintf(tr("profile '%s': %v"), name, err)}}
+	}
+	return nil
+}
+
+// newSSHGenerateCmd builds the ssh generate command
+func newSSHGenerateCmd(configManager *ConfigManager) *cobra.Command {
+	var path string
+	var noPassphrase, agent bool
+
+	var generateCmd = &cobra.Command{
+		Use:   "generate <profile>",
+		Short: "Create an ed25519 SSH key for a profile",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := args[0]
+			profile, exists := configManager.Profiles[name]
+			if !exists {
+				fmt.Fprintf(stdout, tr("Profile '%s' not found.\n"), name)
+				os.Exit(1)
+			}
+
+			keyPath := expandHome(path)
+			if keyPath == "" {
+				defaultPath, err := defaultSSHKeyPath(name)
+				if err != nil {
+					fmt.Fprintln(stdout, tr("Generate failed:"), err)
+					os.Exit(1)
+				}
+				keyPath = defaultPath
+			}
+
+			resolved, err := resolveProfile(profile)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Generate failed:"), err)
+				os.Exit(1)
+			}
+			if err := generateSSHKey(keyPath, resolved.Email, noPassphrase); err != nil {
+				fmt.Fprintln(stdout, tr("Generate failed:"), err)
+				os.Exit(1)
+			}
+
+			profile.SSH.Key = keyPath
+			configManager.Profiles[name] = profile
+			configManager.save()
+
+			if configPath, err := sshConfigPath(); err == nil {
+				if err := configManager.syncSSHConfig(configPath); err != nil {
+					warnf("%v", err)
+				}
+			}
+
+			publicKey, err := os.ReadFile(keyPath + ".pub")
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Generate failed:"), err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(notices, tr("SSH key for profile '%s' written to %s (Host %s).\n"), name, keyPath, profile.sshAlias(name))
+			fmt.Fprintln(notices, tr("Upload this public key to your account:"))
+			fmt.Fprintln(notices)
+			fmt.Fprintln(stdout, strings.TrimSpace(string(publicKey)))
+			fmt.Fprintln(notices)
+
+			if agent {
+				if err := addToAgent(keyPath); err != nil {
+					warnf("%v", err)
+				}
+			}
+		},
+	}
+	generateCmd.Flags().StringVar(&path, "path", "", "Key file to create (defaults to ~/.ssh/id_ed25519_<profile>)")
+	generateCmd.Flags().BoolVar(&noPassphrase, "no-passphrase", false, "Create the key without a passphrase")
+	generateCmd.Flags().BoolVar(&agent, "agent", false, "Add the new key to the ssh-agent")
+
+	return generateCmd
+}
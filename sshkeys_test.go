@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGenerateSSHKey tests creating a profile key pair with ssh-keygen
+func TestGenerateSSHKey(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen is not installed")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	path := filepath.Join(tmpDir, ".ssh", "id_ed25519_work")
+	assert.NoError(t, generateSSHKey(path, "john.doe@company.com", true))
+
+	publicKey, err := os.ReadFile(path + ".pub")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(publicKey), "ssh-ed25519 "))
+	assert.Contains(t, string(publicKey), "john.doe@company.com")
+
+	// Existing keys are never overwritten
+	assert.Error(t, generateSSHKey(path, "john.doe@company.com", true))
+}
+
+// TestCheckAgentKey tests detecting missing keys and keys offered after others
+func TestCheckAgentKey(t *testing.T) {
+	assert.Equal(t, "SHA256:abc", sshFingerprint("256 SHA256:abc john.doe@company.com (ED25519)"))
+
+	assert.NoError(t, checkAgentKey([]string{"SHA256:abc", "SHA256:def"}, "SHA256:abc"))
+	assert.ErrorContains(t, checkAgentKey([]string{"SHA256:def", "SHA256:abc"}, "SHA256:abc"), "offered first")
+	assert.ErrorIs(t, checkAgentKey(nil, "SHA256:abc"), errKeyNotInAgent)
+
+	assert.Equal(t, []string{"--apple-use-keychain", "/keys/id"}, sshAddArgs("darwin", "/keys/id"))
+	assert.Equal(t, []string{"/keys/id"}, sshAddArgs("linux", "/keys/id"))
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// statusKeys are the settings status explains
+var statusKeys = []string{"user.name", "user.email", "user.signingkey"}
+
+// roleStatusKeys override user.* for the author or committer only, and are explained when set
+var roleStatusKeys = []string{"author.name", "author.email", "committer.name", "committer.email"}
+
+// identityEnvOverrides are the environment variables that take precedence over each setting when committing
+var identityEnvOverrides = map[string][]string{
+	"user.name":       {"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"},
+	"user.email":      {"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"},
+	"author.name":     {"GIT_AUTHOR_NAME"},
+	"author.email":    {"GIT_AUTHOR_EMAIL"},
+	"committer.name":  {"GIT_COMMITTER_NAME"},
+	"committer.email": {"GIT_COMMITTER_EMAIL"},
+}
+
+// configSource is one value of a Git config key and the file it was read from
+type configSource struct {
+	Scope    string
+	Origin   string
+	Value    string
+	Included bool
+}
+
+// configResolution lists every value of a key in the order Git reads them, so the last one wins
+type configResolution struct {
+	Key       string
+	Sources   []configSource
+	Overrides []string
+}
+
+// Effective returns the value Git uses
+func (r configResolution) Effective() (configSource, bool) {
+	if len(r.Sources) == 0 {
+		return configSource{}, false
+	}
+	return r.Sources[len(r.Sources)-1], true
+}
+
+// mainConfigFiles returns the files Git reads directly for each scope, so anything else was pulled in by an include
+func mainConfigFiles(dir string) map[string][]string {
+	files := make(map[string][]string)
+	for scope, name := range map[string]string{"local": "config", "worktree": "config.worktree"} {
+		if path, err := runGit(dir, "rev-parse", "--git-path", name); err == nil {
+			files[scope] = []string{absolutePath(dir, path)}
+		}
+	}
+
+	if path := os.Getenv("GIT_CONFIG_GLOBAL"); path != "" {
+		files["global"] = []string{absolutePath(dir, path)}
+	} else if homeDir, err := os.UserHomeDir(); err == nil {
+		xdg := os.Getenv("XDG_CONFIG_HOME")
+		if xdg == "" {
+			xdg = filepath.Join(homeDir, ".config")
+		}
+		files["global"] = []string{filepath.Join(homeDir, ".gitconfig"), filepath.Join(xdg, "git", "config")}
+	}
+	return files
+}
+
+// absolutePath resolves path against dir
+func absolutePath(dir string, path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return absolute
+}
+
+// resolveConfigKey lists where every value of key in the repository at dir comes from
+func resolveConfigKey(dir string, key string, mainFiles map[string][]string) (configResolution, error) {
+	resolution := configResolution{Key: key}
+	for _, name := range identityEnvOverrides[key] {
+		if value, set := os.LookupEnv(name); set {
+			resolution.Overrides = append(resolution.Overrides, name+"="+value)
+		}
+	}
+
+	cmd := exec.Command("git", "config", "--null", "--show-scope", "--show-origin", "--get-all", key)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	traceGit(dir, cmd.Args[1:], err, "")
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return resolution, nil
+		}
+		return resolution, errorf("git config --get-all %s: %w", key, err)
+	}
+
+	// Each value comes as scope, origin and value, NUL-terminated
+	fields := strings.Split(strings.TrimSuffix(string(output), "\x00"), "\x00")
+	for i := 0; i+2 < len(fields); i += 3 {
+		source := configSource{Scope: fields[i], Origin: fields[i+1], Value: fields[i+2]}
+		if path, isFile := strings.CutPrefix(source.Origin, "file:"); isFile {
+			source.Origin = absolutePath(dir, path)
+			if main, known := mainFiles[source.Scope]; known {
+				source.Included = true
+				for _, mainPath := range main {
+					if mainPath == source.Origin {
+						source.Included = false
+					}
+				}
+			}
+		}
+		resolution.Sources = append(resolution.Sources, source)
+	}
+	return resolution, nil
+}
+
+// newStatusCmd builds the status command
+func newStatusCmd(configManager *ConfigManager) *cobra.Command {
+	var porcelain bool
+	var statusCmd = &cobra.Command{
+		Use:   "status",
+		Short: "Explain where the current repository's identity settings come from",
+		Run: func(cmd *cobra.Command, args []string) {
+			if porcelain {
+				if err := configManager.writeStatusPorcelain(stdout, "."); err != nil {
+					fmt.Fprintln(stdout, tr("Status failed:"), err)
+					os.Exit(1)
+				}
+				return
+			}
+
+			if repo := repoTopLevel("."); repo != "" {
+				line := fmt.Sprintf(tr("📂 Repository: %s"), repo)
+				if assigned, _ := gitConfigGet(".", assignedProfileKey); assigned != "" {
+					line += fmt.Sprintf(tr(" (profile '%s' applied)"), assigned)
+				}
+				fmt.Fprintln(stdout, line)
+			} else {
+				fmt.Fprintln(stdout, tr("📂 Not inside a Git repository; showing global settings"))
+			}
+
+			mainFiles := mainConfigFiles(".")
+			includes, _ := includeDir()
+			for _, key := range append(statusKeys, roleStatusKeys...) {
+				resolution, err := resolveConfigKey(".", key, mainFiles)
+				if err != nil {
+					fmt.Fprintln(stdout, tr("Status failed:"), err)
+					os.Exit(1)
+				}
+				if len(resolution.Sources) == 0 && slices.Contains(roleStatusKeys, key) {
+					continue
+				}
+
+				fmt.Fprintf(stdout, "\n%s\n", key)
+				if len(resolution.Sources) == 0 {
+					fmt.Fprintln(stdout, tr("  (not set)"))
+				}
+
+				writer := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+				for i, source := range resolution.Sources {
+					origin := displayPath(source.Origin)
+					switch {
+					case includes != "" && strings.HasPrefix(source.Origin, includes+string(filepath.Separator)):
+						origin += tr(" (includeIf from 'rules install')")
+					case source.Included:
+						origin += tr(" (included)")
+					}
+					winner := ""
+					if i == len(resolution.Sources)-1 {
+						winner = tr("← wins")
+					}
+					fmt.Fprintf(writer, "  %s\t%s\t%s\t%s\n", source.Scope, origin, source.Value, winner)
+				}
+				writer.Flush()
+
+				for _, override := range resolution.Overrides {
+					fmt.Fprintf(stdout, tr("  ⚠️  %s overrides it for commits made from this environment\n"), override)
+				}
+			}
+
+			if name, found := configManager.appliedProfile("."); found {
+				fmt.Fprintf(stdout, tr("\n🎯 Profile in use: %s\n"), name)
+			}
+		},
+	}
+
+	statusCmd.Flags().BoolVar(&porcelain, "porcelain", false, "Print the stable, tab-separated format for scripts")
+	return statusCmd
+}
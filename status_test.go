@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestResolveConfigKey tests tracing each value of a setting to its scope and file
+func TestResolveConfigKey(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("GIT_CONFIG_GLOBAL", "")
+	os.Unsetenv("GIT_CONFIG_GLOBAL")
+	t.Setenv("GIT_AUTHOR_EMAIL", "ci@company.com")
+
+	includePath := filepath.Join(tmpDir, "work.gitconfig")
+	assert.NoError(t, os.WriteFile(includePath, []byte("[user]\n\temail = john.doe@company.com\n"), 0644))
+	globalPath := filepath.Join(tmpDir, ".gitconfig")
+	assert.NoError(t, os.WriteFile(globalPath, []byte("[user]\n\temail = john@home.dev\n[include]\n\tpath = "+includePath+"\n"), 0644))
+
+	repoDir := initTestRepo(t)
+	_, err = runGit(repoDir, "config", "--local", "user.email", "john.doe@acme.com")
+	assert.NoError(t, err)
+	localPath, err := filepath.EvalSymlinks(filepath.Join(repoDir, ".git", "config"))
+	assert.NoError(t, err)
+
+	resolution, err := resolveConfigKey(repoDir, "user.email", mainConfigFiles(repoDir))
+	assert.NoError(t, err)
+	assert.Len(t, resolution.Sources, 3)
+	assert.Equal(t, configSource{Scope: "global", Origin: globalPath, Value: "john@home.dev"}, resolution.Sources[0])
+	assert.Equal(t, configSource{Scope: "global", Origin: includePath, Value: "john.doe@company.com", Included: true}, resolution.Sources[1])
+
+	effective, found := resolution.Effective()
+	assert.True(t, found)
+	assert.Equal(t, "local", effective.Scope)
+	assert.Equal(t, "john.doe@acme.com", effective.Value)
+	origin, err := filepath.EvalSymlinks(effective.Origin)
+	assert.NoError(t, err)
+	assert.Equal(t, localPath, origin)
+	assert.False(t, effective.Included)
+	assert.Equal(t, []string{"GIT_AUTHOR_EMAIL=ci@company.com"}, resolution.Overrides)
+
+	resolution, err = resolveConfigKey(repoDir, "user.signingkey", mainConfigFiles(repoDir))
+	assert.NoError(t, err)
+	_, found = resolution.Effective()
+	assert.False(t, found)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// submodulePaths lists the initialized submodules of the repository at dir, nested ones included, as absolute paths
+func submodulePaths(dir string) ([]string, error) {
+	output, err := runGit(dir, "submodule", "foreach", "--quiet", "--recursive", "pwd")
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			paths = append(paths, line)
+		}
+	}
+	return paths, nil
+}
+
+// applyToSubmodules applies a profile to the local config of every initialized submodule of the repository at dir,
+// since submodules don't inherit the superproject's identity; it returns how many were applied and the failures
+func applyToSubmodules(dir string, name string, profile Profile) (int, []error) {
+	paths, err := submodulePaths(dir)
+	if err != nil {
+		return 0, []error{errorf("listing submodules: %w", err)}
+	}
+
+	applied := 0
+	var failures []error
+	for _, path := range paths {
+		if err := applyProfile(path, name, profile); err != nil {
+			failures = append(failures, errorf("submodule %s: %w", displayPath(path), err))
+			continue
+		}
+		applied++
+	}
+	return applied, failures
+}
+
+// dryRunSubmodules prints the commands applying a profile to every initialized submodule would run
+func dryRunSubmodules(w io.Writer, dir string, name string, profile Profile) error {
+	paths, err := submodulePaths(dir)
+	if err != nil {
+		return errorf("listing submodules: %w", err)
+	}
+	for _, path := range paths {
+		fmt.Fprintf(w, "# in submodule %s\n", displayPath(path))
+		if err := dryRunApply(w, path, name, profile); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestApplyToSubmodules tests applying a profile to the initialized submodules of a repository
+func TestApplyToSubmodules(t *testing.T) {
+	libDir := initTestRepo(t)
+	commitAs(t, libDir, "John Doe", "john.doe@company.com")
+
+	repoDir := initTestRepo(t)
+	_, err := runGit(repoDir, "-c", "protocol.file.allow=always", "submodule", "add", "--quiet", libDir, "vendor/lib")
+	assert.NoError(t, err)
+
+	paths, err := submodulePaths(repoDir)
+	assert.NoError(t, err)
+	libPath, err := filepath.EvalSymlinks(filepath.Join(repoDir, "vendor", "lib"))
+	assert.NoError(t, err)
+	assert.Len(t, paths, 1)
+	resolved, _ := filepath.EvalSymlinks(paths[0])
+	assert.Equal(t, libPath, resolved)
+
+	work := Profile{Name: "John Doe", Email: "john.doe@company.com"}
+	var buf bytes.Buffer
+	assert.NoError(t, dryRunSubmodules(&buf, repoDir, "work", work))
+	assert.Contains(t, buf.String(), "# in submodule ")
+	assert.Contains(t, buf.String(), "git config user.email john.doe@company.com")
+
+	applied, failures := applyToSubmodules(repoDir, "work", work)
+	assert.Empty(t, failures)
+	assert.Equal(t, 1, applied)
+
+	entries, err := gitConfigEntries(libPath, []string{"--local"}, `^user\.email$`)
+	assert.NoError(t, err)
+	assert.Equal(t, []configEntry{{"user.email", "john.doe@company.com"}}, entries)
+	assigned, _ := gitConfigGet(libPath, assignedProfileKey)
+	assert.Equal(t, "work", assigned)
+
+	// Without submodules there is nothing to do
+	applied, failures = applyToSubmodules(libDir, "work", work)
+	assert.Empty(t, failures)
+	assert.Zero(t, applied)
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// syncFileName is the file holding the synced profile store inside the sync repository
+const syncFileName = "profiles.json"
+
+// syncedRef marks the commit this machine last pushed or pulled, the base of three-way merges
+const syncedRef = "refs/git-profile/synced"
+
+// syncDir returns the local clone of the sync repository
+func syncDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "git-profile", "sync"), nil
+}
+
+// runSyncGit runs git in the sync repository, committing under a fixed identity so no profile has to be applied there
+func runSyncGit(dir string, args ...string) (string, error) {
+	hostname, _ := os.Hostname()
+	identity := []string{"-c", "user.name=git-profile sync", "-c", "user.email=git-profile@" + hostname, "-c", "commit.gpgsign=false"}
+	return runGit(dir, append(identity, args...)...)
+}
+
+// setupSync clones the sync repository into dir
+func setupSync(dir string, url string) error {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		remote, _ := gitConfigGet(dir, "remote.origin.url")
+		return errorf("sync is already set up with %s (remove %s to start over)", remote, dir)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return err
+	}
+	_, err := runGit("", "clone", "--quiet", url, dir)
+	return err
+}
+
+// syncSnapshot returns the parts of the config that follow the user across machines
+func (cm *ConfigManager) syncSnapshot() configFile {
+	return configFile{
+		Profiles:  cm.localProfiles(),
+		Rules:     cm.Rules,
+		Templates: cm.Templates,
+		Sources:   cm.Sources,
+	}
+}
+
+// restoreSnapshot replaces the synced parts of the config with snapshot
+func (cm *ConfigManager) restoreSnapshot(snapshot configFile) {
+	cm.Rules = snapshot.Rules
+	cm.Templates = snapshot.Templates
+	cm.Sources = snapshot.Sources
+	cm.replaceProfiles(snapshot.Profiles)
+	cm.save()
+}
+
+// pushSync commits the current profile store to the sync repository and pushes it, reporting whether anything changed
+func (cm *ConfigManager) pushSync(dir string) (bool, error) {
+	data, err := json.MarshalIndent(cm.syncSnapshot(), "", "  ")
+	if err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, syncFileName), append(data, '\n'), 0644); err != nil {
+		return false, err
+	}
+
+	if _, err := runSyncGit(dir, "add", syncFileName); err != nil {
+		return false, err
+	}
+	status, err := runSyncGit(dir, "status", "--porcelain")
+	if err != nil {
+		return false, err
+	}
+	if status != "" {
+		hostname, _ := os.Hostname()
+		if _, err := runSyncGit(dir, "commit", "--quiet", "-m", "Update profiles from "+hostname); err != nil {
+			return false, err
+		}
+	}
+
+	if _, err := runSyncGit(dir, "push", "--quiet", "-u", "origin", "HEAD"); err != nil {
+		return false, err
+	}
+	if _, err := runSyncGit(dir, "update-ref", syncedRef, "HEAD"); err != nil {
+		return false, err
+	}
+	return status != "", nil
+}
+
+// fetchSync updates the sync repository to the remote state, returning false when the remote is still empty
+func fetchSync(dir string) (bool, error) {
+	heads, err := runSyncGit(dir, "ls-remote", "--heads", "origin")
+	if err != nil {
+		return false, err
+	}
+	if heads == "" {
+		return false, nil
+	}
+
+	if _, err := runSyncGit(dir, "fetch", "--quiet", "origin"); err != nil {
+		return false, err
+	}
+
+	// The local config is the source of truth on this machine, so the clone simply follows the remote
+	branch, err := runSyncGit(dir, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return false, err
+	}
+	if _, err := runSyncGit(dir, "reset", "--quiet", "--hard", "origin/"+branch); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// readSyncSnapshot reads the profile store committed in the sync repository
+func readSyncSnapshot(dir string) (configFile, error) {
+	data, err := os.ReadFile(filepath.Join(dir, syncFileName))
+	if err != nil {
+		return configFile{}, err
+	}
+	return parseConfig(data)
+}
+
+// readSyncBase reads the profile store as of the last sync, which is empty before the first one
+func readSyncBase(dir string) (configFile, error) {
+	if _, err := runSyncGit(dir, "rev-parse", "--verify", "--quiet", syncedRef); err != nil {
+		return configFile{}, nil
+	}
+
+	data, err := runSyncGit(dir, "show", syncedRef+":"+syncFileName)
+	if err != nil {
+		return configFile{}, err
+	}
+	return parseConfig([]byte(data))
+}
+
+// pullSync merges the profile store in the sync repository into the local one, using resolve for conflicting edits
+func (cm *ConfigManager) pullSync(dir string, resolve conflictResolver) (bool, error) {
+	base, err := readSyncBase(dir)
+	if err != nil {
+		return false, err
+	}
+
+	fetched, err := fetchSync(dir)
+	if err != nil || !fetched {
+		return false, err
+	}
+
+	remote, err := readSyncSnapshot(dir)
+	if err != nil {
+		return false, err
+	}
+
+	merged, err := mergeSnapshots(base, cm.syncSnapshot(), remote, tr("in the sync repository"), resolve)
+	if err != nil {
+		return false, err
+	}
+	cm.backupBefore("sync-pull")
+	cm.restoreSnapshot(merged)
+	if _, err := runSyncGit(dir, "update-ref", syncedRef, "HEAD"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// newSyncCmd builds the sync command group
+func newSyncCmd(configManager *ConfigManager) *cobra.Command {
+	var syncCmd = &cobra.Command{
+		Use:   "sync",
+		Short: "Keep profiles in a private Git repository shared between machines",
+	}
+
+	resolveDir := func() string {
+		dir, err := syncDir()
+		if err != nil {
+			fmt.Fprintln(stdout, tr("Sync failed:"), err)
+			os.Exit(1)
+		}
+		return dir
+	}
+
+	requireSetup := func() string {
+		dir := resolveDir()
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+			fmt.Fprintln(stdout, tr("Sync is not set up; run 'git profile sync setup <git-url>' first."))
+			os.Exit(1)
+		}
+		return dir
+	}
+
+	var setupCmd = &cobra.Command{
+		Use:   "setup <git-url>",
+		Short: "Clone the private repository profiles are synced through",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			dir := resolveDir()
+			if err := setupSync(dir, args[0]); err != nil {
+				fmt.Fprintln(stdout, tr("Setup failed:"), err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(notices, tr("Sync set up in %s. Run 'git profile sync pull' to fetch existing profiles or 'git profile sync push' to upload yours.\n"), displayPath(dir))
+		},
+	}
+
+	var pushCmd = &cobra.Command{
+		Use:   "push",
+		Short: "Upload the local profiles to the sync repository",
+		Run: func(cmd *cobra.Command, args []string) {
+			changed, err := configManager.pushSync(requireSetup())
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Push failed:"), err)
+				os.Exit(1)
+			}
+			if !changed {
+				fmt.Fprintln(notices, tr("Profiles are already up to date."))
+				return
+			}
+			fmt.Fprintln(notices, tr("Profiles pushed."))
+		},
+	}
+
+	var pullCmd = &cobra.Command{
+		Use:   "pull",
+		Short: "Merge the profiles in the sync repository into the local ones",
+		Run: func(cmd *cobra.Command, args []string) {
+			pulled, err := configManager.pullSync(requireSetup(), promptConflictResolver)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Pull failed:"), err)
+				os.Exit(1)
+			}
+			if !pulled {
+				fmt.Fprintln(stdout, tr("The sync repository is empty; run 'git profile sync push' first."))
+				return
+			}
+			fmt.Fprintf(notices, tr("Profiles pulled: %d profiles.\n"), len(configManager.Profiles))
+		},
+	}
+
+	syncCmd.AddCommand(setupCmd, pushCmd, pullCmd)
+	return syncCmd
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSync tests carrying profiles between two machines through a shared repository
+func TestSync(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	t.Setenv("HOME", tmpDir)
+
+	remote := filepath.Join(tmpDir, "remote.git")
+	_, err = runGit("", "init", "--quiet", "--bare", remote)
+	assert.NoError(t, err)
+
+	laptopDir := filepath.Join(tmpDir, "laptop")
+	laptop := &ConfigManager{
+		ConfigPath: filepath.Join(tmpDir, "laptop.json"),
+		Profiles: map[string]Profile{
+			"work": {Name: "John Doe", Email: "john.doe@company.com"},
+		},
+		Repos: map[string]RegisteredRepo{"/home/john/api": {Profile: "work"}},
+	}
+	assert.NoError(t, setupSync(laptopDir, remote))
+	assert.Error(t, setupSync(laptopDir, remote))
+
+	// Pulling from an empty repository leaves the local profiles alone
+	pulled, err := laptop.pullSync(laptopDir, nil)
+	assert.NoError(t, err)
+	assert.False(t, pulled)
+
+	changed, err := laptop.pushSync(laptopDir)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	changed, err = laptop.pushSync(laptopDir)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	desktopDir := filepath.Join(tmpDir, "desktop")
+	desktop := &ConfigManager{ConfigPath: filepath.Join(tmpDir, "desktop.json"), Profiles: map[string]Profile{}}
+	assert.NoError(t, setupSync(desktopDir, remote))
+	pulled, err = desktop.pullSync(desktopDir, nil)
+	assert.NoError(t, err)
+	assert.True(t, pulled)
+	assert.Equal(t, "john.doe@company.com", desktop.Profiles["work"].Email)
+	assert.Empty(t, desktop.Repos)
+
+	// Changes made on the desktop reach the laptop
+	desktop.Profiles["personal"] = Profile{Name: "John Personal", Email: "john.personal@gmail.com"}
+	_, err = desktop.pushSync(desktopDir)
+	assert.NoError(t, err)
+
+	_, err = laptop.pullSync(laptopDir, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, laptop.Profiles, "personal")
+	assert.Contains(t, laptop.Repos, "/home/john/api")
+}
+
+// TestSyncConflicts tests merging profiles edited on both machines since the last sync
+func TestSyncConflicts(t *testing.T) {
+	work := Profile{Name: "John Doe", Email: "john.doe@company.com"}
+	base := map[string]Profile{
+		"work":     work,
+		"personal": {Name: "John Personal", Email: "john.personal@gmail.com"},
+		"old":      {Name: "John Old", Email: "john@old.com"},
+	}
+
+	localWork := work
+	localWork.Email = "john.doe@newcorp.com"
+	remoteWork := work
+	remoteWork.Name = "Johnny Doe"
+	remoteWork.Tags = []string{"work"}
+
+	local := map[string]Profile{"work": localWork, "personal": base["personal"], "old": base["old"]}
+	remote := map[string]Profile{
+		"work":     remoteWork,
+		"personal": {Name: "John Personal", Email: "jp@gmail.com"},
+		"oss":      {Name: "John Doe", Email: "john@oss.dev"},
+	}
+
+	var conflicts []string
+	resolve := func(conflict profileConflict) (*Profile, error) {
+		conflicts = append(conflicts, conflict.Name)
+		fields, err := fieldDifferences(*conflict.Local, *conflict.Remote)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"email", "name", "tags"}, fields)
+
+		merged, err := mergeFields(*conflict.Local, *conflict.Remote, []string{"name", "tags"})
+		return &merged, err
+	}
+
+	merged, err := mergeProfiles(base, local, remote, resolve)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"work"}, conflicts)
+
+	// Field-by-field resolution combines both edits
+	assert.Equal(t, "Johnny Doe", merged["work"].Name)
+	assert.Equal(t, "john.doe@newcorp.com", merged["work"].Email)
+	assert.Equal(t, []string{"work"}, merged["work"].Tags)
+
+	// One-sided changes, additions and deletions merge without asking
+	assert.Equal(t, "jp@gmail.com", merged["personal"].Email)
+	assert.Contains(t, merged, "oss")
+	assert.NotContains(t, merged, "old")
+
+	rules, conflict := mergeSection([]Rule(nil), []Rule{{Dir: "~/work/", Profile: "work"}}, []Rule{{Dir: "~/oss/", Profile: "oss"}})
+	assert.True(t, conflict)
+	assert.Equal(t, "work", rules[0].Profile)
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/manifoldco/promptui"
+)
+
+// profileConflict is a profile changed differently on this machine and in the sync repository; a nil side was deleted
+type profileConflict struct {
+	Name   string
+	Local  *Profile
+	Remote *Profile
+}
+
+// conflictResolver decides the outcome of a conflict, returning nil to delete the profile
+type conflictResolver func(conflict profileConflict) (*Profile, error)
+
+// sameProfile compares two profiles, ignoring when they were last applied
+func sameProfile(a *Profile, b *Profile) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	left, right := *a, *b
+	left.LastUsed, right.LastUsed = nil, nil
+	return reflect.DeepEqual(left, right)
+}
+
+// lookupProfile returns a pointer to a copy of the named profile, or nil when it doesn't exist
+func lookupProfile(profiles map[string]Profile, name string) *Profile {
+	if profile, exists := profiles[name]; exists {
+		return &profile
+	}
+	return nil
+}
+
+// mergeProfiles three-way merges the local and remote profiles against the last synced base
+func mergeProfiles(base, local, remote map[string]Profile, resolve conflictResolver) (map[string]Profile, error) {
+	names := make(map[string]bool)
+	for _, profiles := range []map[string]Profile{base, local, remote} {
+		for name := range profiles {
+			names[name] = true
+		}
+	}
+	var sorted []string
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	merged := make(map[string]Profile)
+	for _, name := range sorted {
+		baseProfile, localProfile, remoteProfile := lookupProfile(base, name), lookupProfile(local, name), lookupProfile(remote, name)
+
+		var result *Profile
+		switch {
+		case sameProfile(localProfile, remoteProfile), sameProfile(remoteProfile, baseProfile):
+			result = localProfile
+		case sameProfile(localProfile, baseProfile):
+			result = remoteProfile
+			if result != nil && localProfile != nil {
+				result.LastUsed = localProfile.LastUsed
+			}
+		default:
+			resolved, err := resolve(profileConflict{Name: name, Local: localProfile, Remote: remoteProfile})
+			if err != nil {
+				return nil, err
+			}
+			result = resolved
+		}
+
+		if result != nil {
+			merged[name] = *result
+		}
+	}
+	return merged, nil
+}
+
+// mergeSection three-way merges a non-profile section of the config, keeping the local side when both changed
+func mergeSection[T any](base, local, remote T) (T, bool) {
+	switch {
+	case reflect.DeepEqual(local, base):
+		return remote, false
+	case reflect.DeepEqual(remote, base), reflect.DeepEqual(local, remote):
+		return local, false
+	}
+	return local, true
+}
+
+// profileFields splits a profile into its top-level JSON fields
+func profileFields(profile Profile) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	return fields, json.Unmarshal(data, &fields)
+}
+
+// fieldDifferences lists the top-level fields that differ between two profiles
+func fieldDifferences(local Profile, remote Profile) ([]string, error) {
+	localFields, err := profileFields(local)
+	if err != nil {
+		return nil, err
+	}
+	remoteFields, err := profileFields(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	for field := range localFields {
+		if field != "last_used" && string(localFields[field]) != string(remoteFields[field]) {
+			fields = append(fields, field)
+		}
+	}
+	for field := range remoteFields {
+		if _, exists := localFields[field]; !exists && field != "last_used" {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// mergeFields builds a profile from local, replacing the fields in takeRemote with their remote values
+func mergeFields(local Profile, remote Profile, takeRemote []string) (Profile, error) {
+	localFields, err := profileFields(local)
+	if err != nil {
+		return Profile{}, err
+	}
+	remoteFields, err := profileFields(remote)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	for _, field := range takeRemote {
+		if value, exists := remoteFields[field]; exists {
+			localFields[field] = value
+		} else {
+			delete(localFields, field)
+		}
+	}
+
+	data, err := json.Marshal(localFields)
+	if err != nil {
+		return Profile{}, err
+	}
+	var merged Profile
+	return merged, json.Unmarshal(data, &merged)
+}
+
+// describeSide renders one side of a conflict for the resolution prompt
+func describeSide(profile *Profile) string {
+	if profile == nil {
+		return tr("deleted")
+	}
+	return fmt.Sprintf("%s <%s>", profile.Name, profile.Email)
+}
+
+// promptConflictResolver asks how to resolve each conflict of a sync pull
+func promptConflictResolver(conflict profileConflict) (*Profile, error) {
+	return promptConflict(conflict, tr("in the sync repository"), tr("run 'git profile sync pull' in a terminal to resolve it"))
+}
+
+// promptConflict asks how to resolve a conflict with the remote side, changed where other says: keep local, take
+// remote, or pick field by field; hint tells what to do instead when there is no terminal to ask on
+func promptConflict(conflict profileConflict, other string, hint string) (*Profile, error) {
+	if err := requireTerminal(hint); err != nil {
+		return nil, errorf("profile '%s' was edited on both sides: %w", conflict.Name, err)
+	}
+	fmt.Fprintf(stdout, tr("\n⚠️  Profile '%s' changed both here (%s) and %s (%s).\n"),
+		conflict.Name, describeSide(conflict.Local), other, describeSide(conflict.Remote))
+
+	options := []string{tr("Keep local"), tr("Take remote")}
+	if conflict.Local != nil && conflict.Remote != nil {
+		options = append(options, tr("Choose field by field"))
+	}
+	prompt := promptui.Select{
+		Label: fmt.Sprintf(tr("Resolve '%s'"), conflict.Name),
+		Items: options,
+	}
+	choice, _, err := prompt.Run()
+	if err != nil {
+		return nil, errorf("conflict resolution cancelled")
+	}
+
+	switch choice {
+	case 0:
+		return conflict.Local, nil
+	case 1:
+		return conflict.Remote, nil
+	}
+
+	fields, err := fieldDifferences(*conflict.Local, *conflict.Remote)
+	if err != nil {
+		return nil, err
+	}
+	localFields, _ := profileFields(*conflict.Local)
+	remoteFields, _ := profileFields(*conflict.Remote)
+
+	var takeRemote []string
+	for _, field := range fields {
+		fieldPrompt := promptui.Select{
+			Label: fmt.Sprintf(tr("Field '%s'"), field),
+			Items: []string{fmt.Sprintf(tr("local: %s"), localFields[field]), fmt.Sprintf(tr("remote: %s"), remoteFields[field])},
+		}
+		fieldChoice, _, err := fieldPrompt.Run()
+		if err != nil {
+			return nil, errorf("conflict resolution cancelled")
+		}
+		if fieldChoice == 1 {
+			takeRemote = append(takeRemote, field)
+		}
+	}
+
+	merged, err := mergeFields(*conflict.Local, *conflict.Remote, takeRemote)
+	if err != nil {
+		return nil, err
+	}
+	return &merged, nil
+}
+
+// mergeSnapshots three-way merges the local config with the remote one, changed where other says, warning about
+// sections kept locally
+func mergeSnapshots(base, local, remote configFile, other string, resolve conflictResolver) (configFile, error) {
+	profiles, err := mergeProfiles(base.Profiles, local.Profiles, remote.Profiles, resolve)
+	if err != nil {
+		return configFile{}, err
+	}
+
+	merged := configFile{Profiles: profiles}
+	var conflicts []string
+	var conflict bool
+	if merged.Rules, conflict = mergeSection(base.Rules, local.Rules, remote.Rules); conflict {
+		conflicts = append(conflicts, "rules")
+	}
+	if merged.Templates, conflict = mergeSection(base.Templates, local.Templates, remote.Templates); conflict {
+		conflicts = append(conflicts, "templates")
+	}
+	if merged.Sources, conflict = mergeSection(base.Sources, local.Sources, remote.Sources); conflict {
+		conflicts = append(conflicts, "sources")
+	}
+	for _, section := range conflicts {
+		warnf("%s changed both here and %s; kept the local version.", section, other)
+	}
+	return merged, nil
+}
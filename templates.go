@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// templateFromProfile strips the personal and bookkeeping fields from a profile so it can be shared as a template
+func templateFromProfile(profile Profile) Profile {
+	template := profile
+	template.Name, template.Email = "", ""
+	template.Secrets = nil
+	template.Created, template.Updated, template.LastUsed = nil, nil, nil
+	return template
+}
+
+// profileFromTemplate returns a fresh profile pre-filled from a saved template
+func (cm *ConfigManager) profileFromTemplate(name string) (Profile, error) {
+	template, exists := cm.Templates[name]
+	if !exists {
+		return Profile{}, errorf("template '%s' not found", name)
+	}
+
+	profile := templateFromProfile(template)
+	profile.Remotes = append([]string(nil), template.Remotes...)
+	profile.Tags = append([]string(nil), template.Tags...)
+	profile.URLRewrites = append([]URLRewrite(nil), template.URLRewrites...)
+	return profile, nil
+}
+
+// templateProfileInput asks only for the name and email, keeping every other field from the template
+func templateProfileInput(template Profile) Profile {
+	reader := bufio.NewReader(os.Stdin)
+	profile := template
+
+	fmt.Fprint(stdout, tr("\nEnter name: "))
+	name, _ := reader.ReadString('\n')
+	profile.Name = strings.TrimSpace(name)
+
+	if template.Email != "" {
+		fmt.Fprintf(stdout, tr("Enter email [template: %s, press Enter to keep]: "), template.Email)
+	} else {
+		fmt.Fprint(stdout, tr("Enter email: "))
+	}
+	email, _ := reader.ReadString('\n')
+	if email = strings.TrimSpace(email); email != "" {
+		profile.Email = email
+	}
+
+	return profile
+}
+
+// newTemplateCmd builds the template command group
+func newTemplateCmd(configManager *ConfigManager) *cobra.Command {
+	var templateCmd = &cobra.Command{
+		Use:   "template",
+		Short: "Manage templates that pre-fill new profiles",
+	}
+
+	var listCmd = &cobra.Command{
+		Use:   "ls",
+		Short: "List profile templates",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(configManager.Templates) == 0 {
+				fmt.Fprintln(stdout, tr("No templates found."))
+				return
+			}
+
+			var names []string
+			for name := range configManager.Templates {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				template := configManager.Templates[name]
+				fmt.Fprintf(stdout, tr("📋 Template: %s\n"), name)
+				if template.Email != "" {
+					fmt.Fprintf(stdout, tr("  📧 Email: %s\n"), template.Email)
+				}
+				if template.Signing.Key != "" {
+					fmt.Fprintf(stdout, tr("  🔑 Signing: %s\n"), template.SigningFormat())
+				}
+				if template.Host != "" {
+					fmt.Fprintf(stdout, tr("  🌐 Host: %s\n"), strings.TrimSuffix(template.Host+"/"+template.Workspace, "/"))
+				}
+				if len(template.Remotes) > 0 {
+					fmt.Fprintf(stdout, tr("  🔗 Remotes: %s\n"), strings.Join(template.Remotes, ", "))
+				}
+				fmt.Fprintln(stdout)
+			}
+		},
+	}
+
+	var addCmd = &cobra.Command{
+		Use:   "add <template>",
+		Short: "Create a template interactively",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if _, exists := configManager.Templates[args[0]]; exists {
+				fmt.Fprintf(stdout, tr("Template '%s' already exists.\n"), args[0])
+				os.Exit(1)
+			}
+
+			fmt.Fprintln(notices, tr("Leave name and email empty to ask for them when the template is used."))
+			template := templateFromProfile(interactiveProfileInput(nil))
+			if configManager.Templates == nil {
+				configManager.Templates = make(map[string]Profile)
+			}
+			configManager.Templates[args[0]] = template
+			configManager.save()
+			fmt.Fprintf(notices, tr("Template '%s' added successfully!\n"), args[0])
+		},
+	}
+
+	var from string
+	var saveCmd = &cobra.Command{
+		Use:   "save <template> --from <profile>",
+		Short: "Save an existing profile's conventions as a template",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			profile, exists := configManager.Profiles[from]
+			if !exists {
+				fmt.Fprintf(stdout, tr("Profile '%s' not found.\n"), from)
+				os.Exit(1)
+			}
+
+			if configManager.Templates == nil {
+				configManager.Templates = make(map[string]Profile)
+			}
+			configManager.Templates[args[0]] = templateFromProfile(profile)
+			configManager.save()
+			fmt.Fprintf(notices, tr("Template '%s' saved from profile '%s'.\n"), args[0], from)
+		},
+	}
+	saveCmd.Flags().StringVar(&from, "from", "", "Profile to copy the settings from")
+	saveCmd.MarkFlagRequired("from")
+
+	var removeCmd = &cobra.Command{
+		Use:   "rm <template>",
+		Short: "Remove a template",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if _, exists := configManager.Templates[args[0]]; !exists {
+				fmt.Fprintf(stdout, tr("Template '%s' not found.\n"), args[0])
+				os.Exit(1)
+			}
+
+			delete(configManager.Templates, args[0])
+			configManager.save()
+			fmt.Fprintf(notices, tr("Template '%s' removed.\n"), args[0])
+		},
+	}
+
+	templateCmd.AddCommand(listCmd, addCmd, saveCmd, removeCmd)
+	return templateCmd
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestProfileTemplates tests saving a profile as a template and creating profiles from it
+func TestProfileTemplates(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	now := time.Now()
+	work := Profile{Name: "John Doe", Email: "john.doe@company.com", Remotes: []string{"github.com/mycorp/*"}, Created: &now}
+	work.Signing.Key = "~/.ssh/id_ed25519.pub"
+	work.Signing.Commits = true
+	work.Secrets = map[string]string{"github-token": "keyring:work/github-token"}
+
+	template := templateFromProfile(work)
+	assert.Empty(t, template.Name)
+	assert.Empty(t, template.Email)
+	assert.Nil(t, template.Secrets)
+	assert.Nil(t, template.Created)
+	assert.True(t, template.Signing.Commits)
+
+	cm := &ConfigManager{
+		ConfigPath: filepath.Join(tmpDir, ".git-profiles-test.json"),
+		Profiles:   map[string]Profile{"work": work},
+		Templates:  map[string]Profile{"corp": template},
+	}
+	cm.save()
+
+	loaded := &ConfigManager{ConfigPath: cm.ConfigPath}
+	loaded.load()
+	profile, err := loaded.profileFromTemplate("corp")
+	assert.NoError(t, err)
+	assert.Equal(t, "~/.ssh/id_ed25519.pub", profile.Signing.Key)
+	assert.Equal(t, []string{"github.com/mycorp/*"}, profile.Remotes)
+
+	// Profiles created from a template don't share its lists
+	profile.Remotes[0] = "github.com/other/*"
+	assert.Equal(t, "github.com/mycorp/*", loaded.Templates["corp"].Remotes[0])
+
+	_, err = loaded.profileFromTemplate("missing")
+	assert.Error(t, err)
+}
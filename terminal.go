@@ -0,0 +1,25 @@
+package main
+
+import (
+	"os"
+
+	"github.com/mattn/go-isatty"
+)
+
+// isTerminal reports whether stdin and stdout are both attached to a terminal, so prompts can be shown
+var isTerminal = func() bool {
+	for _, file := range []*os.File{os.Stdin, os.Stdout} {
+		if !isatty.IsTerminal(file.Fd()) && !isatty.IsCygwinTerminal(file.Fd()) {
+			return false
+		}
+	}
+	return true
+}
+
+// requireTerminal fails fast when a prompt can't be shown, pointing at the non-interactive alternative
+func requireTerminal(hint string) error {
+	if isTerminal() {
+		return nil
+	}
+	return errorf("not running in a terminal, so there is nothing to prompt on; %s", hint)
+}
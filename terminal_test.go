@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNonInteractive tests failing fast without a terminal and the flag-driven alternatives
+func TestNonInteractive(t *testing.T) {
+	defer func(original func() bool) { isTerminal = original }(isTerminal)
+	isTerminal = func() bool { return false }
+
+	err := requireTerminal("pass --yes")
+	assert.ErrorContains(t, err, "not running in a terminal")
+	assert.ErrorContains(t, err, "pass --yes")
+
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	t.Setenv("HOME", tmpDir)
+
+	importPath := filepath.Join(tmpDir, "import.json")
+	data, err := json.Marshal(map[string]Profile{"oss": {Name: "John Doe", Email: "john@oss.dev"}})
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(importPath, data, 0644))
+
+	cm := &ConfigManager{
+		ConfigPath: filepath.Join(tmpDir, ".git-profiles-test.json"),
+		Profiles:   map[string]Profile{"work": {Name: "John Doe", Email: "john.doe@company.com"}},
+	}
+	assert.ErrorContains(t, cm.Import(importPath, importOptions{}), "--strategy")
+	assert.ErrorContains(t, cm.Import(importPath, importOptions{Strategy: "overwrite"}), "unknown import strategy")
+
+	assert.NoError(t, cm.Import(importPath, importOptions{Strategy: importMerge}))
+	assert.Len(t, cm.Profiles, 2)
+	assert.NoError(t, cm.Import(importPath, importOptions{Strategy: importReplace}))
+	assert.Len(t, cm.Profiles, 1)
+	assert.Contains(t, cm.Profiles, "oss")
+
+	_, err = promptConflictResolver(profileConflict{Name: "work"})
+	assert.ErrorContains(t, err, "not running in a terminal")
+}
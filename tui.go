@@ -0,0 +1,343 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+// tuiView is a screen of the dashboard
+type tuiView int
+
+const (
+	tuiProfilesView tuiView = iota
+	tuiRulesView
+)
+
+// tuiIdentity is the effective identity of the repository the dashboard was opened in
+type tuiIdentity struct {
+	InRepo   bool
+	Name     string
+	Email    string
+	Profile  string
+	Expected string
+}
+
+// tuiEditedMsg reports the end of an interactive edit started from the dashboard
+type tuiEditedMsg struct {
+	name    string
+	profile Profile
+}
+
+// tuiModel is the Bubble Tea model of the dashboard
+type tuiModel struct {
+	cm            *ConfigManager
+	dir           string
+	names         []string
+	cursor        int
+	view          tuiView
+	confirmRemove bool
+	status        string
+	identity      tuiIdentity
+}
+
+var (
+	tuiTitleStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	tuiSelectedStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("10"))
+	tuiDimStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	tuiPanelStyle    = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+)
+
+// readIdentity inspects the effective identity of the repository at dir
+func readIdentity(cm *ConfigManager, dir string) tuiIdentity {
+	if repoTopLevel(dir) == "" {
+		return tuiIdentity{}
+	}
+
+	identity := tuiIdentity{InRepo: true}
+	identity.Name, _ = gitConfigGet(dir, "user.name")
+	identity.Email, _ = gitConfigGet(dir, "user.email")
+	identity.Profile, _ = cm.appliedProfile(dir)
+	identity.Expected, _ = cm.expectedProfile(dir)
+	return identity
+}
+
+// newTUIModel builds the dashboard for the repository at dir
+func newTUIModel(cm *ConfigManager, dir string) tuiModel {
+	m := tuiModel{cm: cm, dir: dir}
+	m.refresh()
+	return m
+}
+
+// refresh reloads the profile names and the repository identity, keeping the cursor in range
+func (m *tuiModel) refresh() {
+	m.names = m.cm.visibleProfiles()
+
+	if m.cursor >= len(m.names) {
+		m.cursor = len(m.names) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	m.identity = readIdentity(m.cm, m.dir)
+}
+
+// selected returns the profile under the cursor
+func (m tuiModel) selected() (string, bool) {
+	if len(m.names) == 0 {
+		return "", false
+	}
+	return m.names[m.cursor], true
+}
+
+func (m tuiModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tuiEditedMsg:
+		m.cm.backupBefore("edit")
+		now := time.Now()
+		msg.profile.Updated = &now
+		m.cm.Profiles[msg.name] = msg.profile
+		m.cm.save()
+		m.status = fmt.Sprintf(tr("Profile '%s' updated."), msg.name)
+		m.refresh()
+		return m, nil
+
+	case tea.KeyMsg:
+		// Removal waits for an explicit yes
+		if m.confirmRemove {
+			m.confirmRemove = false
+			if name, ok := m.selected(); ok && msg.String() == "y" {
+				m.status = m.remove(name)
+				m.refresh()
+			} else {
+				m.status = tr("Removal cancelled.")
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return m, tea.Quit
+		case "tab":
+			if m.view == tuiProfilesView {
+				m.view = tuiRulesView
+			} else {
+				m.view = tuiProfilesView
+			}
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.names)-1 {
+				m.cursor++
+			}
+		}
+
+		if m.view != tuiProfilesView {
+			return m, nil
+		}
+		name, ok := m.selected()
+		if !ok {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "enter", "a":
+			m.status = m.apply(name)
+			m.refresh()
+		case "e":
+			if source, shared := m.cm.sourceOf(name); shared {
+				m.status = fmt.Sprintf(tr("Profile '%s' comes from %s; edit it with 'git profile edit' to save a local override."), name, source)
+				return m, nil
+			}
+			if err := m.cm.checkUnlocked("edit", false, name); err != nil {
+				m.status = err.Error()
+				return m, nil
+			}
+			edit := &profileEditCommand{profile: m.cm.Profiles[name]}
+			return m, tea.Exec(edit, func(err error) tea.Msg {
+				return tuiEditedMsg{name: name, profile: edit.profile}
+			})
+		case "d", "x":
+			m.confirmRemove = true
+			m.status = fmt.Sprintf(tr("Remove profile '%s'? (y/N)"), name)
+		}
+	}
+
+	return m, nil
+}
+
+// apply applies a profile to the dashboard's repository, returning the status line
+func (m tuiModel) apply(name string) string {
+	if !m.identity.InRepo {
+		return tr("Not inside a Git repository.")
+	}
+
+	profile, err := resolveProfile(m.cm.Profiles[name])
+	if err != nil {
+		return fmt.Sprintf(tr("Error applying profile: %v"), err)
+	}
+	if err := applyProfile(m.dir, name, profile); err != nil {
+		return fmt.Sprintf(tr("Error applying profile: %v"), err)
+	}
+	m.cm.registerRepo(m.dir, name)
+	m.cm.recordHistory("apply", name, m.dir, scopeLocal)
+	m.cm.markUsed(name)
+	return fmt.Sprintf(tr("Profile '%s' applied."), name)
+}
+
+// remove deletes a profile, returning the status line
+func (m tuiModel) remove(name string) string {
+	if source, shared := m.cm.sourceOf(name); shared {
+		return fmt.Sprintf(tr("Profile '%s' comes from %s and can't be removed locally."), name, source)
+	}
+	if err := m.cm.checkUnlocked("remove", false, name); err != nil {
+		return err.Error()
+	}
+
+	m.cm.backupBefore("rm")
+	m.cm.removeProfileSecrets(name)
+	delete(m.cm.Profiles, name)
+	m.cm.save()
+	return fmt.Sprintf(tr("Profile '%s' removed."), name)
+}
+
+func (m tuiModel) View() string {
+	var list strings.Builder
+	if m.view == tuiProfilesView {
+		list.WriteString(tuiTitleStyle.Render(tr("Profiles")) + tuiDimStyle.Render(tr("  rules ⇥")) + "\n\n")
+		if len(m.names) == 0 {
+			list.WriteString(tuiDimStyle.Render(tr("No profiles. Use 'git profile add'.")) + "\n")
+		}
+		for i, name := range m.names {
+			line := fmt.Sprintf("  %s", name)
+			if name == m.identity.Profile {
+				line += " ●"
+			}
+			if i == m.cursor {
+				line = tuiSelectedStyle.Render("› " + strings.TrimPrefix(line, "  "))
+			}
+			list.WriteString(line + "\n")
+		}
+	} else {
+		list.WriteString(tuiDimStyle.Render(tr("profiles ⇥  ")) + tuiTitleStyle.Render(tr("Rules")) + "\n\n")
+		if len(m.cm.Rules) == 0 {
+			list.WriteString(tuiDimStyle.Render(tr("No rules. Use 'git profile rules add'.")) + "\n")
+		}
+		matched, found := m.cm.matchRule(m.dir)
+		for _, rule := range m.cm.Rules {
+			line := fmt.Sprintf("  %s → %s", rule.Target(), rule.Profile)
+			if found && rule == matched {
+				line = tuiSelectedStyle.Render("› " + strings.TrimPrefix(line, "  ") + tr(" (matches here)"))
+			}
+			list.WriteString(line + "\n")
+		}
+	}
+
+	panels := lipgloss.JoinHorizontal(lipgloss.Top,
+		tuiPanelStyle.Render(strings.TrimRight(list.String(), "\n")),
+		tuiPanelStyle.Render(m.preview()),
+	)
+
+	help := tr("↑/↓ move • enter apply • e edit • d remove • tab rules • q quit")
+	if m.view == tuiRulesView {
+		help = tr("tab profiles • q quit")
+	}
+
+	view := panels + "\n"
+	if m.status != "" {
+		view += m.status + "\n"
+	}
+	return view + tuiDimStyle.Render(help) + "\n"
+}
+
+// preview renders the repository's effective identity and the selected profile
+func (m tuiModel) preview() string {
+	var sb strings.Builder
+	sb.WriteString(tuiTitleStyle.Render(tr("This repository")) + "\n")
+	switch {
+	case !m.identity.InRepo:
+		sb.WriteString(tuiDimStyle.Render(tr("not a Git repository")) + "\n")
+	case m.identity.Email == "":
+		sb.WriteString(tr("no identity configured\n"))
+	default:
+		sb.WriteString(fmt.Sprintf("%s <%s>\n", m.identity.Name, m.identity.Email))
+		profile := m.identity.Profile
+		if profile == "" {
+			profile = tr("none")
+		}
+		sb.WriteString(fmt.Sprintf(tr("profile: %s\n"), profile))
+	}
+	if m.identity.Expected != "" && m.identity.Expected != m.identity.Profile {
+		sb.WriteString(fmt.Sprintf(tr("⚠️  expected profile: %s\n"), m.identity.Expected))
+	}
+
+	name, ok := m.selected()
+	if !ok || m.view != tuiProfilesView {
+		return strings.TrimRight(sb.String(), "\n")
+	}
+
+	profile := m.cm.Profiles[name]
+	sb.WriteString("\n" + tuiTitleStyle.Render(name) + "\n")
+	sb.WriteString(fmt.Sprintf("%s <%s>\n", profile.Name, profile.Email))
+	if profile.Signing.Key != "" {
+		sb.WriteString(fmt.Sprintf(tr("signing: %s (%s)\n"), profile.Signing.Key, profile.SigningFormat()))
+	}
+	if profile.SSH.Key != "" {
+		sb.WriteString(fmt.Sprintf(tr("ssh key: %s\n"), profile.SSH.Key))
+	}
+	if len(profile.Tags) > 0 {
+		sb.WriteString(fmt.Sprintf(tr("tags: %s\n"), strings.Join(profile.Tags, ", ")))
+	}
+	if source, shared := m.cm.sourceOf(name); shared {
+		sb.WriteString(tuiDimStyle.Render(fmt.Sprintf(tr("shared: %s"), source)) + "\n")
+	}
+	sb.WriteString(tuiDimStyle.Render(fmt.Sprintf(tr("last used: %s"), formatTime(profile.LastUsed))) + "\n")
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// profileEditCommand runs the interactive profile editor while the dashboard gives up the terminal
+type profileEditCommand struct {
+	profile Profile
+}
+
+func (c *profileEditCommand) Run() error {
+	c.profile = interactiveProfileInput(&c.profile)
+	return nil
+}
+
+func (c *profileEditCommand) SetStdin(io.Reader)  {}
+func (c *profileEditCommand) SetStdout(io.Writer) {}
+func (c *profileEditCommand) SetStderr(io.Writer) {}
+
+// newTUICmd builds the tui command
+func newTUICmd(configManager *ConfigManager) *cobra.Command {
+	var tuiCmd = &cobra.Command{
+		Use:   "tui",
+		Short: "Open a full-screen dashboard of profiles and rules",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := requireTerminal(tr("use 'git profile ls' and the other commands instead")); err != nil {
+				fmt.Fprintln(stdout, tr("TUI failed:"), err)
+				os.Exit(1)
+			}
+			program := tea.NewProgram(newTUIModel(configManager, "."), tea.WithAltScreen())
+			if _, err := program.Run(); err != nil {
+				fmt.Fprintln(stdout, tr("TUI failed:"), err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	return tuiCmd
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTUI tests navigating the dashboard and applying and removing profiles from it
+func TestTUI(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	t.Setenv("HOME", tmpDir)
+
+	repoDir := initTestRepo(t)
+	cm := &ConfigManager{
+		ConfigPath: filepath.Join(tmpDir, ".git-profiles-test.json"),
+		Profiles: map[string]Profile{
+			"personal": {Name: "John Personal", Email: "john.personal@gmail.com"},
+			"work":     {Name: "John Doe", Email: "john.doe@company.com"},
+		},
+		Rules: []Rule{{Dir: repoDir, Profile: "work"}},
+	}
+
+	press := func(m tea.Model, key string) tea.Model {
+		var msg tea.KeyMsg
+		switch key {
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		m, _ = m.Update(msg)
+		return m
+	}
+
+	var m tea.Model = newTUIModel(cm, repoDir)
+	assert.Contains(t, m.View(), "personal")
+	assert.Contains(t, m.View(), "expected profile: work")
+
+	// Apply the second profile and see the repository identity update
+	m = press(press(m, "down"), "enter")
+	email, err := gitConfigGet(repoDir, "user.email")
+	assert.NoError(t, err)
+	assert.Equal(t, "john.doe@company.com", email)
+	assert.Contains(t, m.View(), "John Doe <john.doe@company.com>")
+	assert.NotContains(t, m.View(), "expected profile")
+
+	// The rules view shows the rule matching this repository
+	m = press(m, "tab")
+	assert.Contains(t, m.View(), "(matches here)")
+	m = press(m, "tab")
+
+	// Removal needs confirmation
+	m = press(press(m, "d"), "n")
+	assert.Contains(t, cm.Profiles, "work")
+	m = press(press(m, "d"), "y")
+	assert.NotContains(t, cm.Profiles, "work")
+	assert.Contains(t, m.View(), "Profile 'work' removed.")
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	assert.NotNil(t, cmd)
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// updateRepository is the GitHub repository releases are published to
+const updateRepository = "lvluu/git-profile"
+
+// updateCheckInterval is how long a release check is trusted before GitHub is asked again
+const updateCheckInterval = 24 * time.Hour
+
+// updateCheckWait bounds how long a command waits for a release check before giving up until next time
+var updateCheckWait = 500 * time.Millisecond
+
+// noUpdateCheck disables the release check, set by --no-update-check or GIT_PROFILE_NO_UPDATE_CHECK
+var noUpdateCheck = os.Getenv("GIT_PROFILE_NO_UPDATE_CHECK") != ""
+
+// updateCache records the last release check
+type updateCache struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    string    `json:"latest,omitempty"`
+}
+
+// updateCachePath returns the file caching the last release check
+func updateCachePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", "git-profile", "update-check.json"), nil
+}
+
+// readUpdateCache loads the last release check, returning an empty one when there is none
+func readUpdateCache(path string) updateCache {
+	var cache updateCache
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &cache)
+	}
+	return cache
+}
+
+// writeUpdateCache saves a release check
+func writeUpdateCache(path string, cache updateCache) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// fetchLatestRelease asks GitHub for the tag of the latest release
+func fetchLatestRelease() (string, error) {
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(githubAPIURL + "/repos/" + updateRepository + "/releases/latest")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errorf("GitHub API returned %s", resp.Status)
+	}
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", errorf("decoding GitHub response: %w", err)
+	}
+	return release.TagName, nil
+}
+
+// parseVersion splits a version such as "v1.4.2" or "1.4.2-rc1" into its numeric parts
+func parseVersion(v string) ([]int, bool) {
+	v, _, _ = strings.Cut(strings.TrimPrefix(v, "v"), "-")
+	var parts []int
+	for _, field := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, true
+}
+
+// isNewerVersion reports whether latest is a later release than current
+func isNewerVersion(current string, latest string) bool {
+	have, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	want, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+
+	for i := 0; i < len(have) || i < len(want); i++ {
+		var a, b int
+		if i < len(have) {
+			a = have[i]
+		}
+		if i < len(want) {
+			b = want[i]
+		}
+		if a != b {
+			return b > a
+		}
+	}
+	return false
+}
+
+// latestRelease returns the latest known release, refreshing a stale cache in the background for a bounded time.
+// The attempt is recorded before asking GitHub, so a slow network only costs the wait once per interval.
+func latestRelease(path string, fetch func() (string, error)) string {
+	cache := readUpdateCache(path)
+	if time.Since(cache.CheckedAt) < updateCheckInterval {
+		return cache.Latest
+	}
+
+	cache.CheckedAt = time.Now()
+	if err := writeUpdateCache(path, cache); err != nil {
+		debugf("update check: %v", err)
+		return cache.Latest
+	}
+
+	done := make(chan string, 1)
+	go func() {
+		latest, err := fetch()
+		if err != nil {
+			debugf("update check: %v", err)
+			done <- cache.Latest
+			return
+		}
+		if err := writeUpdateCache(path, updateCache{CheckedAt: cache.CheckedAt, Latest: latest}); err != nil {
+			debugf("update check: %v", err)
+		}
+		done <- latest
+	}()
+
+	select {
+	case latest := <-done:
+		return latest
+	case <-time.After(updateCheckWait):
+		return cache.Latest
+	}
+}
+
+// printUpdateHint prints a one-line hint on stderr when a newer release exists, unless disabled or not in a terminal
+func printUpdateHint() {
+	if noUpdateCheck || outputMode.Quiet || !isTerminal() {
+		return
+	}
+	if _, ok := parseVersion(version); !ok {
+		return
+	}
+	path, err := updateCachePath()
+	if err != nil {
+		return
+	}
+
+	if latest := latestRelease(path, fetchLatestRelease); isNewerVersion(version, latest) {
+		fmt.Fprintf(stderr, tr("💡 git-profile %s is available (you have %s): go install github.com/%s@latest\n"), latest, version, updateRepository)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestVersionComparison tests ordering release versions
+func TestVersionComparison(t *testing.T) {
+	assert.True(t, isNewerVersion("v1.2.3", "v1.10.0"))
+	assert.True(t, isNewerVersion("1.2", "v1.2.1"))
+	assert.False(t, isNewerVersion("v1.2.3", "v1.2.3"))
+	assert.False(t, isNewerVersion("v2.0.0", "v1.9.9"))
+	assert.False(t, isNewerVersion("dev", "v1.0.0"))
+	assert.False(t, isNewerVersion("v1.0.0", ""))
+}
+
+// TestLatestRelease tests that release checks are cached and rate limited
+func TestLatestRelease(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	path := filepath.Join(tmpDir, "update-check.json")
+
+	calls := 0
+	fetch := func() (string, error) {
+		calls++
+		return "v1.5.0", nil
+	}
+	assert.Equal(t, "v1.5.0", latestRelease(path, fetch))
+	assert.Equal(t, "v1.5.0", latestRelease(path, fetch))
+	assert.Equal(t, 1, calls)
+
+	// A failed check keeps the last known release and isn't retried within the interval
+	assert.NoError(t, writeUpdateCache(path, updateCache{CheckedAt: time.Now().Add(-2 * updateCheckInterval), Latest: "v1.5.0"}))
+	failing := func() (string, error) {
+		calls++
+		return "", errors.New("offline")
+	}
+	assert.Equal(t, "v1.5.0", latestRelease(path, failing))
+	assert.Equal(t, "v1.5.0", latestRelease(path, failing))
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// githubAPIURL is the base URL of the GitHub REST API
+var githubAPIURL = "https://api.github.com"
+
+// githubAPIBase returns the REST API base URL for a GitHub host, the GitHub Enterprise Server one for hosts other than
+// github.com
+func githubAPIBase(host string) string {
+	if host == "" || strings.EqualFold(host, "github.com") {
+		return githubAPIURL
+	}
+	return "https://" + strings.TrimSuffix(host, "/") + "/api/v3"
+}
+
+// githubTokenSecret is the profile secret consulted for a GitHub token
+const githubTokenSecret = "github-token"
+
+// githubEmail is one entry of the GitHub /user/emails response
+type githubEmail struct {
+	Email    string `json:"email"`
+	Verified bool   `json:"verified"`
+	Primary  bool   `json:"primary"`
+}
+
+// fetchGitHubEmails lists the email addresses of the account owning token through the API at apiURL
+func fetchGitHubEmails(apiURL string, token string) ([]githubEmail, error) {
+	req, err := http.NewRequest(http.MethodGet, apiURL+"/user/emails", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return nil, errorf("GitHub rejected the token")
+	case http.StatusNotFound, http.StatusForbidden:
+		return nil, errorf("the token cannot read account emails (it needs the user:email scope)")
+	default:
+		return nil, errorf("GitHub API returned %s", resp.Status)
+	}
+
+	var emails []githubEmail
+	if err := json.NewDecoder(resp.Body).Decode(&emails); err != nil {
+		return nil, errorf("decoding GitHub response: %w", err)
+	}
+	return emails, nil
+}
+
+// checkGitHubEmail verifies that email is one of the account's verified emails
+func checkGitHubEmail(emails []githubEmail, email string) error {
+	for _, candidate := range emails {
+		if !strings.EqualFold(candidate.Email, email) {
+			continue
+		}
+		if !candidate.Verified {
+			return errorf("<%s> is on the GitHub account but not verified, so commits won't be attributed", email)
+		}
+		return nil
+	}
+
+	return errorf("<%s> is not an email of the GitHub account, so commits won't be attributed to it", email)
+}
+
+// githubToken resolves the token used to verify a profile: flag, profile secret, then environment, so an ambient token
+// doesn't check every profile against the same account
+func (cm *ConfigManager) githubToken(profileName string, flagToken string) (string, error) {
+	if flagToken != "" {
+		return flagToken, nil
+	}
+	if _, exists := cm.Profiles[profileName].Secrets[githubTokenSecret]; exists {
+		return cm.profileSecret(profileName, githubTokenSecret)
+	}
+
+	for _, env := range []string{"GITHUB_TOKEN", "GH_TOKEN"} {
+		if token := os.Getenv(env); token != "" {
+			return token, nil
+		}
+	}
+	return "", errorf("no GitHub token: pass --token, run 'git profile secret set %s %s', or set GITHUB_TOKEN", profileName, githubTokenSecret)
+}
+
+// newVerifyCmd builds the verify command
+func newVerifyCmd(configManager *ConfigManager) *cobra.Command {
+	var github bool
+	var token string
+
+	var verifyCmd = &cobra.Command{
+		Use:   "verify <profile>",
+		Short: "Verify a profile email against a hosting account",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := args[0]
+			saved, exists := configManager.Profiles[name]
+			if !exists {
+				fmt.Fprintf(stdout, tr("Profile '%s' not found.\n"), name)
+				os.Exit(1)
+			}
+			profile, err := resolveProfile(saved)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Verify failed:"), err)
+				os.Exit(1)
+			}
+			if !github {
+				fmt.Fprintln(stdout, tr("Choose an account to verify against, e.g. --github."))
+				os.Exit(1)
+			}
+
+			githubToken, err := configManager.githubToken(name, token)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Verify failed:"), err)
+				os.Exit(1)
+			}
+
+			emails, err := fetchGitHubEmails(githubAPIBase(profile.GitHub.Host), githubToken)
+			if err != nil {
+				fmt.Fprintln(stdout, tr("Verify failed:"), err)
+				os.Exit(1)
+			}
+
+			if err := checkGitHubEmail(emails, profile.Email); err != nil {
+				fmt.Fprintf(stdout, "❌ %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(notices, tr("✅ <%s> is a verified email of the GitHub account.\n"), profile.Email)
+		},
+	}
+	verifyCmd.Flags().BoolVar(&github, "github", false, "Verify against the GitHub account owning the token")
+	verifyCmd.Flags().StringVar(&token, "token", "", "GitHub token (defaults to the profile's github-token secret, then GITHUB_TOKEN)")
+
+	return verifyCmd
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando/go-keyring"
+)
+
+// TestVerifyGitHubEmail tests checking a profile email against the GitHub account emails
+func TestVerifyGitHubEmail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user/emails" || r.Header.Get("Authorization") != "Bearer good-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[
+			{"email": "john.doe@company.com", "verified": true, "primary": true},
+			{"email": "john@old-company.com", "verified": false, "primary": false}
+		]`))
+	}))
+	defer server.Close()
+
+	emails, err := fetchGitHubEmails(server.URL, "good-token")
+	assert.NoError(t, err)
+	assert.Len(t, emails, 2)
+
+	assert.NoError(t, checkGitHubEmail(emails, "John.Doe@company.com"))
+	assert.ErrorContains(t, checkGitHubEmail(emails, "john@old-company.com"), "not verified")
+	assert.ErrorContains(t, checkGitHubEmail(emails, "john.personal@gmail.com"), "not an email")
+
+	_, err = fetchGitHubEmails(server.URL, "bad-token")
+	assert.ErrorContains(t, err, "rejected")
+
+	// GitHub Enterprise profiles are checked against their own server
+	assert.Equal(t, "https://api.github.com", githubAPIBase(""))
+	assert.Equal(t, "https://api.github.com", githubAPIBase("GitHub.com"))
+	assert.Equal(t, "https://github.acme.com/api/v3", githubAPIBase("github.acme.com"))
+}
+
+// TestGitHubToken tests that a profile's own token wins over one in the environment
+func TestGitHubToken(t *testing.T) {
+	keyring.MockInit()
+	t.Setenv("GITHUB_TOKEN", "ambient-token")
+	t.Setenv("GH_TOKEN", "")
+
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	cm := &ConfigManager{
+		ConfigPath: filepath.Join(tmpDir, ".git-profiles-test.json"),
+		Profiles:   map[string]Profile{"work": {}, "oss": {}},
+	}
+	assert.NoError(t, cm.setSecret("work", githubTokenSecret, "work-token"))
+
+	token, err := cm.githubToken("work", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "work-token", token)
+	token, err = cm.githubToken("work", "flag-token")
+	assert.NoError(t, err)
+	assert.Equal(t, "flag-token", token)
+	token, err = cm.githubToken("oss", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "ambient-token", token)
+
+	t.Setenv("GITHUB_TOKEN", "")
+	_, err = cm.githubToken("oss", "")
+	assert.ErrorContains(t, err, "no GitHub token")
+}
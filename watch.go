@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// watchedKeys are the identity keys watch compares against the assigned profile
+var watchedKeys = []string{"user.name", "user.email", "user.signingkey", "author.name", "author.email", "committer.name", "committer.email"}
+
+// identityDrift describes a registered repository whose identity no longer matches its profile
+type identityDrift struct {
+	Path    string
+	Profile string
+	Changes []string
+}
+
+// String summarizes the drift, e.g. "~/work/api (work): user.email is <john@personal.dev>, not <john.doe@company.com>"
+func (d identityDrift) String() string {
+	return fmt.Sprintf("%s (%s): %s", displayPath(d.Path), d.Profile, strings.Join(d.Changes, "; "))
+}
+
+// checkDrift compares the effective identity of the repository at path with what its registered profile writes
+func (cm *ConfigManager) checkDrift(path string) (identityDrift, bool, error) {
+	drift := identityDrift{Path: path, Profile: cm.Repos[path].Profile}
+	saved, exists := cm.Profiles[drift.Profile]
+	if !exists {
+		return drift, false, nil
+	}
+	profile, err := resolveProfile(saved)
+	if err != nil {
+		return drift, false, err
+	}
+
+	entries, err := gitConfigEntries(path, nil, `^(user|author|committer)\.`)
+	if err != nil {
+		return drift, false, err
+	}
+	effective := make(map[string]string)
+	for _, entry := range entries {
+		effective[entry.Key] = entry.Value
+	}
+
+	for _, want := range profileConfig(drift.Profile, profile) {
+		got, set := effective[want.Key]
+		switch {
+		case !slices.Contains(watchedKeys, want.Key) || got == want.Value:
+		case !set:
+			drift.Changes = append(drift.Changes, fmt.Sprintf(tr("%s is unset, not <%s>"), want.Key, want.Value))
+		default:
+			drift.Changes = append(drift.Changes, fmt.Sprintf(tr("%s is <%s>, not <%s>"), want.Key, got, want.Value))
+		}
+	}
+	return drift, len(drift.Changes) > 0, nil
+}
+
+// watchOnce checks every registered repository that still exists and returns the drifts found, or with restore set
+// reapplies the profile of drifted repositories and returns those it restored
+func (cm *ConfigManager) watchOnce(restore bool) []identityDrift {
+	var drifts []identityDrift
+	for _, path := range cm.registeredPaths("") {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		drift, drifted, err := cm.checkDrift(path)
+		if err != nil {
+			warnf("%s: %v", displayPath(path), err)
+			continue
+		}
+		if !drifted {
+			continue
+		}
+
+		if restore {
+			// Archived profiles aren't applied anymore, not even to put a repository back
+			if err := cm.checkNotArchived(drift.Profile); err != nil {
+				warnf("%s: %v", displayPath(path), err)
+				continue
+			}
+			if err := cm.applyWithHooks(path, drift.Profile, cm.Profiles[drift.Profile]); err != nil {
+				warnf("%s: %v", displayPath(path), err)
+				continue
+			}
+			cm.registerRepo(path, drift.Profile)
+			cm.recordHistory("apply", drift.Profile, path, scopeLocal)
+		}
+		drifts = append(drifts, drift)
+	}
+	return drifts
+}
+
+// notifyDesktop shows a desktop notification where the platform has a way to, and does nothing elsewhere
+func notifyDesktop(title string, message string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf("display notification %q with title %q", message, title))
+	default:
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return
+		}
+		cmd = exec.Command("notify-send", title, message)
+	}
+	if err := cmd.Run(); err != nil {
+		debugf("watch: desktop notification failed: %v", err)
+	}
+}
+
+// serviceFile returns where the user service running watch lives on goos and its content, running executable with args
+func serviceFile(goos string, executable string, args []string) (string, string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", err
+	}
+
+	command := append([]string{executable}, args...)
+	switch goos {
+	case "linux":
+		configHome := os.Getenv("XDG_CONFIG_HOME")
+		if configHome == "" {
+			configHome = filepath.Join(homeDir, ".config")
+		}
+		unit := fmt.Sprintf(`[Unit]
+Description=Keep Git identities of registered repositories in line with their profiles
+
+[Service]
+ExecStart=%s
+Restart=on-failure
+
+[Install]
+WantedBy=default.target
+`, shellCommand(command...))
+		return filepath.Join(configHome, "systemd", "user", "git-profile-watch.service"), unit, nil
+	case "darwin":
+		var arguments strings.Builder
+		for _, arg := range command {
+			fmt.Fprintf(&arguments, "    <string>%s</string>\n", xmlEscape(arg))
+		}
+		plist := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+  <key>Label</key>
+  <string>com.github.lvluu.git-profile.watch</string>
+  <key>ProgramArguments</key>
+  <array>
+%s  </array>
+  <key>RunAtLoad</key>
+  <true/>
+  <key>KeepAlive</key>
+  <true/>
+</dict>
+</plist>
+`, arguments.String())
+		return filepath.Join(homeDir, "Library", "LaunchAgents", "com.github.lvluu.git-profile.watch.plist"), plist, nil
+	}
+	return "", "", errorf("installing a service is not supported on %s; run 'git profile watch' from your session startup instead", goos)
+}
+
+// xmlEscape escapes text for an XML element
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// newWatchCmd builds the watch command
+func newWatchCmd(configManager *ConfigManager) *cobra.Command {
+	var restore, once, installService bool
+	var interval time.Duration
+
+	var watchCmd = &cobra.Command{
+		Use:   "watch",
+		Short: "Watch registered repositories and notify or restore when their identity drifts from the assigned profile",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if interval <= 0 {
+				fmt.Fprintln(stdout, tr("Watch failed: --interval must be positive"))
+				os.Exit(1)
+			}
+			if installService {
+				executable, err := os.Executable()
+				if err != nil {
+					fmt.Fprintln(stdout, tr("Watch failed:"), err)
+					os.Exit(1)
+				}
+				serviceArgs := []string{"watch", "--interval", interval.String()}
+				if restore {
+					serviceArgs = append(serviceArgs, "--restore")
+				}
+				path, content, err := serviceFile(runtime.GOOS, executable, serviceArgs)
+				if err == nil {
+					if err = os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+						err = os.WriteFile(path, []byte(content), 0644)
+					}
+				}
+				if err != nil {
+					fmt.Fprintln(stdout, tr("Watch failed:"), err)
+					os.Exit(1)
+				}
+				fmt.Fprintf(notices, tr("✅ Service written to %s\n"), displayPath(path))
+				if strings.HasSuffix(path, ".plist") {
+					fmt.Fprintf(notices, tr("Start it with 'launchctl load %s'.\n"), displayPath(path))
+				} else {
+					fmt.Fprintln(notices, tr("Start it with 'systemctl --user enable --now git-profile-watch'."))
+				}
+				return
+			}
+
+			// The config is reloaded as serve does, so newly applied repositories are watched too
+			server := newProfileServer(configManager)
+			reported := make(map[string]string)
+			check := func() []identityDrift {
+				if err := server.reload(); err != nil {
+					warnf("%v", err)
+				}
+				drifts := server.cm.watchOnce(restore)
+				current := make(map[string]string)
+				for _, drift := range drifts {
+					current[drift.Path] = drift.String()
+					if reported[drift.Path] != drift.String() {
+						if restore {
+							fmt.Fprintf(stdout, tr("🔧 Restored %s\n"), drift)
+						} else {
+							fmt.Fprintf(stdout, "⚠️  %s\n", drift)
+							notifyDesktop(tr("Git identity changed"), drift.String())
+						}
+					}
+				}
+				if !restore {
+					// Drifts are reported once until they change or are fixed
+					reported = current
+				}
+				return drifts
+			}
+
+			if once {
+				if drifts := check(); len(drifts) > 0 && !restore {
+					os.Exit(1)
+				}
+				return
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			fmt.Fprintf(notices, tr("🦑 Watching %d registered repositories every %s (Ctrl+C to stop)\n"), len(configManager.registeredPaths("")), interval)
+			for {
+				check()
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		},
+	}
+
+	watchCmd.Flags().BoolVar(&restore, "restore", false, "Reapply the assigned profile instead of only notifying")
+	watchCmd.Flags().DurationVar(&interval, "interval", 10*time.Second, "How often to check the repositories")
+	watchCmd.Flags().BoolVar(&once, "once", false, "Check once and exit, non-zero when a repository drifted and wasn't restored")
+	watchCmd.Flags().BoolVar(&installService, "install-service", false, "Install watch, with the given --interval and --restore, as a systemd (Linux) or launchd (macOS) user service")
+	return watchCmd
+}
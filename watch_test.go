@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWatchDrift tests detecting and restoring registered repositories whose identity drifted from their profile
+func TestWatchDrift(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "git-profile-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(tmpDir, ".gitconfig"))
+
+	cm := &ConfigManager{ConfigPath: filepath.Join(tmpDir, ".git-profiles-test.json"), Profiles: map[string]Profile{
+		"corp": {Name: "John Doe", Email: "john.doe@company.com"},
+	}}
+	repoDir := initTestRepo(t)
+	assert.NoError(t, applyProfile(repoDir, "corp", cm.Profiles["corp"]))
+	cm.registerRepo(repoDir, "corp")
+	repoPath := repoTopLevel(repoDir)
+
+	_, drifted, err := cm.checkDrift(repoPath)
+	assert.NoError(t, err)
+	assert.False(t, drifted)
+	assert.Empty(t, cm.watchOnce(false))
+
+	// Another tool overwrites the email
+	_, err = runGit(repoDir, "config", "user.email", "john@personal.dev")
+	assert.NoError(t, err)
+	drift, drifted, err := cm.checkDrift(repoPath)
+	assert.NoError(t, err)
+	assert.True(t, drifted)
+	assert.Equal(t, []string{"user.email is <john@personal.dev>, not <john.doe@company.com>"}, drift.Changes)
+
+	assert.Len(t, cm.watchOnce(false), 1)
+	email, _ := gitConfigGet(repoDir, "user.email")
+	assert.Equal(t, "john@personal.dev", email)
+
+	assert.Len(t, cm.watchOnce(true), 1)
+	email, _ = gitConfigGet(repoDir, "user.email")
+	assert.Equal(t, "john.doe@company.com", email)
+	assert.Empty(t, cm.watchOnce(false))
+
+	// Archived profiles are reported but not restored
+	_, err = runGit(repoDir, "config", "user.email", "john@personal.dev")
+	assert.NoError(t, err)
+	corp := cm.Profiles["corp"]
+	corp.Archived = true
+	cm.Profiles["corp"] = corp
+	assert.Empty(t, cm.watchOnce(true))
+	assert.Len(t, cm.watchOnce(false), 1)
+	email, _ = gitConfigGet(repoDir, "user.email")
+	assert.Equal(t, "john@personal.dev", email)
+}
+
+// TestServiceFile tests rendering the user service running watch
+func TestServiceFile(t *testing.T) {
+	t.Setenv("HOME", "/home/john")
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	path, content, err := serviceFile("linux", "/usr/local/bin/git-profile", []string{"watch", "--restore"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/home/john/.config/systemd/user/git-profile-watch.service", path)
+	assert.Contains(t, content, "ExecStart=/usr/local/bin/git-profile watch --restore\n")
+
+	path, content, err = serviceFile("darwin", "/opt/Git Tools/git-profile", []string{"watch"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/home/john/Library/LaunchAgents/com.github.lvluu.git-profile.watch.plist", path)
+	assert.Contains(t, content, "<string>/opt/Git Tools/git-profile</string>\n    <string>watch</string>\n")
+
+	_, _, err = serviceFile("windows", "git-profile.exe", nil)
+	assert.ErrorContains(t, err, "not supported on windows")
+}
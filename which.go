@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+// ruleVerdict is how one policy rule fared against a repository
+type ruleVerdict struct {
+	Rule     Rule
+	Matches  bool
+	Selected bool
+	Missing  bool
+}
+
+// profileSelection explains which profile a repository gets and why
+type profileSelection struct {
+	Repo    string
+	Origin  string
+	Remotes []string
+	Rules   []ruleVerdict
+	Profile string
+	Reason  string
+
+	// Via is what selected the profile, a rule or a remote pattern, and Match the rule pattern or remote pattern
+	Via   string
+	Match string
+}
+
+// explainProfile follows the precedence of recommendedProfile: the first matching rule, then the origin remote's patterns
+func (cm *ConfigManager) explainProfile(dir string) profileSelection {
+	selection := profileSelection{Repo: repoTopLevel(dir), Remotes: repoRemoteURLs(dir)}
+	selection.Origin, _ = gitConfigGet(dir, "remote.origin.url")
+
+	decided := false
+	for _, rule := range cm.Rules {
+		verdict := ruleVerdict{Rule: rule, Matches: rule.matches(selection.Repo, selection.Remotes)}
+		if verdict.Matches && !decided {
+			decided = true
+			if _, exists := cm.Profiles[rule.Profile]; exists {
+				verdict.Selected = true
+				selection.Profile, selection.Reason = rule.Profile, fmt.Sprintf(tr("policy rule %s"), rule.Target())
+				selection.Via = "rule"
+				_, selection.Match = rule.Pattern()
+			} else {
+				verdict.Missing = true
+			}
+		}
+		selection.Rules = append(selection.Rules, verdict)
+	}
+
+	if selection.Profile == "" {
+		if name, pattern, found := cm.matchProfilePattern(selection.Origin); found {
+			selection.Profile, selection.Reason = name, fmt.Sprintf(tr("origin matches remote pattern %s"), pattern)
+			selection.Via, selection.Match = "pattern", pattern
+		}
+	}
+	return selection
+}
+
+// newWhichCmd builds the which command
+func newWhichCmd(configManager *ConfigManager) *cobra.Command {
+	var porcelain bool
+	var whichCmd = &cobra.Command{
+		Use:   "which [path]",
+		Short: "Explain which profile the rules and remote patterns select for a repository",
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			dir := "."
+			if len(args) > 0 {
+				dir = expandHome(args[0])
+			}
+			if _, err := os.Stat(dir); err != nil {
+				fmt.Fprintln(stdout, tr("Which failed:"), err)
+				os.Exit(1)
+			}
+
+			selection := configManager.explainProfile(dir)
+			if porcelain {
+				writeSelectionPorcelain(stdout, selection)
+				return
+			}
+			if selection.Repo == "" {
+				absolute, _ := filepath.Abs(dir)
+				fmt.Fprintf(stdout, tr("📂 %s is not inside a Git repository\n"), absolute)
+			} else {
+				fmt.Fprintf(stdout, tr("📂 Repository: %s\n"), selection.Repo)
+			}
+			for _, url := range selection.Remotes {
+				fmt.Fprintf(stdout, tr("🔗 Remote: %s\n"), url)
+			}
+
+			if len(selection.Rules) > 0 {
+				fmt.Fprintln(stdout, tr("📏 Rules (the first match wins):"))
+				for _, verdict := range selection.Rules {
+					switch {
+					case verdict.Selected:
+						fmt.Fprintf(stdout, "  ✅ %s → %s\n", verdict.Rule.Target(), verdict.Rule.Profile)
+					case verdict.Missing:
+						fmt.Fprintf(stdout, tr("  ⚠️  %s → %s (matches, but the profile doesn't exist)\n"), verdict.Rule.Target(), verdict.Rule.Profile)
+					case verdict.Matches:
+						fmt.Fprintf(stdout, tr("  •  %s → %s (matches, but an earlier rule won)\n"), verdict.Rule.Target(), verdict.Rule.Profile)
+					default:
+						fmt.Fprintf(stdout, tr("  •  %s → %s (no match)\n"), verdict.Rule.Target(), verdict.Rule.Profile)
+					}
+				}
+			}
+
+			if selection.Profile == "" {
+				fmt.Fprintln(stdout, tr("🎯 No profile selected: no rule or remote pattern matches"))
+				return
+			}
+			fmt.Fprintf(stdout, tr("🎯 Profile: %s (%s)\n"), selection.Profile, selection.Reason)
+		},
+	}
+
+	whichCmd.Flags().BoolVar(&porcelain, "porcelain", false, "Print the stable, tab-separated format for scripts")
+	return whichCmd
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExplainProfile tests explaining the profile selected for a repository
+func TestExplainProfile(t *testing.T) {
+	repoDir := initTestRepo(t)
+	_, err := runGit(repoDir, "remote", "add", "origin", "git@github.com:acme/api.git")
+	assert.NoError(t, err)
+
+	cm := &ConfigManager{
+		Profiles: map[string]Profile{
+			"work": {Name: "John Doe", Email: "john.doe@company.com", Remotes: []string{"github.com/acme/*"}},
+			"oss":  {Name: "John Doe", Email: "john@oss.dev", Remotes: []string{"github.com/*"}},
+		},
+	}
+
+	selection := cm.explainProfile(repoDir)
+	assert.Equal(t, "work", selection.Profile)
+	assert.Equal(t, "origin matches remote pattern github.com/acme/*", selection.Reason)
+	assert.Equal(t, []string{"git@github.com:acme/api.git"}, selection.Remotes)
+
+	// The first matching rule wins; one naming a missing profile falls through to the remote patterns
+	cm.Rules = []Rule{
+		{Remote: "gitlab.com/*", Profile: "work"},
+		{Remote: "github.com/acme/*", Profile: "client"},
+	}
+	selection = cm.explainProfile(repoDir)
+	assert.Equal(t, []ruleVerdict{
+		{Rule: cm.Rules[0]},
+		{Rule: cm.Rules[1], Matches: true, Missing: true},
+	}, selection.Rules)
+	assert.Equal(t, "work", selection.Profile)
+
+	cm.Rules = append(cm.Rules, Rule{Remote: "github.com/*", Profile: "oss"})
+	cm.Rules[1].Profile = "oss"
+	selection = cm.explainProfile(repoDir)
+	assert.True(t, selection.Rules[1].Selected)
+	assert.True(t, selection.Rules[2].Matches)
+	assert.False(t, selection.Rules[2].Selected)
+	assert.Equal(t, "oss", selection.Profile)
+	assert.Equal(t, "policy rule remote github.com/acme/*", selection.Reason)
+}
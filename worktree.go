@@ -0,0 +1,17 @@
+package main
+
+// worktreeConfigArgs select the worktree-specific config file, config.worktree, which wins over the shared local config
+var worktreeConfigArgs = []string{"--worktree"}
+
+// checkWorktreeConfig verifies that the repository at dir has per-worktree config, without which Git writes --worktree
+// settings to the shared local config or refuses them
+func checkWorktreeConfig(dir string) error {
+	if repoTopLevel(dir) == "" {
+		return errorf("%s is not inside a Git repository", displayPath(absolutePath("", dir)))
+	}
+	enabled, _ := runGit(dir, "config", "--type=bool", "--get", "extensions.worktreeConfig")
+	if enabled != "true" {
+		return errorf("extensions.worktreeConfig is not enabled in this repository; enable it with 'git config extensions.worktreeConfig true' (see git help worktree)")
+	}
+	return nil
+}
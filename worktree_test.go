@@ -0,0 +1,45 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestApplyWorktree tests giving one worktree of a clone its own profile
+func TestApplyWorktree(t *testing.T) {
+	repoDir := initTestRepo(t)
+	commitAs(t, repoDir, "John Doe", "john.doe@company.com")
+	forkDir := filepath.Join(repoDir, ".worktrees", "fork")
+	_, err := runGit(repoDir, "worktree", "add", "--quiet", "-b", "fork", forkDir)
+	assert.NoError(t, err)
+
+	assert.ErrorContains(t, checkWorktreeConfig(forkDir), "extensions.worktreeConfig is not enabled")
+	_, err = runGit(repoDir, "config", "extensions.worktreeConfig", "true")
+	assert.NoError(t, err)
+	assert.NoError(t, checkWorktreeConfig(forkDir))
+
+	work := Profile{Name: "John Doe", Email: "john.doe@company.com"}
+	oss := Profile{Name: "John Doe", Email: "john@oss.dev"}
+	assert.NoError(t, applyProfile(repoDir, "work", work))
+	assert.NoError(t, applyProfile(forkDir, "oss", oss, worktreeConfigArgs...))
+
+	email, _ := gitConfigGet(forkDir, "user.email")
+	assert.Equal(t, "john@oss.dev", email)
+	assigned, _ := gitConfigGet(forkDir, assignedProfileKey)
+	assert.Equal(t, "oss", assigned)
+	email, _ = gitConfigGet(repoDir, "user.email")
+	assert.Equal(t, "john.doe@company.com", email)
+
+	keys, err := appliedKeys(forkDir, worktreeConfigArgs...)
+	assert.NoError(t, err)
+	assert.Contains(t, keys, "user.email")
+
+	// Unapplying the worktree falls back to the shared local identity
+	assert.NoError(t, unapplyProfile(forkDir, worktreeConfigArgs...))
+	email, _ = gitConfigGet(forkDir, "user.email")
+	assert.Equal(t, "john.doe@company.com", email)
+	assigned, _ = gitConfigGet(forkDir, assignedProfileKey)
+	assert.Equal(t, "work", assigned)
+}